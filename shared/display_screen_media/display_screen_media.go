@@ -5,23 +5,47 @@ package main
  * libVLC screen module must be installed.
  * See https://github.com/adrg/libvlc-go/wiki for installation instructions.
  * See https://wiki.videolan.org/Documentation:Modules/screen.
+ *
+ * Usage:
+ *   display_screen_media [-list-monitors] [-monitor <index|name|all>]
+ *
+ * Monitors are listed using `xrandr --listmonitors`.
  */
 import (
+	"flag"
+	"fmt"
 	"log"
 
-	vlc "github.com/adrg/libvlc-go/v2"
+	"github.com/adrg/libvlc-go-examples/shared/screencap"
+	"github.com/adrg/libvlc-go-examples/shared/vlcompat"
 )
 
 func main() {
+	listMonitorsFlag := flag.Bool("list-monitors", false, "list monitors and exit")
+	monitorFlag := flag.String("monitor", "", "capture the monitor with the specified index or name, or all monitors (all)")
+	flag.Parse()
+
+	// List monitors.
+	if *listMonitorsFlag {
+		monitors, err := screencap.ListMonitors()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, m := range monitors {
+			fmt.Println(m)
+		}
+		return
+	}
+
 	// Initialize libVLC. Additional command line arguments can be passed in
 	// to libVLC by specifying them in the Init function.
-	if err := vlc.Init("--quiet"); err != nil {
+	if err := vlcompat.Init("--quiet"); err != nil {
 		log.Fatal(err)
 	}
-	defer vlc.Release()
+	defer vlcompat.Release()
 
 	// Create a new player.
-	player, err := vlc.NewPlayer()
+	player, err := vlcompat.NewPlayer()
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -31,7 +55,7 @@ func main() {
 	}()
 
 	// Create new media instance from screen.
-	screenOpts := &vlc.MediaScreenOptions{
+	screenOpts := &vlcompat.ScreenOptions{
 		// Captured area left edge. Default: 0.
 		X: 100,
 
@@ -51,7 +75,25 @@ func main() {
 		FollowMouse: true,
 	}
 
-	media, err := vlc.NewMediaFromScreen(screenOpts)
+	// Capture the selected monitor, if specified.
+	if *monitorFlag != "" {
+		monitors, err := screencap.ListMonitors()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		m, err := screencap.FindMonitor(monitors, *monitorFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Capturing monitor %s\n", m)
+
+		screenOpts.X, screenOpts.Y = m.X, m.Y
+		screenOpts.Width, screenOpts.Height = m.Width, m.Height
+		screenOpts.FollowMouse = false
+	}
+
+	media, err := vlcompat.NewMediaFromScreen(screenOpts)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -70,11 +112,11 @@ func main() {
 
 	// Register the media end reached event with the event manager.
 	quit := make(chan struct{})
-	eventCallback := func(event vlc.Event, userData interface{}) {
+	eventCallback := func(event vlcompat.Event, userData interface{}) {
 		close(quit)
 	}
 
-	eventID, err := manager.Attach(vlc.MediaPlayerEndReached, eventCallback, nil)
+	eventID, err := manager.Attach(vlcompat.MediaPlayerEndReached, eventCallback, nil)
 	if err != nil {
 		log.Fatal(err)
 	}
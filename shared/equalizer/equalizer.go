@@ -2,90 +2,31 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"log"
+	"os"
 	"time"
 
-	vlc "github.com/adrg/libvlc-go/v2"
+	"github.com/adrg/libvlc-go-examples/shared/vlcompat"
 )
 
 func main() {
 	// Initialize libVLC. Additional command line arguments can be passed in
 	// to libVLC by specifying them in the Init function.
-	if err := vlc.Init("--no-video", "--quiet"); err != nil {
+	if err := vlcompat.Init("--no-video", "--quiet"); err != nil {
 		log.Fatal(err)
 	}
-	defer vlc.Release()
+	defer vlcompat.Release()
 
-	// Get equalizer preset names.
-	var (
-		eqPresetNames = vlc.EqualizerPresetNames()
-		presetIdx     uint
-	)
-
-	fmt.Println("Equalizer presets: ")
-	for i, eqPresetName := range eqPresetNames {
-		if eqPresetName == "Full bass" {
-			presetIdx = uint(i)
-		}
-
-		fmt.Printf("#%d: %s\n", i, eqPresetName)
-	}
-	fmt.Println("")
-
-	// NOTE: in order to get a single equalizer preset, use
-	// vlc.EqualizerPresetName. Use EqualizerPresetCount to
-	// obtain the number of available equalizer presets.
-
-	// Get equalizer band frequencies.
-	bandFreqs := vlc.EqualizerBandFrequencies()
-
-	fmt.Println("Equalizer band frequencies: ")
-	for i, bandFreq := range bandFreqs {
-		fmt.Printf("#%d: %.2f\n", i, bandFreq)
-	}
-	fmt.Println("")
-
-	// NOTE: in order to get a single band frequency, use
-	// vlc.EqualizerBandFrequency. Use EqualizerBandCount
-	// to obtain the number of available equalizer bands.
-
-	// Create a new equalizer from a preset.
-	// If you want to start from scratch, use vlc.NewEqualizer.
-	equalizer, err := vlc.NewEqualizerFromPreset(presetIdx)
+	// Create a new equalizer based on the "Full bass" preset.
+	equalizer, err := newEqualizer(os.Stdout, "Full bass")
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer equalizer.Release()
 
-	// Get and set preamplification value.
-	preAmp, err := equalizer.PreampValue()
-	if err != nil {
-		log.Fatal(err)
-	}
-	fmt.Printf("Preamp value: %.2f\n", preAmp)
-
-	if err := equalizer.SetPreampValue(preAmp - 3); err != nil {
-		log.Fatal(err)
-	}
-
-	// Get and set individidual amplification values for the
-	// equalizer frequency bands.
-	bandCount := vlc.EqualizerBandCount()
-	for i := uint(0); i < bandCount; i++ {
-		bandFreq, err := equalizer.AmpValueAtIndex(i)
-		if err != nil {
-			log.Fatal(err)
-		}
-		fmt.Printf("#%d (%.2f): %.2f\n", i, bandFreqs[i], bandFreq)
-
-		if err := equalizer.SetAmpValueAtIndex(bandFreq+float64(i), i); err != nil {
-			log.Fatal(err)
-		}
-	}
-	fmt.Println("")
-
 	// Create a new player.
-	player, err := vlc.NewPlayer()
+	player, err := vlcompat.NewPlayer()
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -116,11 +57,11 @@ func main() {
 
 	// Register the media end reached event with the event manager.
 	quit := make(chan struct{})
-	eventCallback := func(event vlc.Event, userData interface{}) {
+	eventCallback := func(event vlcompat.Event, userData interface{}) {
 		close(quit)
 	}
 
-	eventID, err := manager.Attach(vlc.MediaPlayerEndReached, eventCallback, nil)
+	eventID, err := manager.Attach(vlcompat.MediaPlayerEndReached, eventCallback, nil)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -140,3 +81,86 @@ func main() {
 
 	<-quit
 }
+
+// newEqualizer prints the available equalizer presets and band frequencies
+// and creates a new equalizer from the preset with the specified name. The
+// preamplification value of the equalizer is decreased by 3dB and the
+// amplification value of each band at index i is increased by i dB.
+func newEqualizer(w io.Writer, presetName string) (vlcompat.Equalizer, error) {
+	// Get equalizer preset names.
+	var (
+		eqPresetNames = vlcompat.EqualizerPresetNames()
+		presetIdx     = -1
+	)
+
+	fmt.Fprintln(w, "Equalizer presets: ")
+	for i, eqPresetName := range eqPresetNames {
+		if eqPresetName == presetName {
+			presetIdx = i
+		}
+
+		fmt.Fprintf(w, "#%d: %s\n", i, eqPresetName)
+	}
+	fmt.Fprintln(w)
+
+	if presetIdx < 0 {
+		return nil, fmt.Errorf("equalizer preset %q not found", presetName)
+	}
+
+	// NOTE: in order to get a single equalizer preset, use
+	// vlcompat.EqualizerPresetName. Use EqualizerPresetCount to
+	// obtain the number of available equalizer presets.
+
+	// Get equalizer band frequencies.
+	bandFreqs := vlcompat.EqualizerBandFrequencies()
+
+	fmt.Fprintln(w, "Equalizer band frequencies: ")
+	for i, bandFreq := range bandFreqs {
+		fmt.Fprintf(w, "#%d: %.2f\n", i, bandFreq)
+	}
+	fmt.Fprintln(w)
+
+	// NOTE: in order to get a single band frequency, use
+	// vlcompat.EqualizerBandFrequency. Use EqualizerBandCount
+	// to obtain the number of available equalizer bands.
+
+	// Create a new equalizer from a preset.
+	// If you want to start from scratch, use vlcompat.NewEqualizer.
+	equalizer, err := vlcompat.NewEqualizerFromPreset(uint(presetIdx))
+	if err != nil {
+		return nil, err
+	}
+
+	// Get and set preamplification value.
+	preAmp, err := equalizer.PreampValue()
+	if err != nil {
+		equalizer.Release()
+		return nil, err
+	}
+	fmt.Fprintf(w, "Preamp value: %.2f\n", preAmp)
+
+	if err := equalizer.SetPreampValue(preAmp - 3); err != nil {
+		equalizer.Release()
+		return nil, err
+	}
+
+	// Get and set individidual amplification values for the
+	// equalizer frequency bands.
+	bandCount := vlcompat.EqualizerBandCount()
+	for i := uint(0); i < bandCount; i++ {
+		bandFreq, err := equalizer.AmpValueAtIndex(i)
+		if err != nil {
+			equalizer.Release()
+			return nil, err
+		}
+		fmt.Fprintf(w, "#%d (%.2f): %.2f\n", i, bandFreqs[i], bandFreq)
+
+		if err := equalizer.SetAmpValueAtIndex(bandFreq+float64(i), i); err != nil {
+			equalizer.Release()
+			return nil, err
+		}
+	}
+	fmt.Fprintln(w)
+
+	return equalizer, nil
+}
@@ -0,0 +1,114 @@
+//go:build integration
+
+package main
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/adrg/libvlc-go-examples/shared/fixtures"
+	"github.com/adrg/libvlc-go-examples/shared/vlcompat"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Main(m))
+}
+
+func TestNewEqualizer(t *testing.T) {
+	const presetName = "Full bass"
+
+	var output bytes.Buffer
+	equalizer, err := newEqualizer(&output, presetName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer equalizer.Release()
+
+	if !strings.Contains(output.String(), ": "+presetName+"\n") {
+		t.Errorf("preset %q not listed:\n%s", presetName, output.String())
+	}
+
+	// Compare the adjusted values with the values of the preset.
+	var presetIdx uint
+	for i, name := range vlcompat.EqualizerPresetNames() {
+		if name == presetName {
+			presetIdx = uint(i)
+		}
+	}
+	preset, err := vlcompat.NewEqualizerFromPreset(presetIdx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer preset.Release()
+
+	presetPreamp, _ := preset.PreampValue()
+	preamp, _ := equalizer.PreampValue()
+	if !almostEqual(preamp, presetPreamp-3) {
+		t.Errorf("got preamp value %.2f, want %.2f", preamp, presetPreamp-3)
+	}
+	for i := uint(0); i < vlcompat.EqualizerBandCount(); i++ {
+		presetAmp, _ := preset.AmpValueAtIndex(i)
+		amp, _ := equalizer.AmpValueAtIndex(i)
+		if want := presetAmp + float64(i); !almostEqual(amp, want) {
+			t.Errorf("band %d: got amplification value %.2f, want %.2f", i, amp, want)
+		}
+	}
+
+	// Play media using the equalizer.
+	player, err := vlcompat.NewPlayer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		player.Stop()
+		player.Release()
+	}()
+
+	media, err := player.LoadMediaFromPath(fixtures.SineWAV(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer media.Release()
+
+	if err := player.SetEqualizer(equalizer); err != nil {
+		t.Fatal(err)
+	}
+
+	manager, err := player.EventManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	eventID, err := manager.Attach(vlcompat.MediaPlayerEndReached, func(vlcompat.Event, interface{}) {
+		close(done)
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Detach(eventID)
+
+	err = fixtures.RunWithTimeout(t, fixtures.PlaybackTimeout, func() error {
+		if err := player.Play(); err != nil {
+			return err
+		}
+		<-done
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewEqualizerUnknownPreset(t *testing.T) {
+	if _, err := newEqualizer(&bytes.Buffer{}, "Unknown"); err == nil {
+		t.Error("expected error for unknown preset")
+	}
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 0.01
+}
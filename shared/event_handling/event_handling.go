@@ -3,19 +3,19 @@ package main
 import (
 	"log"
 
-	vlc "github.com/adrg/libvlc-go/v3"
+	"github.com/adrg/libvlc-go-examples/shared/vlcompat"
 )
 
 func main() {
 	// Initialize libVLC. Additional command line arguments can be passed in
 	// to libVLC by specifying them in the Init function.
-	if err := vlc.Init("--no-video", "--quiet"); err != nil {
+	if err := vlcompat.Init("--no-video", "--quiet"); err != nil {
 		log.Fatal(err)
 	}
-	defer vlc.Release()
+	defer vlcompat.Release()
 
 	// Create a new player.
-	player, err := vlc.NewPlayer()
+	player, err := vlcompat.NewPlayer()
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -34,47 +34,56 @@ func main() {
 	}
 	defer media.Release()
 
+	if err := playMedia(player, log.Default()); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// playMedia plays the media of the specified player and logs the media
+// statistics each time the playback time changes. Returns when playback
+// ends.
+func playMedia(player vlcompat.Player, logger *log.Logger) error {
 	// Retrieve player event manager.
 	manager, err := player.EventManager()
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	// Create event handler.
 	quit := make(chan struct{})
-	eventCallback := func(event vlc.Event, userData interface{}) {
+	eventCallback := func(event vlcompat.Event, userData interface{}) {
 		switch event {
-		case vlc.MediaPlayerEndReached:
-			log.Println("Player end reached")
+		case vlcompat.MediaPlayerEndReached:
+			logger.Println("Player end reached")
 			close(quit)
-		case vlc.MediaPlayerTimeChanged:
+		case vlcompat.MediaPlayerTimeChanged:
 			media, err := player.Media()
 			if err != nil {
-				log.Println(err)
+				logger.Println(err)
 				break
 			}
 
 			stats, err := media.Stats()
 			if err != nil {
-				log.Println(err)
+				logger.Println(err)
 				break
 			}
 
-			log.Printf("%+v\n", stats)
+			logger.Printf("%+v\n", stats)
 		}
 	}
 
 	// Register events with the event manager.
-	events := []vlc.Event{
-		vlc.MediaPlayerTimeChanged,
-		vlc.MediaPlayerEndReached,
+	events := []vlcompat.Event{
+		vlcompat.MediaPlayerTimeChanged,
+		vlcompat.MediaPlayerEndReached,
 	}
 
-	var eventIDs []vlc.EventID
+	var eventIDs []vlcompat.EventID
 	for _, event := range events {
 		eventID, err := manager.Attach(event, eventCallback, nil)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		eventIDs = append(eventIDs, eventID)
@@ -85,8 +94,9 @@ func main() {
 
 	// Start playing the media.
 	if err = player.Play(); err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	<-quit
+	return nil
 }
@@ -0,0 +1,68 @@
+//go:build integration
+
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/adrg/libvlc-go-examples/shared/fixtures"
+	"github.com/adrg/libvlc-go-examples/shared/vlcompat"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Main(m))
+}
+
+func TestPlayMedia(t *testing.T) {
+	for _, fixture := range []struct {
+		name string
+		path func(testing.TB) string
+	}{
+		{"audio", fixtures.SineWAV},
+		{"video", fixtures.ColorBars},
+	} {
+		t.Run(fixture.name, func(t *testing.T) {
+			player, err := vlcompat.NewPlayer()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				player.Stop()
+				player.Release()
+			}()
+
+			media, err := player.LoadMediaFromPath(fixture.path(t))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer media.Release()
+
+			var output fixtures.Log
+			err = fixtures.RunWithTimeout(t, fixtures.PlaybackTimeout, func() error {
+				return playMedia(player, output.Logger())
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The media statistics are logged on each time change event.
+			var endReached, stats int
+			for _, line := range strings.Split(output.String(), "\n") {
+				switch {
+				case line == "Player end reached":
+					endReached++
+				case strings.HasPrefix(line, "&{") && strings.Contains(line, "ReadBytes:"):
+					stats++
+				}
+			}
+			if endReached != 1 {
+				t.Errorf("got %d end reached events, want 1", endReached)
+			}
+			if stats == 0 {
+				t.Errorf("no media statistics logged:\n%s", output.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+// Package fixtures generates the media files used by the integration tests
+// of the examples of all modules. The fixtures are generated at test time,
+// using the stream output (sout) transcoding of libVLC, from raw audio and
+// video data synthesized by the package. libVLC is accessed through the
+// vlcompat compatibility layer, so the package can be used by the tests of
+// both the shared and the v3 examples.
+package fixtures
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adrg/libvlc-go-examples/shared/vlcompat"
+)
+
+// VLCArgs contains the arguments libVLC is initialized with by Main.
+// Video and audio are rendered using dummy outputs, so the tests can be run
+// on headless machines.
+var VLCArgs = []string{"--quiet", "--vout=dummy", "--aout=dummy"}
+
+// Properties of the generated fixtures.
+const (
+	Duration      = 3 * time.Second
+	SampleRate    = 44100
+	AudioChannels = 2
+	VideoWidth    = 320
+	VideoHeight   = 240
+	FrameRate     = 25
+
+	// Frequencies of the tones contained by the generated audio tracks.
+	SineFrequency    = 440
+	AltSineFrequency = 880
+
+	// Number of cues of the subtitle track of the multi-track fixture.
+	SubtitleCues = 3
+)
+
+// Maximum duration of the generation of a single fixture.
+const generateTimeout = 60 * time.Second
+
+var (
+	dir string
+
+	sineWAV    = &fixture{name: "sine.wav", generate: generateSineWAV}
+	altSineWAV = &fixture{name: "sine-alt.wav", generate: generateAltSineWAV}
+	colorBars  = &fixture{name: "colorbars.mp4", generate: generateColorBars}
+	multiTrack = &fixture{name: "multitrack.mp4", generate: generateMultiTrack}
+)
+
+// Main initializes libVLC using VLCArgs, runs the tests and removes the
+// generated fixtures. It should be called from the TestMain function of
+// the test package:
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(fixtures.Main(m))
+//	}
+func Main(m *testing.M) int {
+	var err error
+	if dir, err = os.MkdirTemp("", "libvlc-go-fixtures-"); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+
+	if err := vlcompat.Init(VLCArgs...); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer vlcompat.Release()
+
+	return m.Run()
+}
+
+// SineWAV returns the path of a WAV file containing a sine wave tone of
+// SineFrequency Hz.
+func SineWAV(t testing.TB) string {
+	return sineWAV.path(t)
+}
+
+// ColorBars returns the path of an MP4 file containing a color bars video
+// track, without audio.
+func ColorBars(t testing.TB) string {
+	return colorBars.path(t)
+}
+
+// MultiTrack returns the path of an MP4 file containing the color bars
+// video track, two audio tracks (sine wave tones of SineFrequency and
+// AltSineFrequency Hz) and a subtitle track with SubtitleCues cues.
+func MultiTrack(t testing.TB) string {
+	return multiTrack.path(t)
+}
+
+// fixture is a media file which is generated the first time it is requested.
+type fixture struct {
+	name     string
+	generate func(dst string) error
+
+	once sync.Once
+	err  error
+}
+
+func (f *fixture) path(t testing.TB) string {
+	t.Helper()
+	if dir == "" {
+		t.Fatal("fixtures: Main must be called from TestMain")
+	}
+
+	path, err := f.get()
+	if err != nil {
+		t.Fatalf("fixtures: cannot generate %s: %v", f.name, err)
+	}
+
+	return path
+}
+
+// get returns the path of the fixture, generating it if necessary.
+func (f *fixture) get() (string, error) {
+	dst := filepath.Join(dir, f.name)
+	f.once.Do(func() {
+		if f.err = f.generate(dst); f.err == nil {
+			f.err = checkFile(dst)
+		}
+	})
+
+	return dst, f.err
+}
+
+func generateSineWAV(dst string) error {
+	return generateWAV(dst, SineFrequency)
+}
+
+func generateAltSineWAV(dst string) error {
+	return generateWAV(dst, AltSineFrequency)
+}
+
+func generateWAV(dst string, freq float64) error {
+	rawPath := dst + ".pcm"
+	if err := os.WriteFile(rawPath, sinePCM(freq, Duration), 0o644); err != nil {
+		return err
+	}
+	defer os.Remove(rawPath)
+
+	return transcode(rawPath,
+		":demux=rawaud",
+		":rawaud-fourcc=s16l",
+		fmt.Sprintf(":rawaud-channels=%d", AudioChannels),
+		fmt.Sprintf(":rawaud-samplerate=%d", SampleRate),
+		fmt.Sprintf(":sout=#transcode{acodec=s16l,channels=%d,samplerate=%d}:std{access=file,mux=wav,dst=%s}",
+			AudioChannels, SampleRate, soutPath(dst)),
+	)
+}
+
+func generateColorBars(dst string) error {
+	rawPath := dst + ".yuv"
+	if err := writeColorBarsI420(rawPath, VideoWidth, VideoHeight, int(Duration.Seconds()*FrameRate)); err != nil {
+		return err
+	}
+	defer os.Remove(rawPath)
+
+	return transcode(rawPath,
+		":demux=rawvid",
+		":rawvid-chroma=I420",
+		fmt.Sprintf(":rawvid-width=%d", VideoWidth),
+		fmt.Sprintf(":rawvid-height=%d", VideoHeight),
+		fmt.Sprintf(":rawvid-fps=%d", FrameRate),
+		fmt.Sprintf(":sout=#transcode{vcodec=mp4v,vb=800}:std{access=file,mux=mp4,dst=%s}", soutPath(dst)),
+	)
+}
+
+func generateMultiTrack(dst string) error {
+	videoPath, err := colorBars.get()
+	if err != nil {
+		return err
+	}
+	audioPath, err := sineWAV.get()
+	if err != nil {
+		return err
+	}
+	altPath, err := altSineWAV.get()
+	if err != nil {
+		return err
+	}
+
+	subsPath := dst + ".srt"
+	if err := os.WriteFile(subsPath, subtitlesSRT(SubtitleCues, Duration), 0o644); err != nil {
+		return err
+	}
+	defer os.Remove(subsPath)
+
+	// The audio tracks are added as input slaves of the video file, while
+	// the subtitles are passed through to the output without transcoding.
+	return transcode(videoPath,
+		":input-slave="+fileURL(audioPath)+"#"+fileURL(altPath),
+		":sub-file="+subsPath,
+		":sout-all",
+		fmt.Sprintf(":sout=#transcode{acodec=mp4a,ab=96,channels=%d,samplerate=%d}:std{access=file,mux=mp4,dst=%s}",
+			AudioChannels, SampleRate, soutPath(dst)),
+	)
+}
+
+// transcode plays the media file at the specified path using the provided
+// media options, which are expected to contain a stream output chain, and
+// waits for the playback to end.
+func transcode(path string, opts ...string) error {
+	media, err := vlcompat.NewMediaFromPath(path)
+	if err != nil {
+		return err
+	}
+	defer media.Release()
+
+	if err := media.AddOptions(opts...); err != nil {
+		return err
+	}
+
+	player, err := vlcompat.NewPlayer()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		player.Stop()
+		player.Release()
+	}()
+
+	if err := player.SetMedia(media); err != nil {
+		return err
+	}
+
+	manager, err := player.EventManager()
+	if err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	eventCallback := func(event vlcompat.Event, userData interface{}) {
+		var err error
+		if event == vlcompat.MediaPlayerEncounteredError {
+			err = errors.New("stream output failed")
+		}
+
+		select {
+		case done <- err:
+		default:
+		}
+	}
+
+	var eventIDs []vlcompat.EventID
+	for _, event := range []vlcompat.Event{vlcompat.MediaPlayerEndReached, vlcompat.MediaPlayerEncounteredError} {
+		eventID, err := manager.Attach(event, eventCallback, nil)
+		if err != nil {
+			return err
+		}
+		eventIDs = append(eventIDs, eventID)
+	}
+	defer manager.Detach(eventIDs...)
+
+	if err := player.Play(); err != nil {
+		return err
+	}
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(generateTimeout):
+		return fmt.Errorf("timed out after %s", generateTimeout)
+	}
+}
+
+func checkFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.Size() == 0 {
+		return errors.New("output file is empty")
+	}
+
+	return nil
+}
+
+func fileURL(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// soutPath quotes the specified path for use in stream output chains.
+func soutPath(path string) string {
+	return `"` + filepath.ToSlash(path) + `"`
+}
@@ -0,0 +1,115 @@
+package fixtures
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+// sinePCM returns interleaved signed 16-bit little endian PCM samples of a
+// sine wave with the specified frequency, using AudioChannels channels
+// sampled at SampleRate Hz.
+func sinePCM(freq float64, duration time.Duration) []byte {
+	samples := int(duration.Seconds() * SampleRate)
+	data := make([]byte, 0, samples*AudioChannels*2)
+
+	for i := 0; i < samples; i++ {
+		value := int16(math.Sin(2*math.Pi*freq*float64(i)/SampleRate) * math.MaxInt16 / 2)
+		for c := 0; c < AudioChannels; c++ {
+			data = binary.LittleEndian.AppendUint16(data, uint16(value))
+		}
+	}
+
+	return data
+}
+
+// colorBarsYUV contains the BT.601 YUV values of the standard color bars:
+// white, yellow, cyan, green, magenta, red, blue and black.
+var colorBarsYUV = [][3]byte{
+	{235, 128, 128},
+	{210, 16, 146},
+	{170, 166, 16},
+	{145, 54, 34},
+	{106, 202, 222},
+	{81, 90, 240},
+	{41, 240, 110},
+	{16, 128, 128},
+}
+
+// colorBarsI420 returns a color bars frame of the specified size, using the
+// planar I420 format.
+func colorBarsI420(width, height int) []byte {
+	var (
+		chromaWidth  = width / 2
+		chromaHeight = height / 2
+		lumaSize     = width * height
+		chromaSize   = chromaWidth * chromaHeight
+		frame        = make([]byte, lumaSize+2*chromaSize)
+	)
+
+	barColor := func(x, planeWidth int) [3]byte {
+		return colorBarsYUV[x*len(colorBarsYUV)/planeWidth]
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			frame[y*width+x] = barColor(x, width)[0]
+		}
+	}
+	for y := 0; y < chromaHeight; y++ {
+		for x := 0; x < chromaWidth; x++ {
+			color := barColor(x, chromaWidth)
+			frame[lumaSize+y*chromaWidth+x] = color[1]
+			frame[lumaSize+chromaSize+y*chromaWidth+x] = color[2]
+		}
+	}
+
+	return frame
+}
+
+// writeColorBarsI420 writes the specified number of color bars frames to
+// the file at the specified path, as raw I420 video.
+func writeColorBarsI420(path string, width, height, frames int) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	frame := colorBarsI420(width, height)
+	for i := 0; i < frames; i++ {
+		if _, err := w.Write(frame); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// subtitlesSRT returns a SubRip subtitle file containing the specified
+// number of cues, evenly distributed over the specified duration.
+func subtitlesSRT(cues int, duration time.Duration) []byte {
+	formatTime := func(d time.Duration) string {
+		ms := d.Milliseconds()
+		return fmt.Sprintf("%02d:%02d:%02d,%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+	}
+
+	var buf bytes.Buffer
+	cueDuration := duration / time.Duration(cues)
+	for i := 0; i < cues; i++ {
+		start := time.Duration(i) * cueDuration
+		fmt.Fprintf(&buf, "%d\n%s --> %s\nSubtitle %d\n\n",
+			i+1, formatTime(start), formatTime(start+cueDuration), i+1)
+	}
+
+	return buf.Bytes()
+}
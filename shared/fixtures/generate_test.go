@@ -0,0 +1,49 @@
+package fixtures
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func TestSinePCM(t *testing.T) {
+	data := sinePCM(SineFrequency, time.Second)
+	if got, want := len(data), SampleRate*AudioChannels*2; got != want {
+		t.Fatalf("got %d bytes, want %d", got, want)
+	}
+
+	// The first sample is zero and the channels are identical.
+	if sample := binary.LittleEndian.Uint16(data); sample != 0 {
+		t.Errorf("got first sample %d, want 0", sample)
+	}
+	frame := 10 * AudioChannels * 2
+	for c := 1; c < AudioChannels; c++ {
+		if data[frame] != data[frame+c*2] || data[frame+1] != data[frame+c*2+1] {
+			t.Errorf("channel %d differs from channel 0", c)
+		}
+	}
+}
+
+func TestColorBarsI420(t *testing.T) {
+	frame := colorBarsI420(VideoWidth, VideoHeight)
+	if got, want := len(frame), VideoWidth*VideoHeight*3/2; got != want {
+		t.Fatalf("got %d bytes, want %d", got, want)
+	}
+
+	barWidth := VideoWidth / len(colorBarsYUV)
+	for i, color := range colorBarsYUV {
+		if got := frame[i*barWidth+barWidth/2]; got != color[0] {
+			t.Errorf("bar %d: got luma %d, want %d", i, got, color[0])
+		}
+	}
+}
+
+func TestSubtitlesSRT(t *testing.T) {
+	got := string(subtitlesSRT(3, 3*time.Second))
+	want := "1\n00:00:00,000 --> 00:00:01,000\nSubtitle 1\n\n" +
+		"2\n00:00:01,000 --> 00:00:02,000\nSubtitle 2\n\n" +
+		"3\n00:00:02,000 --> 00:00:03,000\nSubtitle 3\n\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,59 @@
+package fixtures
+
+import (
+	"bytes"
+	"log"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Log collects the output of loggers used from libVLC event callbacks,
+// which are called on separate threads.
+type Log struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// Write appends the specified data to the log.
+func (l *Log) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.buf.Write(p)
+}
+
+// String returns the contents of the log.
+func (l *Log) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.buf.String()
+}
+
+// Logger returns a logger which writes to the log, without any prefix.
+func (l *Log) Logger() *log.Logger {
+	return log.New(l, "", 0)
+}
+
+// PlaybackTimeout is the maximum duration of the playback of a fixture.
+const PlaybackTimeout = Duration + 20*time.Second
+
+// RunWithTimeout calls fn and fails the test if it does not return within
+// the specified duration. Returns the error returned by fn.
+func RunWithTimeout(t testing.TB, timeout time.Duration, fn func() error) error {
+	t.Helper()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		t.Fatalf("timed out after %s", timeout)
+		return nil
+	}
+}
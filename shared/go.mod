@@ -0,0 +1,9 @@
+module github.com/adrg/libvlc-go-examples/shared
+
+go 1.21
+
+require (
+	github.com/adrg/libvlc-go/v2 v2.1.5
+	github.com/adrg/libvlc-go/v3 v3.1.5
+	github.com/gotk3/gotk3 v0.6.2
+)
@@ -0,0 +1,73 @@
+//go:build gtk3
+
+package gtkutil
+
+import (
+	"os"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// App is a GTK application which uses libVLC.
+type App struct {
+	*gtk.Application
+	cleanups []func()
+	release  func()
+}
+
+// NewApp calls the init function, which is expected to initialize libVLC,
+// and creates a new GTK application with the specified ID. The release
+// function is called if the application cannot be created, or when the
+// application shuts down, after the functions registered using OnShutdown
+// are called.
+func NewApp(id string, init func() error, release func()) (*App, error) {
+	if err := init(); err != nil {
+		return nil, err
+	}
+
+	gtkApp, err := gtk.ApplicationNew(id, glib.APPLICATION_FLAGS_NONE)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	app := &App{Application: gtkApp, release: release}
+	app.Connect("shutdown", app.shutdown)
+
+	return app, nil
+}
+
+// OnActivate loads the specified layout file and calls fn with the created
+// builder when the application is activated. The application panics if the
+// layout file cannot be loaded.
+func (a *App) OnActivate(layoutFile string, fn func(builder *gtk.Builder)) {
+	a.Connect("activate", func() {
+		builder, err := gtk.BuilderNewFromFile(layoutFile)
+		AssertErr(err)
+
+		fn(builder)
+	})
+}
+
+// OnShutdown registers a function to be called when the application shuts
+// down. The functions are called in the reverse order of their registration,
+// so resources should be registered right after they are created.
+func (a *App) OnShutdown(fn func()) {
+	a.cleanups = append(a.cleanups, fn)
+}
+
+// Run runs the application using the command line arguments of the process
+// and returns its exit status.
+func (a *App) Run() int {
+	return a.Application.Run(os.Args)
+}
+
+func (a *App) shutdown() {
+	for i := len(a.cleanups) - 1; i >= 0; i-- {
+		a.cleanups[i]()
+	}
+	a.cleanups = nil
+
+	a.release()
+}
@@ -0,0 +1,49 @@
+//go:build gtk3
+
+// Package gtkutil contains the helpers shared by the GTK 3 examples of all
+// modules, which do not depend on a specific libVLC version: typed widget
+// lookup, error assertions, a standard application bootstrap and access to
+// the native handles of GDK windows. The libVLC specific helpers are
+// provided by the gtkvlc package of each module.
+package gtkutil
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// Get returns the object with the specified ID from the builder, converted
+// to type T. The returned error names the ID of the object if it is missing
+// or if it is not of type T.
+func Get[T any](builder *gtk.Builder, id string) (T, error) {
+	var zero T
+
+	obj, err := builder.GetObject(id)
+	if err != nil || obj == nil {
+		return zero, fmt.Errorf("widget %q not found in layout", id)
+	}
+
+	widget, ok := obj.(T)
+	if !ok {
+		return zero, fmt.Errorf("widget %q is %T, not %T", id, obj, zero)
+	}
+
+	return widget, nil
+}
+
+// MustGet is like Get but panics if the object cannot be retrieved.
+func MustGet[T any](builder *gtk.Builder, id string) T {
+	widget, err := Get[T](builder, id)
+	AssertErr(err)
+
+	return widget
+}
+
+// AssertErr panics if the specified error is not nil.
+func AssertErr(err error) {
+	if err != nil {
+		log.Panic(err)
+	}
+}
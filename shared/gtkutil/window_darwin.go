@@ -0,0 +1,24 @@
+//go:build gtk3
+
+package gtkutil
+
+/*
+#cgo CFLAGS: -x objective-c
+#cgo pkg-config: gdk-3.0
+#include <AppKit/AppKit.h>
+#include <gdk/gdk.h>
+
+GDK_AVAILABLE_IN_ALL NSView* gdk_quartz_window_get_nsview(GdkWindow *window);
+*/
+import "C"
+import (
+	"unsafe"
+
+	"github.com/gotk3/gotk3/gdk"
+)
+
+// WindowHandle returns the native handle of the specified GDK window
+// (the NSView of the window).
+func WindowHandle(window *gdk.Window) uintptr {
+	return uintptr(unsafe.Pointer(C.gdk_quartz_window_get_nsview((*C.GdkWindow)(unsafe.Pointer(window.GObject)))))
+}
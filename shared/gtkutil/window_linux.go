@@ -0,0 +1,13 @@
+//go:build gtk3
+
+package gtkutil
+
+import (
+	"github.com/gotk3/gotk3/gdk"
+)
+
+// WindowHandle returns the native handle of the specified GDK window
+// (the X11 window ID).
+func WindowHandle(window *gdk.Window) uintptr {
+	return uintptr(window.GetXID())
+}
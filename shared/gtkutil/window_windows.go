@@ -0,0 +1,21 @@
+//go:build gtk3
+
+package gtkutil
+
+/*
+#cgo pkg-config: gdk-3.0
+#include <gdk/gdk.h>
+#include <gdk/gdkwin32.h>
+*/
+import "C"
+import (
+	"unsafe"
+
+	"github.com/gotk3/gotk3/gdk"
+)
+
+// WindowHandle returns the native handle of the specified GDK window
+// (the HWND of the window).
+func WindowHandle(window *gdk.Window) uintptr {
+	return uintptr(unsafe.Pointer(C.gdk_win32_window_get_handle((*C.GdkWindow)(unsafe.Pointer(window.Native())))))
+}
@@ -3,19 +3,19 @@ package main
 import (
 	"log"
 
-	vlc "github.com/adrg/libvlc-go/v3"
+	"github.com/adrg/libvlc-go-examples/shared/vlcompat"
 )
 
 func main() {
 	// Initialize libVLC. Additional command line arguments can be passed in
 	// to libVLC by specifying them in the Init function.
-	if err := vlc.Init("--no-video", "--quiet"); err != nil {
+	if err := vlcompat.Init("--no-video", "--quiet"); err != nil {
 		log.Fatal(err)
 	}
-	defer vlc.Release()
+	defer vlcompat.Release()
 
 	// Create a new list player.
-	player, err := vlc.NewListPlayer()
+	player, err := vlcompat.NewListPlayer()
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -25,7 +25,7 @@ func main() {
 	}()
 
 	// Create a new media list.
-	list, err := vlc.NewMediaList()
+	list, err := vlcompat.NewMediaList()
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -61,11 +61,11 @@ func main() {
 
 	// Register the media end reached event with the event manager.
 	quit := make(chan struct{})
-	eventCallback := func(event vlc.Event, userData interface{}) {
+	eventCallback := func(event vlcompat.Event, userData interface{}) {
 		close(quit)
 	}
 
-	eventID, err := manager.Attach(vlc.MediaListPlayerPlayed, eventCallback, nil)
+	eventID, err := manager.Attach(vlcompat.MediaListPlayerPlayed, eventCallback, nil)
 	if err != nil {
 		log.Fatal(err)
 	}
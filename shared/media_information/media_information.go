@@ -0,0 +1,163 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/adrg/libvlc-go-examples/shared/vlcompat"
+)
+
+// metaFields contains the media metadata fields which are logged.
+var metaFields = []struct {
+	Key  vlcompat.MetaKey
+	Name string
+}{
+	{vlcompat.MediaTitle, "title"},
+	{vlcompat.MediaArtist, "artist"},
+	{vlcompat.MediaAlbum, "album"},
+	{vlcompat.MediaAlbumArtist, "album artist"},
+	{vlcompat.MediaGenre, "genre"},
+	{vlcompat.MediaDate, "date"},
+	{vlcompat.MediaTrackNumber, "track number"},
+	{vlcompat.MediaDescription, "description"},
+	{vlcompat.MediaArtworkURL, "artwork URL"},
+}
+
+func main() {
+	// Initialize libVLC. Additional command line arguments can be passed in
+	// to libVLC by specifying them in the Init function.
+	if err := vlcompat.Init("--no-video", "--quiet"); err != nil {
+		log.Fatal(err)
+	}
+	defer vlcompat.Release()
+
+	if err := playMedia("localpath/test.mp3", log.Default()); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// playMedia plays the media file at the specified path using a list player
+// and logs its metadata once it starts playing. Returns when playback ends.
+func playMedia(path string, logger *log.Logger) error {
+	// Create a new list player.
+	lp, err := vlcompat.NewListPlayer()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		lp.Stop()
+		lp.Release()
+	}()
+
+	// Create a new media list.
+	list, err := vlcompat.NewMediaList()
+	if err != nil {
+		return err
+	}
+	defer list.Release()
+
+	// Add media to list.
+	media, err := vlcompat.NewMediaFromPath(path)
+	if err != nil {
+		return err
+	}
+	if err = media.Parse(10 * time.Second); err != nil {
+		return err
+	}
+
+	// Retrieve media parse status. Parse statuses are only available on
+	// libVLC 3, so ErrUnsupported is ignored.
+	parseStatus, err := media.ParseStatus()
+	switch {
+	case err == nil:
+		logger.Println("Media parse status:", parseStatus)
+	case !errors.Is(err, vlcompat.ErrUnsupported):
+		return err
+	}
+
+	err = list.AddMedia(media)
+	if err != nil {
+		return err
+	}
+
+	// Set player media list.
+	if err = lp.SetMediaList(list); err != nil {
+		return err
+	}
+
+	// Retrieve player event manager.
+	manager, err := lp.EventManager()
+	if err != nil {
+		return err
+	}
+
+	// Create event handler.
+	quit := make(chan struct{})
+	eventCallback := func(event vlcompat.Event, userData interface{}) {
+		switch event {
+		case vlcompat.MediaListPlayerPlayed:
+			logger.Println("Player end reached")
+			close(quit)
+		case vlcompat.MediaListPlayerNextItemSet:
+			// Retrieve underlying player.
+			p, err := lp.Player()
+			if err != nil {
+				logger.Println(err)
+				break
+			}
+
+			// Retrieve currently playing media.
+			media, err := p.Media()
+			if err != nil {
+				logger.Println(err)
+				break
+			}
+
+			// Get media location.
+			location, err := media.Location()
+			if err != nil {
+				logger.Println(err)
+				break
+			}
+			logger.Println("Media location:", location)
+
+			// Get media metadata. Fields which are not available on
+			// libVLC 2 (e.g. album artist) are logged as unsupported.
+			for _, field := range metaFields {
+				value, err := media.Meta(field.Key)
+				if err != nil {
+					value = err.Error()
+				}
+				logger.Printf("Media %s: %s\n", field.Name, value)
+			}
+		}
+	}
+
+	// Register events with the event manager.
+	events := []vlcompat.Event{
+		vlcompat.MediaListPlayerPlayed,
+		vlcompat.MediaListPlayerNextItemSet,
+	}
+
+	var eventIDs []vlcompat.EventID
+	for _, event := range events {
+		eventID, err := manager.Attach(event, eventCallback, nil)
+		if err != nil {
+			return err
+		}
+
+		eventIDs = append(eventIDs, eventID)
+	}
+
+	// De-register attached events.
+	defer manager.Detach(eventIDs...)
+
+	// Start playing the media list.
+	if err = lp.Play(); err != nil {
+		return err
+	}
+
+	<-quit
+	return nil
+}
@@ -0,0 +1,73 @@
+//go:build integration
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adrg/libvlc-go-examples/shared/fixtures"
+	"github.com/adrg/libvlc-go-examples/shared/vlcompat"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Main(m))
+}
+
+func TestPlayMedia(t *testing.T) {
+	path := fixtures.SineWAV(t)
+
+	var output fixtures.Log
+	err := fixtures.RunWithTimeout(t, fixtures.PlaybackTimeout, func() error {
+		return playMedia(path, output.Logger())
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Files without a title tag are titled using their file name.
+	lines := strings.Split(output.String(), "\n")
+	wantLines := []string{
+		"Media title: " + filepath.Base(path),
+		"Media artist: ",
+		"Player end reached",
+	}
+	if vlcompat.MajorVersion >= 3 {
+		// Parse statuses are only available on libVLC 3.
+		wantLines = append(wantLines, fmt.Sprint("Media parse status: ", vlcompat.MediaParseDone))
+	}
+	for _, want := range wantLines {
+		if !containsLine(lines, want) {
+			t.Errorf("output does not contain %q:\n%s", want, output.String())
+		}
+	}
+
+	// Check the location of the played media.
+	var location string
+	for _, line := range lines {
+		if strings.HasPrefix(line, "Media location: ") {
+			location = strings.TrimPrefix(line, "Media location: ")
+		}
+	}
+	if !strings.HasPrefix(location, "file://") || !strings.HasSuffix(location, filepath.Base(path)) {
+		t.Errorf("got media location %q, want file URL of %s", location, path)
+	}
+
+	// The end of the playback is logged last.
+	if !strings.HasSuffix(output.String(), "Player end reached\n") {
+		t.Errorf("want %q logged last:\n%s", "Player end reached", output.String())
+	}
+}
+
+func containsLine(lines []string, line string) bool {
+	for _, l := range lines {
+		if strings.TrimSpace(l) == strings.TrimSpace(line) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -3,26 +3,27 @@ package main
 import (
 	"fmt"
 	"log"
+	"time"
 
-	vlc "github.com/adrg/libvlc-go/v2"
+	"github.com/adrg/libvlc-go-examples/shared/vlcompat"
 )
 
 func main() {
 	// Initialize libVLC.
-	if err := vlc.Init("--quiet"); err != nil {
+	if err := vlcompat.Init("--quiet"); err != nil {
 		log.Fatal(err)
 	}
-	defer vlc.Release()
+	defer vlcompat.Release()
 
 	// Load media from file.
-	media, err := vlc.NewMediaFromPath("test.mp4")
+	media, err := vlcompat.NewMediaFromPath("test.mp4")
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer media.Release()
 
-	// Parse media.
-	if err := media.Parse(); err != nil {
+	// Parse media. The timeout is only enforced by libVLC 3.
+	if err := media.Parse(10 * time.Second); err != nil {
 		log.Fatal(err)
 	}
 
@@ -38,25 +39,38 @@ func main() {
 		fmt.Println("Bit rate:", track.BitRate)
 		fmt.Println("Codec:", track.Codec)
 		fmt.Println("Original codec:", track.OriginalCodec)
+		if track.CodecDescription != "" {
+			// Codec descriptions are only available on libVLC 3.
+			fmt.Println("Codec description:", track.CodecDescription)
+		}
 		fmt.Println("Profile:", track.Profile)
 		fmt.Println("Level:", track.Level)
 		fmt.Println("Language:", track.Language)
 		fmt.Println("Description:", track.Description)
 
 		switch track.Type {
-		case vlc.MediaTrackAudio:
+		case vlcompat.MediaTrackAudio:
 			audio := track.Audio
 			fmt.Println("Type: audio track")
 			fmt.Println("Audio channels:", audio.Channels)
 			fmt.Println("Audio rate:", audio.Rate)
-		case vlc.MediaTrackVideo:
+		case vlcompat.MediaTrackVideo:
 			video := track.Video
 			fmt.Println("Type: video track")
 			fmt.Println("Video width:", video.Width)
 			fmt.Println("Video height:", video.Height)
 			fmt.Printf("Aspect ratio: %d:%d\n", video.AspectRatioNum, video.AspectRatioDen)
 			fmt.Printf("Frame rate: %d/%d\n", video.FrameRateNum, video.FrameRateDen)
-		case vlc.MediaTrackText:
+
+			// The orientation, projection and viewpoint of videos are only
+			// available on libVLC 3.
+			if pose := video.Viewpoint; pose != nil {
+				fmt.Println("Video orientation:", video.Orientation)
+				fmt.Println("Video projection:", video.Projection)
+				fmt.Printf("Video viewpoint: %.2f yaw, %.2f pitch, %.2f roll, %.2f FOV\n",
+					pose.Yaw, pose.Pitch, pose.Roll, pose.FOV)
+			}
+		case vlcompat.MediaTrackText:
 			subtitle := track.Subtitle
 			fmt.Println("Type: subtitle track")
 			fmt.Println("Encoding:", subtitle.Encoding)
@@ -3,19 +3,19 @@ package main
 import (
 	"log"
 
-	vlc "github.com/adrg/libvlc-go/v3"
+	"github.com/adrg/libvlc-go-examples/shared/vlcompat"
 )
 
 func main() {
 	// Initialize libVLC. Additional command line arguments can be passed in
 	// to libVLC by specifying them in the Init function.
-	if err := vlc.Init("--no-video", "--quiet"); err != nil {
+	if err := vlcompat.Init("--no-video", "--quiet"); err != nil {
 		log.Fatal(err)
 	}
-	defer vlc.Release()
+	defer vlcompat.Release()
 
 	// Create a new player.
-	player, err := vlc.NewPlayer()
+	player, err := vlcompat.NewPlayer()
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -42,11 +42,11 @@ func main() {
 
 	// Register the media end reached event with the event manager.
 	quit := make(chan struct{})
-	eventCallback := func(event vlc.Event, userData interface{}) {
+	eventCallback := func(event vlcompat.Event, userData interface{}) {
 		close(quit)
 	}
 
-	eventID, err := manager.Attach(vlc.MediaPlayerEndReached, eventCallback, nil)
+	eventID, err := manager.Attach(vlcompat.MediaPlayerEndReached, eventCallback, nil)
 	if err != nil {
 		log.Fatal(err)
 	}
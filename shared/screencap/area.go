@@ -0,0 +1,63 @@
+package screencap
+
+import (
+	"fmt"
+	"math"
+)
+
+// Area represents a rectangular area of the screen, in screen coordinates.
+type Area struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+// NewArea returns the area delimited by the specified points, regardless
+// of the order in which they are provided.
+func NewArea(x1, y1, x2, y2 int) Area {
+	if x2 < x1 {
+		x1, x2 = x2, x1
+	}
+	if y2 < y1 {
+		y1, y2 = y2, y1
+	}
+
+	return Area{X: x1, Y: y1, Width: x2 - x1, Height: y2 - y1}
+}
+
+// ParseRegion parses regions specified using the X11 geometry format
+// (e.g. 640x480+10+20). The size of the region must be positive and its
+// offsets must not be negative.
+func ParseRegion(region string) (Area, error) {
+	var area Area
+	if _, err := fmt.Sscanf(region, "%dx%d+%d+%d", &area.Width, &area.Height, &area.X, &area.Y); err != nil {
+		return Area{}, fmt.Errorf("invalid region %q: expected WIDTHxHEIGHT+X+Y", region)
+	}
+	if area.IsEmpty() || area.X < 0 || area.Y < 0 {
+		return Area{}, fmt.Errorf("invalid region %q: size must be positive and offsets non-negative", region)
+	}
+
+	return area, nil
+}
+
+// IsEmpty returns true if the area has no width or height.
+func (a Area) IsEmpty() bool {
+	return a.Width <= 0 || a.Height <= 0
+}
+
+// String returns a textual representation of the area (e.g. 640x480+10+20).
+func (a Area) String() string {
+	return fmt.Sprintf("%dx%d%+d%+d", a.Width, a.Height, a.X, a.Y)
+}
+
+// Scale returns the area having its position and size multiplied by the
+// specified factor and rounded to the nearest integer (e.g. for converting
+// application pixels to device pixels on HiDPI screens).
+func (a Area) Scale(factor float64) Area {
+	scale := func(v int) int {
+		return int(math.Round(float64(v) * factor))
+	}
+
+	return Area{X: scale(a.X), Y: scale(a.Y), Width: scale(a.Width), Height: scale(a.Height)}
+}
@@ -0,0 +1,41 @@
+package screencap
+
+import "testing"
+
+func TestParseRegion(t *testing.T) {
+	area, err := ParseRegion("640x480+10+20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Area{X: 10, Y: 20, Width: 640, Height: 480}); area != want {
+		t.Errorf("got %+v, want %+v", area, want)
+	}
+	if got := area.String(); got != "640x480+10+20" {
+		t.Errorf("got %q, want %q", got, "640x480+10+20")
+	}
+
+	for _, region := range []string{"640x480", "0x480+0+0", "640x480+-1+0", "region"} {
+		if _, err := ParseRegion(region); err == nil {
+			t.Errorf("expected error for region %q", region)
+		}
+	}
+}
+
+func TestNewArea(t *testing.T) {
+	if got, want := NewArea(100, 80, 20, 10), (Area{X: 20, Y: 10, Width: 80, Height: 70}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if !NewArea(10, 10, 10, 50).IsEmpty() {
+		t.Error("expected empty area")
+	}
+}
+
+func TestAreaScale(t *testing.T) {
+	area := Area{X: 10, Y: 20, Width: 640, Height: 480}
+	if got, want := area.Scale(2), (Area{X: 20, Y: 40, Width: 1280, Height: 960}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if got, want := area.Scale(0.5), (Area{X: 5, Y: 10, Width: 320, Height: 240}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,100 @@
+// Package screencap contains screen capture helpers which do not depend on
+// libVLC: audio capture sources, monitor layouts, capture regions and X11
+// window geometry. They are shared by the screen recording examples of all
+// libvlc-go versions.
+package screencap
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"strings"
+)
+
+// AudioSource represents an audio capture device, which can be passed in
+// to libVLC using the `input-slave` media option.
+type AudioSource struct {
+	// Name of the device, displayed in the audio source combo boxes.
+	Name string
+
+	// Media resource locator of the device (e.g. pulse://<source name>).
+	MRL string
+}
+
+// AudioCodec represents an audio codec used to encode captured audio.
+type AudioCodec struct {
+	// Name of the codec, displayed in the audio codec combo boxes.
+	Name string
+
+	// Codec identifier (e.g. mp4a, vorb).
+	Codec string
+
+	// Bitrate in kb/s. Default: 0 (encoder default).
+	Bitrate int
+}
+
+// AudioCodecs contains the audio codecs which can be used to encode
+// captured audio.
+var AudioCodecs = []*AudioCodec{
+	{Name: "AAC", Codec: "mp4a", Bitrate: 128},
+	{Name: "MP3", Codec: "mpga", Bitrate: 128},
+	{Name: "Vorbis", Codec: "vorb", Bitrate: 128},
+	{Name: "Opus", Codec: "opus", Bitrate: 96},
+	{Name: "FLAC", Codec: "flac"},
+}
+
+// AudioCodecIndex returns the index of the specified codec identifier in
+// AudioCodecs. The index of the first codec is returned if the identifier
+// is unknown.
+func AudioCodecIndex(codec string) int {
+	for i, audioCodec := range AudioCodecs {
+		if audioCodec.Codec == codec {
+			return i
+		}
+	}
+
+	return 0
+}
+
+// ListAudioSources returns the available audio capture devices. PulseAudio
+// sources (including monitors of output devices) are retrieved using
+// `pactl`, if available. The default PulseAudio and ALSA devices are always
+// included in the returned list.
+func ListAudioSources() []*AudioSource {
+	sources := []*AudioSource{
+		{Name: "Default PulseAudio source", MRL: "pulse://"},
+		{Name: "Default ALSA device", MRL: "alsa://default"},
+	}
+
+	output, err := exec.Command("pactl", "list", "short", "sources").Output()
+	if err != nil {
+		return sources
+	}
+
+	return append(sources, parseAudioSources(output)...)
+}
+
+// parseAudioSources parses the output of `pactl list short sources`. Each
+// line has the following format:
+// <index>\t<name>\t<driver>\t<sample spec>\t<state>
+func parseAudioSources(output []byte) []*AudioSource {
+	var sources []*AudioSource
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 2 || fields[1] == "" {
+			continue
+		}
+
+		name := fields[1]
+		label := "Input: " + name
+		if strings.HasSuffix(name, ".monitor") {
+			label = "Monitor: " + strings.TrimSuffix(name, ".monitor")
+		}
+
+		sources = append(sources, &AudioSource{Name: label, MRL: "pulse://" + name})
+	}
+
+	return sources
+}
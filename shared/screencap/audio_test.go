@@ -0,0 +1,23 @@
+package screencap
+
+import "testing"
+
+func TestParseAudioSources(t *testing.T) {
+	output := []byte("0\talsa_output.pci.analog-stereo.monitor\tmodule-alsa-card.c\ts16le 2ch 44100Hz\tSUSPENDED\n" +
+		"1\talsa_input.pci.analog-stereo\tmodule-alsa-card.c\ts16le 2ch 44100Hz\tRUNNING\n" +
+		"\n")
+
+	sources := parseAudioSources(output)
+	want := []AudioSource{
+		{Name: "Monitor: alsa_output.pci.analog-stereo", MRL: "pulse://alsa_output.pci.analog-stereo.monitor"},
+		{Name: "Input: alsa_input.pci.analog-stereo", MRL: "pulse://alsa_input.pci.analog-stereo"},
+	}
+	if len(sources) != len(want) {
+		t.Fatalf("got %d sources, want %d", len(sources), len(want))
+	}
+	for i, source := range sources {
+		if *source != want[i] {
+			t.Errorf("source %d: got %+v, want %+v", i, *source, want[i])
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package screencap
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Monitor represents a monitor of the X11 display.
+type Monitor struct {
+	Index   int
+	Name    string
+	Primary bool
+	X       int
+	Y       int
+	Width   int
+	Height  int
+}
+
+// String returns a textual representation of the monitor
+// (e.g. 0: HDMI-1 1920x1080+0+0 (primary)).
+func (m *Monitor) String() string {
+	str := fmt.Sprintf("%s %dx%d%+d%+d", m.Name, m.Width, m.Height, m.X, m.Y)
+	if m.Index >= 0 {
+		str = fmt.Sprintf("%d: %s", m.Index, str)
+	}
+	if m.Primary {
+		str += " (primary)"
+	}
+
+	return str
+}
+
+// Area returns the area of the screen covered by the monitor.
+func (m *Monitor) Area() Area {
+	return Area{X: m.X, Y: m.Y, Width: m.Width, Height: m.Height}
+}
+
+// ListMonitors returns the monitors of the X11 display, as reported by
+// `xrandr --listmonitors`.
+func ListMonitors() ([]*Monitor, error) {
+	output, err := exec.Command("xrandr", "--listmonitors").Output()
+	if err != nil {
+		return nil, fmt.Errorf("cannot list monitors: %w", err)
+	}
+
+	return ParseMonitors(output)
+}
+
+// ParseMonitors parses the output of `xrandr --listmonitors`, which has the
+// following format:
+//
+//	Monitors: 2
+//	 0: +*HDMI-1 1920/531x1080/299+0+0  HDMI-1
+//	 1: +DP-1 2560/597x1440/336+1920+0  DP-1
+func ParseMonitors(output []byte) ([]*Monitor, error) {
+	var monitors []*Monitor
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || !strings.HasSuffix(fields[0], ":") {
+			continue
+		}
+
+		index, err := strconv.Atoi(strings.TrimSuffix(fields[0], ":"))
+		if err != nil {
+			continue
+		}
+
+		m := &Monitor{
+			Index:   index,
+			Name:    strings.TrimLeft(fields[1], "+*"),
+			Primary: strings.Contains(fields[1], "*"),
+		}
+
+		// Parse geometry, ignoring the physical size of the monitor.
+		var physW, physH int
+		if _, err := fmt.Sscanf(fields[2], "%d/%dx%d/%d+%d+%d",
+			&m.Width, &physW, &m.Height, &physH, &m.X, &m.Y); err != nil {
+			return nil, fmt.Errorf("invalid monitor geometry %q", fields[2])
+		}
+
+		monitors = append(monitors, m)
+	}
+	if len(monitors) == 0 {
+		return nil, errors.New("no monitors found")
+	}
+
+	return monitors, nil
+}
+
+// FindMonitor returns the monitor having the specified index or name.
+// If the query is `all`, the returned monitor covers all monitors.
+func FindMonitor(monitors []*Monitor, query string) (*Monitor, error) {
+	if query == "all" {
+		return AllMonitors(monitors), nil
+	}
+
+	if index, err := strconv.Atoi(query); err == nil {
+		for _, m := range monitors {
+			if m.Index == index {
+				return m, nil
+			}
+		}
+	}
+	for _, m := range monitors {
+		if strings.EqualFold(m.Name, query) {
+			return m, nil
+		}
+	}
+
+	return nil, fmt.Errorf("monitor %q not found", query)
+}
+
+// AllMonitors returns a monitor covering the bounding box of all the
+// specified monitors.
+func AllMonitors(monitors []*Monitor) *Monitor {
+	all := &Monitor{Index: -1, Name: "all"}
+	if len(monitors) == 0 {
+		return all
+	}
+
+	x1, y1 := monitors[0].X, monitors[0].Y
+	x2, y2 := x1+monitors[0].Width, y1+monitors[0].Height
+	for _, m := range monitors[1:] {
+		if m.X < x1 {
+			x1 = m.X
+		}
+		if m.Y < y1 {
+			y1 = m.Y
+		}
+		if m.X+m.Width > x2 {
+			x2 = m.X + m.Width
+		}
+		if m.Y+m.Height > y2 {
+			y2 = m.Y + m.Height
+		}
+	}
+
+	all.X, all.Y, all.Width, all.Height = x1, y1, x2-x1, y2-y1
+	return all
+}
+
+// MonitorAt returns the monitor containing the center of the specified
+// area, or nil if the center is not located on any of the monitors.
+func MonitorAt(monitors []*Monitor, area Area) *Monitor {
+	cx, cy := area.X+area.Width/2, area.Y+area.Height/2
+	for _, m := range monitors {
+		if cx >= m.X && cx < m.X+m.Width && cy >= m.Y && cy < m.Y+m.Height {
+			return m
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package screencap
+
+import "testing"
+
+func TestParseMonitors(t *testing.T) {
+	output := []byte("Monitors: 2\n" +
+		" 0: +*HDMI-1 1920/531x1080/299+0+0  HDMI-1\n" +
+		" 1: +DP-1 2560/597x1440/336+1920+0  DP-1\n")
+
+	monitors, err := ParseMonitors(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Monitor{
+		{Index: 0, Name: "HDMI-1", Primary: true, X: 0, Y: 0, Width: 1920, Height: 1080},
+		{Index: 1, Name: "DP-1", X: 1920, Y: 0, Width: 2560, Height: 1440},
+	}
+	if len(monitors) != len(want) {
+		t.Fatalf("got %d monitors, want %d", len(monitors), len(want))
+	}
+	for i, m := range monitors {
+		if *m != want[i] {
+			t.Errorf("monitor %d: got %+v, want %+v", i, *m, want[i])
+		}
+	}
+
+	if got, want := monitors[0].String(), "0: HDMI-1 1920x1080+0+0 (primary)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	all := AllMonitors(monitors)
+	if all.X != 0 || all.Y != 0 || all.Width != 4480 || all.Height != 1440 {
+		t.Errorf("got all monitors area %+v, want 4480x1440+0+0", *all)
+	}
+
+	if all.Area() != (Area{Width: 4480, Height: 1440}) {
+		t.Errorf("got all monitors area %s, want 4480x1440+0+0", all.Area())
+	}
+	if m := MonitorAt(monitors, Area{X: 2000, Y: 100, Width: 640, Height: 480}); m == nil || m.Name != "DP-1" {
+		t.Errorf("MonitorAt: got %v, want DP-1", m)
+	}
+	if m := MonitorAt(monitors, Area{X: 5000, Y: 0, Width: 10, Height: 10}); m != nil {
+		t.Errorf("MonitorAt: got %v, want nil", m)
+	}
+
+	for _, query := range []string{"1", "dp-1"} {
+		m, err := FindMonitor(monitors, query)
+		if err != nil || m.Name != "DP-1" {
+			t.Errorf("FindMonitor(%q): got %v, %v, want DP-1", query, m, err)
+		}
+	}
+	if _, err := FindMonitor(monitors, "VGA-1"); err == nil {
+		t.Error("expected error for unknown monitor")
+	}
+}
+
+func TestParseMonitorsInvalid(t *testing.T) {
+	if _, err := ParseMonitors([]byte("Monitors: 0\n")); err == nil {
+		t.Error("expected error for output without monitors")
+	}
+	if _, err := ParseMonitors([]byte(" 0: +*HDMI-1 1920x1080+0+0  HDMI-1\n")); err == nil {
+		t.Error("expected error for invalid monitor geometry")
+	}
+}
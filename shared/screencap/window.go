@@ -0,0 +1,77 @@
+package screencap
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// PickWindowArea waits for the user to click on an X11 window and returns
+// the area occupied by it. The window is selected using `xwininfo`, which
+// must be installed (usually provided by the x11-utils package).
+// The function blocks until a window is selected.
+func PickWindowArea() (Area, error) {
+	output, err := exec.Command("xwininfo").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.Is(err, exec.ErrNotFound) || !errors.As(err, &exitErr) {
+			return Area{}, fmt.Errorf("cannot run xwininfo: %w", err)
+		}
+		return Area{}, fmt.Errorf("no window selected: %s", bytes.TrimSpace(exitErr.Stderr))
+	}
+
+	return ParseWindowInfo(output)
+}
+
+// ParseWindowInfo returns the window area contained in the specified
+// `xwininfo` output.
+func ParseWindowInfo(output []byte) (Area, error) {
+	fields := map[string]*int{}
+
+	var area Area
+	fields["Absolute upper-left X"] = &area.X
+	fields["Absolute upper-left Y"] = &area.Y
+	fields["Width"] = &area.Width
+	fields["Height"] = &area.Height
+
+	var found int
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+
+		field, ok := fields[strings.TrimSpace(key)]
+		if !ok {
+			continue
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return Area{}, fmt.Errorf("invalid window info %q: %w", key, err)
+		}
+		*field = n
+		found++
+	}
+
+	if found < len(fields) || area.IsEmpty() {
+		return Area{}, errors.New("incomplete window info")
+	}
+
+	// Clamp the area to the visible part of the screen.
+	if area.X < 0 {
+		area.Width += area.X
+		area.X = 0
+	}
+	if area.Y < 0 {
+		area.Height += area.Y
+		area.Y = 0
+	}
+
+	return area, nil
+}
@@ -0,0 +1,29 @@
+package screencap
+
+import "testing"
+
+func TestParseWindowInfo(t *testing.T) {
+	output := []byte(`
+xwininfo: Window id: 0x3a00007 "Terminal"
+
+  Absolute upper-left X:  -20
+  Absolute upper-left Y:  40
+  Relative upper-left X:  0
+  Relative upper-left Y:  0
+  Width: 800
+  Height: 600
+  Depth: 24
+`)
+
+	area, err := ParseWindowInfo(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Area{X: 0, Y: 40, Width: 780, Height: 600}); area != want {
+		t.Errorf("got %+v, want %+v", area, want)
+	}
+
+	if _, err := ParseWindowInfo([]byte("Width: 800\nHeight: 600\n")); err == nil {
+		t.Error("expected error for incomplete window info")
+	}
+}
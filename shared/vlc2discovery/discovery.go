@@ -0,0 +1,199 @@
+// Package vlc2discovery provides bindings for the media discoverer API of
+// libVLC 2.2, which libvlc-go v2 does not cover. It is used by the libVLC 2
+// adapter of the vlcompat package and by the media discovery example of the
+// v2 module.
+//
+// libVLC 2.2 cannot list the available media discovery services, so they
+// have to be referenced by name (e.g. upnp, sap, podcast or video_dir).
+// The discoverers use their own libVLC instance, as the instance of
+// libvlc-go v2 is not exported. For this reason, the discovered media items
+// are returned as locations and titles, from which media instances can be
+// created using libvlc-go.
+package vlc2discovery
+
+// #cgo LDFLAGS: -lvlc
+// #cgo CFLAGS: -w
+// #include <vlc/vlc.h>
+// #include <stdlib.h>
+import "C"
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// Item represents a media item found by a media discovery service.
+type Item struct {
+	Location string
+	Title    string
+}
+
+// Discoverer represents a libVLC 2.2 media discovery service.
+type Discoverer struct {
+	name string
+	inst *C.libvlc_instance_t
+	md   *C.libvlc_media_discoverer_t
+}
+
+// New creates and starts the media discovery service with the specified
+// name. The libVLC instance of the service is created using the specified
+// command line arguments (e.g. --quiet).
+func New(name string, args ...string) (*Discoverer, error) {
+	inst, err := newInstance(args)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Discoverer{name: name, inst: inst}
+	if err := d.Start(); err != nil {
+		C.libvlc_release(inst)
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Name returns the name of the media discovery service.
+func (d *Discoverer) Name() string {
+	return d.name
+}
+
+// Release stops the media discovery service and releases its libVLC
+// instance. The discoverer must not be used afterwards.
+func (d *Discoverer) Release() error {
+	if d.inst == nil {
+		return nil
+	}
+
+	d.Stop()
+	C.libvlc_release(d.inst)
+	d.inst = nil
+
+	return nil
+}
+
+// Start starts the media discovery service, if it is not running already.
+// libVLC 2.2 starts media discovery services when they are created, so a
+// new service is created if the previous one was stopped.
+func (d *Discoverer) Start() error {
+	if d.inst == nil {
+		return errors.New("media discoverer is released")
+	}
+	if d.md != nil {
+		return nil
+	}
+
+	cName := C.CString(d.name)
+	defer C.free(unsafe.Pointer(cName))
+
+	if d.md = C.libvlc_media_discoverer_new_from_name(d.inst, cName); d.md == nil {
+		return lastError(fmt.Sprintf("cannot start %s discovery service", d.name))
+	}
+
+	return nil
+}
+
+// Stop stops the media discovery service. libVLC 2.2 services cannot be
+// stopped without releasing them, so their items are discarded.
+func (d *Discoverer) Stop() error {
+	if d.md == nil {
+		return nil
+	}
+
+	C.libvlc_media_discoverer_release(d.md)
+	d.md = nil
+
+	return nil
+}
+
+// IsRunning returns true if the media discovery service is running.
+func (d *Discoverer) IsRunning() bool {
+	return d.md != nil && C.libvlc_media_discoverer_is_running(d.md) != 0
+}
+
+// LocalizedName returns the localized name of the media discovery service
+// (e.g. Universal Plug'n'Play). An empty string is returned if the service
+// is not running or if it does not have a localized name.
+func (d *Discoverer) LocalizedName() string {
+	if d.md == nil {
+		return ""
+	}
+
+	return takeString(C.libvlc_media_discoverer_localized_name(d.md))
+}
+
+// Items returns the top level media items found by the service so far.
+// Stopped services have no items.
+func (d *Discoverer) Items() ([]Item, error) {
+	if d.md == nil {
+		return nil, nil
+	}
+
+	list := C.libvlc_media_discoverer_media_list(d.md)
+	if list == nil {
+		return nil, lastError(fmt.Sprintf("cannot retrieve the media list of %s", d.name))
+	}
+	defer C.libvlc_media_list_release(list)
+
+	C.libvlc_media_list_lock(list)
+	defer C.libvlc_media_list_unlock(list)
+
+	count := int(C.libvlc_media_list_count(list))
+	items := make([]Item, 0, count)
+	for i := 0; i < count; i++ {
+		media := C.libvlc_media_list_item_at_index(list, C.int(i))
+		if media == nil {
+			continue
+		}
+
+		// libVLC 2.2 falls back to the name of the item for missing titles.
+		items = append(items, Item{
+			Location: takeString(C.libvlc_media_get_mrl(media)),
+			Title:    takeString(C.libvlc_media_get_meta(media, C.libvlc_meta_Title)),
+		})
+		C.libvlc_media_release(media)
+	}
+
+	return items, nil
+}
+
+func newInstance(args []string) (*C.libvlc_instance_t, error) {
+	argv := make([]*C.char, 0, len(args))
+	for _, arg := range args {
+		cArg := C.CString(arg)
+		defer C.free(unsafe.Pointer(cArg))
+		argv = append(argv, cArg)
+	}
+
+	var cArgv **C.char
+	if len(argv) > 0 {
+		cArgv = &argv[0]
+	}
+
+	inst := C.libvlc_new(C.int(len(argv)), cArgv)
+	if inst == nil {
+		return nil, lastError("cannot create libVLC instance")
+	}
+
+	return inst, nil
+}
+
+// takeString converts the specified string allocated by libVLC and frees it.
+func takeString(s *C.char) string {
+	if s == nil {
+		return ""
+	}
+	defer C.libvlc_free(unsafe.Pointer(s))
+
+	return C.GoString(s)
+}
+
+// lastError returns an error containing the specified message, along with
+// the last libVLC error message, if any.
+func lastError(msg string) error {
+	if errMsg := C.libvlc_errmsg(); errMsg != nil {
+		return fmt.Errorf("%s: %s", msg, C.GoString(errMsg))
+	}
+
+	return errors.New(msg)
+}
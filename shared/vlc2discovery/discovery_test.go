@@ -0,0 +1,87 @@
+//go:build integration
+
+package vlc2discovery
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiscoverVideoDir(t *testing.T) {
+	// Point the videos directory used by the local discovery service to
+	// an empty directory, which is reported as the only item.
+	dir := t.TempDir()
+	configDir := t.TempDir()
+	userDirs := fmt.Sprintf("XDG_VIDEOS_DIR=%q\n", dir)
+	if err := os.WriteFile(filepath.Join(configDir, "user-dirs.dirs"), []byte(userDirs), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+
+	d, err := New("video_dir", "--quiet")
+	if err != nil {
+		t.Skipf("video_dir discovery service not available: %s", err)
+	}
+	defer d.Release()
+
+	if !d.IsRunning() {
+		t.Error("got stopped service, want running service")
+	}
+
+	waitForItem(t, d, dir, 5*time.Second)
+
+	if err := d.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if d.IsRunning() {
+		t.Error("got running service, want stopped service")
+	}
+	if items, err := d.Items(); err != nil || len(items) != 0 {
+		t.Errorf("got %d items (error %v) for stopped service, want none", len(items), err)
+	}
+
+	// Stopped services can be restarted.
+	if err := d.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if !d.IsRunning() {
+		t.Error("got stopped service after restart, want running service")
+	}
+}
+
+func TestNewUnknownService(t *testing.T) {
+	d, err := New("libvlc-go-unknown-service", "--quiet")
+	if err == nil {
+		d.Release()
+		t.Fatal("got nil error for unknown service, want error")
+	}
+}
+
+// waitForItem waits until the specified service finds an item located at
+// the specified path.
+func waitForItem(t *testing.T, d *Discoverer, path string, timeout time.Duration) {
+	t.Helper()
+
+	for deadline := time.Now().Add(timeout); ; {
+		items, err := d.Items()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var locations []string
+		for _, item := range items {
+			if strings.HasSuffix(strings.TrimSuffix(item.Location, "/"), "/"+filepath.Base(path)) {
+				return
+			}
+			locations = append(locations, item.Location)
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%s not found in item locations %q", path, locations)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,39 @@
+package vlcompat
+
+// DiscoveryCategory represents the category of media discovery services.
+type DiscoveryCategory int
+
+// Media discovery categories.
+const (
+	DiscoveryDevices DiscoveryCategory = iota
+	DiscoveryLAN
+	DiscoveryInternet
+	DiscoveryLocal
+)
+
+// DiscoveryService contains information about a media discovery service.
+type DiscoveryService struct {
+	Name     string
+	LongName string
+	Category DiscoveryCategory
+}
+
+// MediaDiscoverer represents a media discovery service.
+type MediaDiscoverer interface {
+	// Release stops and releases the media discovery service.
+	Release() error
+
+	// Start starts the media discovery service.
+	Start() error
+
+	// Stop stops the media discovery service.
+	Stop() error
+
+	// IsRunning returns true if the media discovery service is running.
+	IsRunning() bool
+
+	// Items returns the top level media items found by the service so
+	// far. The returned media are owned by the service and must not be
+	// released. They must not be used after the service is stopped.
+	Items() ([]Media, error)
+}
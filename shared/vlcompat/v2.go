@@ -0,0 +1,536 @@
+//go:build vlc2
+
+package vlcompat
+
+import (
+	"fmt"
+	"time"
+
+	vlc "github.com/adrg/libvlc-go/v2"
+
+	"github.com/adrg/libvlc-go-examples/shared/vlc2discovery"
+)
+
+// MajorVersion is the major version of libVLC the package is built against.
+const MajorVersion = 2
+
+var events = map[Event]vlc.Event{
+	MediaMetaChanged:            vlc.MediaMetaChanged,
+	MediaDurationChanged:        vlc.MediaDurationChanged,
+	MediaParsedChanged:          vlc.MediaParsedChanged,
+	MediaStateChanged:           vlc.MediaStateChanged,
+	MediaPlayerMediaChanged:     vlc.MediaPlayerMediaChanged,
+	MediaPlayerOpening:          vlc.MediaPlayerOpening,
+	MediaPlayerBuffering:        vlc.MediaPlayerBuffering,
+	MediaPlayerPlaying:          vlc.MediaPlayerPlaying,
+	MediaPlayerPaused:           vlc.MediaPlayerPaused,
+	MediaPlayerStopped:          vlc.MediaPlayerStopped,
+	MediaPlayerEndReached:       vlc.MediaPlayerEndReached,
+	MediaPlayerEncounteredError: vlc.MediaPlayerEncounteredError,
+	MediaPlayerTimeChanged:      vlc.MediaPlayerTimeChanged,
+	MediaPlayerPositionChanged:  vlc.MediaPlayerPositionChanged,
+	MediaPlayerLengthChanged:    vlc.MediaPlayerLengthChanged,
+	MediaListPlayerPlayed:       vlc.MediaListPlayerPlayed,
+	MediaListPlayerNextItemSet:  vlc.MediaListPlayerNextItemSet,
+	MediaListPlayerStopped:      vlc.MediaListPlayerStopped,
+}
+
+var metaKeys = map[MetaKey]vlc.MediaMetaKey{
+	MediaTitle:       vlc.MediaTitle,
+	MediaArtist:      vlc.MediaArtist,
+	MediaAlbum:       vlc.MediaAlbum,
+	MediaGenre:       vlc.MediaGenre,
+	MediaDate:        vlc.MediaDate,
+	MediaTrackNumber: vlc.MediaTrackNumber,
+	MediaDescription: vlc.MediaDescription,
+	MediaArtworkURL:  vlc.MediaArtworkURL,
+}
+
+// initArgs contains the arguments libVLC was initialized with. They are
+// also used for the libVLC instances of the media discovery services.
+var initArgs []string
+
+// Init initializes libVLC using the specified command line arguments.
+func Init(args ...string) error {
+	if err := vlc.Init(args...); err != nil {
+		return err
+	}
+
+	initArgs = args
+	return nil
+}
+
+// Release releases the resources allocated by libVLC.
+func Release() error {
+	return vlc.Release()
+}
+
+type eventManager struct {
+	em *vlc.EventManager
+}
+
+func newEventManager(em *vlc.EventManager, err error) (EventManager, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &eventManager{em: em}, nil
+}
+
+func (em *eventManager) Attach(event Event, callback EventCallback, userData interface{}) (EventID, error) {
+	vlcEvent, ok := events[event]
+	if !ok {
+		return 0, ErrUnsupported
+	}
+
+	id, err := em.em.Attach(vlcEvent, func(_ vlc.Event, userData interface{}) {
+		callback(event, userData)
+	}, userData)
+	return EventID(id), err
+}
+
+func (em *eventManager) Detach(eventIDs ...EventID) {
+	ids := make([]vlc.EventID, 0, len(eventIDs))
+	for _, id := range eventIDs {
+		ids = append(ids, vlc.EventID(id))
+	}
+	em.em.Detach(ids...)
+}
+
+type media struct {
+	m *vlc.Media
+}
+
+func newMedia(m *vlc.Media, err error) (Media, error) {
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, nil
+	}
+	return &media{m: m}, nil
+}
+
+// NewMediaFromPath creates a media instance from the file at the
+// specified path.
+func NewMediaFromPath(path string) (Media, error) {
+	return newMedia(vlc.NewMediaFromPath(path))
+}
+
+// NewMediaFromURL creates a media instance from the specified URL.
+func NewMediaFromURL(url string) (Media, error) {
+	return newMedia(vlc.NewMediaFromURL(url))
+}
+
+// NewMediaFromScreen creates a media instance which captures the screen
+// using the specified options.
+func NewMediaFromScreen(opts *ScreenOptions) (Media, error) {
+	var vlcOpts *vlc.MediaScreenOptions
+	if opts != nil {
+		vlcOpts = &vlc.MediaScreenOptions{
+			X:           opts.X,
+			Y:           opts.Y,
+			Width:       opts.Width,
+			Height:      opts.Height,
+			FPS:         opts.FPS,
+			FollowMouse: opts.FollowMouse,
+		}
+	}
+
+	return newMedia(vlc.NewMediaFromScreen(vlcOpts))
+}
+
+func (m *media) Release() error {
+	return m.m.Release()
+}
+
+func (m *media) AddOptions(options ...string) error {
+	return m.m.AddOptions(options...)
+}
+
+func (m *media) Location() (string, error) {
+	return m.m.Location()
+}
+
+func (m *media) Meta(key MetaKey) (string, error) {
+	vlcKey, ok := metaKeys[key]
+	if !ok {
+		return "", ErrUnsupported
+	}
+	return m.m.Meta(vlcKey)
+}
+
+func (m *media) Type() (MediaType, error) {
+	return MediaTypeUnknown, ErrUnsupported
+}
+
+func (m *media) Duration() (time.Duration, error) {
+	return m.m.Duration()
+}
+
+func (m *media) Stats() (*Stats, error) {
+	stats, err := m.m.Stats()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Stats{
+		ReadBytes:          stats.ReadBytes,
+		InputBitRate:       float64(stats.InputBitRate),
+		DemuxReadBytes:     stats.DemuxReadBytes,
+		DemuxBitRate:       float64(stats.DemuxBitRate),
+		DemuxCorrupted:     stats.DemuxCorrupted,
+		DemuxDiscontinuity: stats.DemuxDiscontinuity,
+		DecodedVideo:       stats.DecodedVideo,
+		DecodedAudio:       stats.DecodedAudio,
+		DisplayedPictures:  stats.DisplayedPictures,
+		LostPictures:       stats.LostPictures,
+		PlayedAudioBuffers: stats.PlayedAudioBuffers,
+		LostAudioBuffers:   stats.LostAudioBuffers,
+	}, nil
+}
+
+func (m *media) Tracks() ([]*MediaTrack, error) {
+	vlcTracks, err := m.m.Tracks()
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := make([]*MediaTrack, 0, len(vlcTracks))
+	for _, vlcTrack := range vlcTracks {
+		track := &MediaTrack{
+			ID:            vlcTrack.ID,
+			Type:          TrackType(vlcTrack.Type),
+			BitRate:       vlcTrack.BitRate,
+			Codec:         vlcTrack.Codec,
+			OriginalCodec: vlcTrack.OriginalCodec,
+			Profile:       vlcTrack.Profile,
+			Level:         vlcTrack.Level,
+			Language:      vlcTrack.Language,
+			Description:   vlcTrack.Description,
+		}
+
+		switch {
+		case vlcTrack.Audio != nil:
+			track.Audio = &AudioTrack{
+				Channels: vlcTrack.Audio.Channels,
+				Rate:     vlcTrack.Audio.Rate,
+			}
+		case vlcTrack.Video != nil:
+			video := vlcTrack.Video
+			track.Video = &VideoTrack{
+				Width:          video.Width,
+				Height:         video.Height,
+				AspectRatioNum: video.AspectRatioNum,
+				AspectRatioDen: video.AspectRatioDen,
+				FrameRateNum:   video.FrameRateNum,
+				FrameRateDen:   video.FrameRateDen,
+			}
+		case vlcTrack.Subtitle != nil:
+			track.Subtitle = &SubtitleTrack{
+				Encoding: vlcTrack.Subtitle.Encoding,
+			}
+		}
+
+		tracks = append(tracks, track)
+	}
+
+	return tracks, nil
+}
+
+// Parse parses the media synchronously. libVLC 2 does not support parse
+// timeouts, so the timeout is ignored.
+func (m *media) Parse(timeout time.Duration) error {
+	return m.m.Parse()
+}
+
+func (m *media) ParseStatus() (ParseStatus, error) {
+	return MediaParseUnstarted, ErrUnsupported
+}
+
+func (m *media) EventManager() (EventManager, error) {
+	return newEventManager(m.m.EventManager())
+}
+
+type player struct {
+	p *vlc.Player
+}
+
+// NewPlayer creates a new media player.
+func NewPlayer() (Player, error) {
+	p, err := vlc.NewPlayer()
+	if err != nil {
+		return nil, err
+	}
+	return &player{p: p}, nil
+}
+
+func (p *player) Release() error {
+	return p.p.Release()
+}
+
+func (p *player) Play() error {
+	return p.p.Play()
+}
+
+func (p *player) Stop() error {
+	return p.p.Stop()
+}
+
+func (p *player) SetPause(pause bool) error {
+	return p.p.SetPause(pause)
+}
+
+func (p *player) IsPlaying() bool {
+	return p.p.IsPlaying()
+}
+
+func (p *player) Media() (Media, error) {
+	return newMedia(p.p.Media())
+}
+
+func (p *player) SetMedia(m Media) error {
+	if m == nil {
+		return p.p.SetMedia(nil)
+	}
+	return p.p.SetMedia(m.(*media).m)
+}
+
+func (p *player) LoadMediaFromPath(path string) (Media, error) {
+	return newMedia(p.p.LoadMediaFromPath(path))
+}
+
+func (p *player) LoadMediaFromURL(url string) (Media, error) {
+	return newMedia(p.p.LoadMediaFromURL(url))
+}
+
+func (p *player) MediaTime() (int, error) {
+	return p.p.MediaTime()
+}
+
+func (p *player) SetEqualizer(e Equalizer) error {
+	if e == nil {
+		return p.p.SetEqualizer(nil)
+	}
+	return p.p.SetEqualizer(e.(*equalizer).e)
+}
+
+func (p *player) EventManager() (EventManager, error) {
+	return newEventManager(p.p.EventManager())
+}
+
+type mediaList struct {
+	l *vlc.MediaList
+}
+
+// NewMediaList creates an empty media list.
+func NewMediaList() (MediaList, error) {
+	l, err := vlc.NewMediaList()
+	if err != nil {
+		return nil, err
+	}
+	return &mediaList{l: l}, nil
+}
+
+func (l *mediaList) Release() error {
+	return l.l.Release()
+}
+
+func (l *mediaList) AddMedia(m Media) error {
+	return l.l.AddMedia(m.(*media).m)
+}
+
+func (l *mediaList) AddMediaFromPath(path string) error {
+	return l.l.AddMediaFromPath(path)
+}
+
+func (l *mediaList) AddMediaFromURL(url string) error {
+	return l.l.AddMediaFromURL(url)
+}
+
+type listPlayer struct {
+	lp *vlc.ListPlayer
+}
+
+// NewListPlayer creates a new list player.
+func NewListPlayer() (ListPlayer, error) {
+	lp, err := vlc.NewListPlayer()
+	if err != nil {
+		return nil, err
+	}
+	return &listPlayer{lp: lp}, nil
+}
+
+func (lp *listPlayer) Release() error {
+	return lp.lp.Release()
+}
+
+func (lp *listPlayer) Play() error {
+	return lp.lp.Play()
+}
+
+func (lp *listPlayer) Stop() error {
+	return lp.lp.Stop()
+}
+
+func (lp *listPlayer) Player() (Player, error) {
+	p, err := lp.lp.Player()
+	if err != nil {
+		return nil, err
+	}
+	return &player{p: p}, nil
+}
+
+func (lp *listPlayer) SetMediaList(l MediaList) error {
+	return lp.lp.SetMediaList(l.(*mediaList).l)
+}
+
+func (lp *listPlayer) EventManager() (EventManager, error) {
+	return newEventManager(lp.lp.EventManager())
+}
+
+type equalizer struct {
+	e *vlc.Equalizer
+}
+
+// NewEqualizer creates a flat equalizer.
+func NewEqualizer() (Equalizer, error) {
+	e, err := vlc.NewEqualizer()
+	if err != nil {
+		return nil, err
+	}
+	return &equalizer{e: e}, nil
+}
+
+// NewEqualizerFromPreset creates an equalizer using the preset at the
+// specified index.
+func NewEqualizerFromPreset(index uint) (Equalizer, error) {
+	e, err := vlc.NewEqualizerFromPreset(index)
+	if err != nil {
+		return nil, err
+	}
+	return &equalizer{e: e}, nil
+}
+
+// EqualizerPresetNames returns the names of the equalizer presets.
+func EqualizerPresetNames() []string {
+	return vlc.EqualizerPresetNames()
+}
+
+// EqualizerBandCount returns the number of equalizer bands.
+func EqualizerBandCount() uint {
+	return vlc.EqualizerBandCount()
+}
+
+// EqualizerBandFrequency returns the frequency of the equalizer band at
+// the specified index, in Hz.
+func EqualizerBandFrequency(index uint) float64 {
+	return vlc.EqualizerBandFrequency(index)
+}
+
+// EqualizerBandFrequencies returns the frequencies of the equalizer
+// bands, in Hz.
+func EqualizerBandFrequencies() []float64 {
+	return vlc.EqualizerBandFrequencies()
+}
+
+func (e *equalizer) Release() error {
+	return e.e.Release()
+}
+
+func (e *equalizer) PreampValue() (float64, error) {
+	return e.e.PreampValue()
+}
+
+func (e *equalizer) SetPreampValue(value float64) error {
+	return e.e.SetPreampValue(value)
+}
+
+func (e *equalizer) AmpValueAtIndex(index uint) (float64, error) {
+	return e.e.AmpValueAtIndex(index)
+}
+
+func (e *equalizer) SetAmpValueAtIndex(value float64, index uint) error {
+	return e.e.SetAmpValueAtIndex(value, index)
+}
+
+// errDiscoveryUnsupported is returned by ListDiscoveryServices, as
+// libVLC 2 cannot list the available media discovery services.
+var errDiscoveryUnsupported = fmt.Errorf("listing media discovery services is %w", ErrUnsupported)
+
+// ListDiscoveryServices returns the media discovery services of the
+// specified category. Always returns ErrUnsupported on libVLC 2.
+func ListDiscoveryServices(category DiscoveryCategory) ([]*DiscoveryService, error) {
+	return nil, errDiscoveryUnsupported
+}
+
+type mediaDiscoverer struct {
+	md    *vlc2discovery.Discoverer
+	media map[string]*media
+}
+
+// NewMediaDiscoverer creates the media discovery service with the
+// specified name. libVLC 2 starts the service when it is created, using
+// the arguments libVLC was initialized with.
+func NewMediaDiscoverer(name string) (MediaDiscoverer, error) {
+	md, err := vlc2discovery.New(name, initArgs...)
+	if err != nil {
+		return nil, err
+	}
+	return &mediaDiscoverer{md: md, media: map[string]*media{}}, nil
+}
+
+func (md *mediaDiscoverer) Release() error {
+	md.releaseMedia()
+	return md.md.Release()
+}
+
+func (md *mediaDiscoverer) Start() error {
+	return md.md.Start()
+}
+
+func (md *mediaDiscoverer) Stop() error {
+	md.releaseMedia()
+	return md.md.Stop()
+}
+
+func (md *mediaDiscoverer) IsRunning() bool {
+	return md.md.IsRunning()
+}
+
+// Items returns the top level media items found by the service so far. The
+// discoverer uses its own libVLC instance, so the media are created from
+// the locations of the items. They are released when the service is
+// stopped.
+func (md *mediaDiscoverer) Items() ([]Media, error) {
+	items, err := md.md.Items()
+	if err != nil {
+		return nil, err
+	}
+
+	mediaItems := make([]Media, 0, len(items))
+	for _, item := range items {
+		m, ok := md.media[item.Location]
+		if !ok {
+			vlcMedia, err := vlc.NewMediaFromURL(item.Location)
+			if err != nil {
+				return nil, err
+			}
+			if item.Title != "" {
+				vlcMedia.SetMeta(vlc.MediaTitle, item.Title)
+			}
+
+			m = &media{m: vlcMedia}
+			md.media[item.Location] = m
+		}
+		mediaItems = append(mediaItems, m)
+	}
+
+	return mediaItems, nil
+}
+
+func (md *mediaDiscoverer) releaseMedia() {
+	for location, m := range md.media {
+		m.Release()
+		delete(md.media, location)
+	}
+}
@@ -0,0 +1,21 @@
+//go:build vlc2
+
+package vlcompat
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUnsupported(t *testing.T) {
+	m := &media{}
+	if _, err := m.Type(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Type: got error %v, want %v", err, ErrUnsupported)
+	}
+	if _, err := m.ParseStatus(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("ParseStatus: got error %v, want %v", err, ErrUnsupported)
+	}
+	if _, err := ListDiscoveryServices(DiscoveryLAN); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("ListDiscoveryServices: got error %v, want %v", err, ErrUnsupported)
+	}
+}
@@ -0,0 +1,590 @@
+//go:build !vlc2
+
+package vlcompat
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+)
+
+// MajorVersion is the major version of libVLC the package is built against.
+const MajorVersion = 3
+
+var events = map[Event]vlc.Event{
+	MediaMetaChanged:            vlc.MediaMetaChanged,
+	MediaDurationChanged:        vlc.MediaDurationChanged,
+	MediaParsedChanged:          vlc.MediaParsedChanged,
+	MediaStateChanged:           vlc.MediaStateChanged,
+	MediaPlayerMediaChanged:     vlc.MediaPlayerMediaChanged,
+	MediaPlayerOpening:          vlc.MediaPlayerOpening,
+	MediaPlayerBuffering:        vlc.MediaPlayerBuffering,
+	MediaPlayerPlaying:          vlc.MediaPlayerPlaying,
+	MediaPlayerPaused:           vlc.MediaPlayerPaused,
+	MediaPlayerStopped:          vlc.MediaPlayerStopped,
+	MediaPlayerEndReached:       vlc.MediaPlayerEndReached,
+	MediaPlayerEncounteredError: vlc.MediaPlayerEncounteredError,
+	MediaPlayerTimeChanged:      vlc.MediaPlayerTimeChanged,
+	MediaPlayerPositionChanged:  vlc.MediaPlayerPositionChanged,
+	MediaPlayerLengthChanged:    vlc.MediaPlayerLengthChanged,
+	MediaListPlayerPlayed:       vlc.MediaListPlayerPlayed,
+	MediaListPlayerNextItemSet:  vlc.MediaListPlayerNextItemSet,
+	MediaListPlayerStopped:      vlc.MediaListPlayerStopped,
+}
+
+var metaKeys = map[MetaKey]vlc.MediaMetaKey{
+	MediaTitle:       vlc.MediaTitle,
+	MediaArtist:      vlc.MediaArtist,
+	MediaAlbum:       vlc.MediaAlbum,
+	MediaAlbumArtist: vlc.MediaAlbumArtist,
+	MediaGenre:       vlc.MediaGenre,
+	MediaDate:        vlc.MediaDate,
+	MediaTrackNumber: vlc.MediaTrackNumber,
+	MediaDescription: vlc.MediaDescription,
+	MediaArtworkURL:  vlc.MediaArtworkURL,
+}
+
+// Init initializes libVLC using the specified command line arguments.
+func Init(args ...string) error {
+	return vlc.Init(args...)
+}
+
+// Release releases the resources allocated by libVLC.
+func Release() error {
+	return vlc.Release()
+}
+
+type eventManager struct {
+	em *vlc.EventManager
+}
+
+func newEventManager(em *vlc.EventManager, err error) (EventManager, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &eventManager{em: em}, nil
+}
+
+func (em *eventManager) Attach(event Event, callback EventCallback, userData interface{}) (EventID, error) {
+	vlcEvent, ok := events[event]
+	if !ok {
+		return 0, ErrUnsupported
+	}
+
+	id, err := em.em.Attach(vlcEvent, func(_ vlc.Event, userData interface{}) {
+		callback(event, userData)
+	}, userData)
+	return EventID(id), err
+}
+
+func (em *eventManager) Detach(eventIDs ...EventID) {
+	ids := make([]vlc.EventID, 0, len(eventIDs))
+	for _, id := range eventIDs {
+		ids = append(ids, vlc.EventID(id))
+	}
+	em.em.Detach(ids...)
+}
+
+type media struct {
+	m *vlc.Media
+}
+
+func newMedia(m *vlc.Media, err error) (Media, error) {
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, nil
+	}
+	return &media{m: m}, nil
+}
+
+// NewMediaFromPath creates a media instance from the file at the
+// specified path.
+func NewMediaFromPath(path string) (Media, error) {
+	return newMedia(vlc.NewMediaFromPath(path))
+}
+
+// NewMediaFromURL creates a media instance from the specified URL.
+func NewMediaFromURL(url string) (Media, error) {
+	return newMedia(vlc.NewMediaFromURL(url))
+}
+
+// NewMediaFromScreen creates a media instance which captures the screen
+// using the specified options.
+func NewMediaFromScreen(opts *ScreenOptions) (Media, error) {
+	var vlcOpts *vlc.MediaScreenOptions
+	if opts != nil {
+		vlcOpts = &vlc.MediaScreenOptions{
+			X:           opts.X,
+			Y:           opts.Y,
+			Width:       opts.Width,
+			Height:      opts.Height,
+			FPS:         opts.FPS,
+			FollowMouse: opts.FollowMouse,
+		}
+	}
+
+	return newMedia(vlc.NewMediaFromScreen(vlcOpts))
+}
+
+func (m *media) Release() error {
+	return m.m.Release()
+}
+
+func (m *media) AddOptions(options ...string) error {
+	return m.m.AddOptions(options...)
+}
+
+func (m *media) Location() (string, error) {
+	return m.m.Location()
+}
+
+func (m *media) Meta(key MetaKey) (string, error) {
+	vlcKey, ok := metaKeys[key]
+	if !ok {
+		return "", ErrUnsupported
+	}
+	return m.m.Meta(vlcKey)
+}
+
+func (m *media) Type() (MediaType, error) {
+	mediaType, err := m.m.Type()
+	if err != nil {
+		return MediaTypeUnknown, err
+	}
+	return MediaType(mediaType), nil
+}
+
+func (m *media) Duration() (time.Duration, error) {
+	return m.m.Duration()
+}
+
+func (m *media) Stats() (*Stats, error) {
+	stats, err := m.m.Stats()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Stats{
+		ReadBytes:          stats.ReadBytes,
+		InputBitRate:       float64(stats.InputBitRate),
+		DemuxReadBytes:     stats.DemuxReadBytes,
+		DemuxBitRate:       float64(stats.DemuxBitRate),
+		DemuxCorrupted:     stats.DemuxCorrupted,
+		DemuxDiscontinuity: stats.DemuxDiscontinuity,
+		DecodedVideo:       stats.DecodedVideo,
+		DecodedAudio:       stats.DecodedAudio,
+		DisplayedPictures:  stats.DisplayedPictures,
+		LostPictures:       stats.LostPictures,
+		PlayedAudioBuffers: stats.PlayedAudioBuffers,
+		LostAudioBuffers:   stats.LostAudioBuffers,
+	}, nil
+}
+
+func (m *media) Tracks() ([]*MediaTrack, error) {
+	vlcTracks, err := m.m.Tracks()
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := make([]*MediaTrack, 0, len(vlcTracks))
+	for _, vlcTrack := range vlcTracks {
+		codecDesc, err := vlcTrack.CodecDescription()
+		if err != nil {
+			return nil, err
+		}
+
+		track := &MediaTrack{
+			ID:               vlcTrack.ID,
+			Type:             TrackType(vlcTrack.Type),
+			BitRate:          vlcTrack.BitRate,
+			Codec:            vlcTrack.Codec,
+			OriginalCodec:    vlcTrack.OriginalCodec,
+			Profile:          vlcTrack.Profile,
+			Level:            vlcTrack.Level,
+			Language:         vlcTrack.Language,
+			Description:      vlcTrack.Description,
+			CodecDescription: codecDesc,
+		}
+
+		switch {
+		case vlcTrack.Audio != nil:
+			track.Audio = &AudioTrack{
+				Channels: vlcTrack.Audio.Channels,
+				Rate:     vlcTrack.Audio.Rate,
+			}
+		case vlcTrack.Video != nil:
+			video := vlcTrack.Video
+			track.Video = &VideoTrack{
+				Width:          video.Width,
+				Height:         video.Height,
+				AspectRatioNum: video.AspectRatioNum,
+				AspectRatioDen: video.AspectRatioDen,
+				FrameRateNum:   video.FrameRateNum,
+				FrameRateDen:   video.FrameRateDen,
+				Orientation:    uint(video.Orientation),
+				Projection:     uint(video.Projection),
+				Viewpoint: &Viewpoint{
+					Yaw:   float64(video.Pose.Yaw),
+					Pitch: float64(video.Pose.Pitch),
+					Roll:  float64(video.Pose.Roll),
+					FOV:   float64(video.Pose.FOV),
+				},
+			}
+		case vlcTrack.Subtitle != nil:
+			track.Subtitle = &SubtitleTrack{
+				Encoding: vlcTrack.Subtitle.Encoding,
+			}
+		}
+
+		tracks = append(tracks, track)
+	}
+
+	return tracks, nil
+}
+
+func (m *media) Parse(timeout time.Duration) error {
+	em, err := m.m.EventManager()
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{}, 1)
+	eventID, err := em.Attach(vlc.MediaParsedChanged, func(vlc.Event, interface{}) {
+		select {
+		case done <- struct{}{}:
+		default:
+		}
+	}, nil)
+	if err != nil {
+		return err
+	}
+	defer em.Detach(eventID)
+
+	if err := m.m.ParseWithOptions(int(timeout.Milliseconds()), vlc.MediaParseLocal); err != nil {
+		return err
+	}
+
+	select {
+	case <-done:
+	case <-time.After(timeout + time.Second):
+		m.m.StopParse()
+		return ErrParseTimeout
+	}
+
+	status, err := m.ParseStatus()
+	if err != nil {
+		return err
+	}
+
+	switch status {
+	case MediaParseDone:
+		return nil
+	case MediaParseTimeout:
+		return ErrParseTimeout
+	default:
+		return errors.New("could not parse media")
+	}
+}
+
+func (m *media) ParseStatus() (ParseStatus, error) {
+	status, err := m.m.ParseStatus()
+	if err != nil {
+		return MediaParseUnstarted, err
+	}
+	return ParseStatus(status), nil
+}
+
+func (m *media) EventManager() (EventManager, error) {
+	return newEventManager(m.m.EventManager())
+}
+
+type player struct {
+	p *vlc.Player
+}
+
+// NewPlayer creates a new media player.
+func NewPlayer() (Player, error) {
+	p, err := vlc.NewPlayer()
+	if err != nil {
+		return nil, err
+	}
+	return &player{p: p}, nil
+}
+
+func (p *player) Release() error {
+	return p.p.Release()
+}
+
+func (p *player) Play() error {
+	return p.p.Play()
+}
+
+func (p *player) Stop() error {
+	return p.p.Stop()
+}
+
+func (p *player) SetPause(pause bool) error {
+	return p.p.SetPause(pause)
+}
+
+func (p *player) IsPlaying() bool {
+	return p.p.IsPlaying()
+}
+
+func (p *player) Media() (Media, error) {
+	return newMedia(p.p.Media())
+}
+
+func (p *player) SetMedia(m Media) error {
+	if m == nil {
+		return p.p.SetMedia(nil)
+	}
+	return p.p.SetMedia(m.(*media).m)
+}
+
+func (p *player) LoadMediaFromPath(path string) (Media, error) {
+	return newMedia(p.p.LoadMediaFromPath(path))
+}
+
+func (p *player) LoadMediaFromURL(url string) (Media, error) {
+	return newMedia(p.p.LoadMediaFromURL(url))
+}
+
+func (p *player) MediaTime() (int, error) {
+	return p.p.MediaTime()
+}
+
+func (p *player) SetEqualizer(e Equalizer) error {
+	if e == nil {
+		return p.p.SetEqualizer(nil)
+	}
+	return p.p.SetEqualizer(e.(*equalizer).e)
+}
+
+func (p *player) EventManager() (EventManager, error) {
+	return newEventManager(p.p.EventManager())
+}
+
+type mediaList struct {
+	l *vlc.MediaList
+}
+
+// NewMediaList creates an empty media list.
+func NewMediaList() (MediaList, error) {
+	l, err := vlc.NewMediaList()
+	if err != nil {
+		return nil, err
+	}
+	return &mediaList{l: l}, nil
+}
+
+func (l *mediaList) Release() error {
+	return l.l.Release()
+}
+
+func (l *mediaList) AddMedia(m Media) error {
+	return l.l.AddMedia(m.(*media).m)
+}
+
+func (l *mediaList) AddMediaFromPath(path string) error {
+	return l.l.AddMediaFromPath(path)
+}
+
+func (l *mediaList) AddMediaFromURL(url string) error {
+	return l.l.AddMediaFromURL(url)
+}
+
+type listPlayer struct {
+	lp *vlc.ListPlayer
+}
+
+// NewListPlayer creates a new list player.
+func NewListPlayer() (ListPlayer, error) {
+	lp, err := vlc.NewListPlayer()
+	if err != nil {
+		return nil, err
+	}
+	return &listPlayer{lp: lp}, nil
+}
+
+func (lp *listPlayer) Release() error {
+	return lp.lp.Release()
+}
+
+func (lp *listPlayer) Play() error {
+	return lp.lp.Play()
+}
+
+func (lp *listPlayer) Stop() error {
+	return lp.lp.Stop()
+}
+
+func (lp *listPlayer) Player() (Player, error) {
+	p, err := lp.lp.Player()
+	if err != nil {
+		return nil, err
+	}
+	return &player{p: p}, nil
+}
+
+func (lp *listPlayer) SetMediaList(l MediaList) error {
+	return lp.lp.SetMediaList(l.(*mediaList).l)
+}
+
+func (lp *listPlayer) EventManager() (EventManager, error) {
+	return newEventManager(lp.lp.EventManager())
+}
+
+type equalizer struct {
+	e *vlc.Equalizer
+}
+
+// NewEqualizer creates a flat equalizer.
+func NewEqualizer() (Equalizer, error) {
+	e, err := vlc.NewEqualizer()
+	if err != nil {
+		return nil, err
+	}
+	return &equalizer{e: e}, nil
+}
+
+// NewEqualizerFromPreset creates an equalizer using the preset at the
+// specified index.
+func NewEqualizerFromPreset(index uint) (Equalizer, error) {
+	e, err := vlc.NewEqualizerFromPreset(index)
+	if err != nil {
+		return nil, err
+	}
+	return &equalizer{e: e}, nil
+}
+
+// EqualizerPresetNames returns the names of the equalizer presets.
+func EqualizerPresetNames() []string {
+	return vlc.EqualizerPresetNames()
+}
+
+// EqualizerBandCount returns the number of equalizer bands.
+func EqualizerBandCount() uint {
+	return vlc.EqualizerBandCount()
+}
+
+// EqualizerBandFrequency returns the frequency of the equalizer band at
+// the specified index, in Hz.
+func EqualizerBandFrequency(index uint) float64 {
+	return vlc.EqualizerBandFrequency(index)
+}
+
+// EqualizerBandFrequencies returns the frequencies of the equalizer
+// bands, in Hz.
+func EqualizerBandFrequencies() []float64 {
+	return vlc.EqualizerBandFrequencies()
+}
+
+func (e *equalizer) Release() error {
+	return e.e.Release()
+}
+
+func (e *equalizer) PreampValue() (float64, error) {
+	return e.e.PreampValue()
+}
+
+func (e *equalizer) SetPreampValue(value float64) error {
+	return e.e.SetPreampValue(value)
+}
+
+func (e *equalizer) AmpValueAtIndex(index uint) (float64, error) {
+	return e.e.AmpValueAtIndex(index)
+}
+
+func (e *equalizer) SetAmpValueAtIndex(value float64, index uint) error {
+	return e.e.SetAmpValueAtIndex(value, index)
+}
+
+var discoveryCategories = map[DiscoveryCategory]vlc.MediaDiscoveryCategory{
+	DiscoveryDevices:  vlc.MediaDiscoveryDevices,
+	DiscoveryLAN:      vlc.MediaDiscoveryLAN,
+	DiscoveryInternet: vlc.MediaDiscoveryInternet,
+	DiscoveryLocal:    vlc.MediaDiscoveryLocal,
+}
+
+// ListDiscoveryServices returns the media discovery services of the
+// specified category.
+func ListDiscoveryServices(category DiscoveryCategory) ([]*DiscoveryService, error) {
+	vlcCategory, ok := discoveryCategories[category]
+	if !ok {
+		return nil, fmt.Errorf("unknown media discovery category %d", category)
+	}
+
+	descriptors, err := vlc.ListMediaDiscoverers(vlcCategory)
+	if err != nil {
+		return nil, err
+	}
+
+	services := make([]*DiscoveryService, 0, len(descriptors))
+	for _, descriptor := range descriptors {
+		services = append(services, &DiscoveryService{
+			Name:     descriptor.Name,
+			LongName: descriptor.LongName,
+			Category: category,
+		})
+	}
+
+	return services, nil
+}
+
+type mediaDiscoverer struct {
+	md *vlc.MediaDiscoverer
+}
+
+// NewMediaDiscoverer creates the media discovery service with the
+// specified name. The service must be started in order to find media.
+func NewMediaDiscoverer(name string) (MediaDiscoverer, error) {
+	md, err := vlc.NewMediaDiscoverer(name)
+	if err != nil {
+		return nil, err
+	}
+	return &mediaDiscoverer{md: md}, nil
+}
+
+func (md *mediaDiscoverer) Release() error {
+	return md.md.Release()
+}
+
+func (md *mediaDiscoverer) Start() error {
+	return md.md.Start(func(vlc.Event, *vlc.Media, int) {})
+}
+
+func (md *mediaDiscoverer) Stop() error {
+	return md.md.Stop()
+}
+
+func (md *mediaDiscoverer) IsRunning() bool {
+	return md.md.IsRunning()
+}
+
+func (md *mediaDiscoverer) Items() ([]Media, error) {
+	list, err := md.md.MediaList()
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := list.Count()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Media, 0, count)
+	for i := 0; i < count; i++ {
+		m, err := list.MediaAtIndex(uint(i))
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, &media{m: m})
+	}
+
+	return items, nil
+}
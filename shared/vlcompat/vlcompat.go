@@ -0,0 +1,354 @@
+// Package vlcompat provides a common interface over the players, media and
+// event managers of libvlc-go v2 (libVLC 2.x) and libvlc-go v3 (libVLC 3.x),
+// so that the examples which do not depend on version specific features
+// are written once.
+//
+// The libvlc-go v3 adapter is used by default. Build with the vlc2 tag in
+// order to use the libvlc-go v2 adapter instead:
+//
+//	go build -tags vlc2 ./...
+//
+// Features which are not available in libVLC 2 return ErrUnsupported when
+// using the v2 adapter, so callers can degrade gracefully.
+package vlcompat
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrUnsupported is returned when using features which are not available
+// in the libVLC version the package is built against.
+var ErrUnsupported = errors.New("unsupported on libVLC 2")
+
+// ErrParseTimeout is returned when media parsing does not finish within
+// the specified timeout.
+var ErrParseTimeout = errors.New("parse timeout exceeded")
+
+// Event represents an event emitted by libVLC objects.
+type Event int
+
+// Events emitted by media instances.
+const (
+	MediaMetaChanged Event = iota
+	MediaDurationChanged
+	MediaParsedChanged
+	MediaStateChanged
+)
+
+// Events emitted by players.
+const (
+	MediaPlayerMediaChanged Event = iota + 100
+	MediaPlayerOpening
+	MediaPlayerBuffering
+	MediaPlayerPlaying
+	MediaPlayerPaused
+	MediaPlayerStopped
+	MediaPlayerEndReached
+	MediaPlayerEncounteredError
+	MediaPlayerTimeChanged
+	MediaPlayerPositionChanged
+	MediaPlayerLengthChanged
+)
+
+// Events emitted by list players.
+const (
+	MediaListPlayerPlayed Event = iota + 200
+	MediaListPlayerNextItemSet
+	MediaListPlayerStopped
+)
+
+// EventID uniquely identifies an event attached to an event manager.
+type EventID uint64
+
+// EventCallback is called when an event is emitted. The user data is the
+// value provided when the event was attached.
+type EventCallback func(event Event, userData interface{})
+
+// EventManager attaches and detaches event callbacks.
+type EventManager interface {
+	// Attach registers the callback for the specified event. The callback
+	// is called on a libVLC thread, so it must not block.
+	Attach(event Event, callback EventCallback, userData interface{}) (EventID, error)
+
+	// Detach unregisters the events with the specified IDs.
+	Detach(eventIDs ...EventID)
+}
+
+// MetaKey identifies a media metadata field.
+type MetaKey int
+
+// Media metadata fields.
+const (
+	MediaTitle MetaKey = iota
+	MediaArtist
+	MediaAlbum
+	MediaAlbumArtist // libVLC 3 only.
+	MediaGenre
+	MediaDate
+	MediaTrackNumber
+	MediaDescription
+	MediaArtworkURL
+)
+
+// ParseStatus represents the parsing status of a media instance.
+type ParseStatus int
+
+// Parsing statuses.
+const (
+	MediaParseUnstarted ParseStatus = iota
+	MediaParseSkipped
+	MediaParseFailed
+	MediaParseTimeout
+	MediaParseDone
+)
+
+// MediaType represents the type of a media instance.
+type MediaType int
+
+// Media types.
+const (
+	MediaTypeUnknown MediaType = iota
+	MediaTypeFile
+	MediaTypeDirectory
+	MediaTypeDisc
+	MediaTypeStream
+	MediaTypePlaylist
+)
+
+// Stats contains playback statistics of a media instance.
+type Stats struct {
+	ReadBytes          int
+	InputBitRate       float64
+	DemuxReadBytes     int
+	DemuxBitRate       float64
+	DemuxCorrupted     int
+	DemuxDiscontinuity int
+	DecodedVideo       int
+	DecodedAudio       int
+	DisplayedPictures  int
+	LostPictures       int
+	PlayedAudioBuffers int
+	LostAudioBuffers   int
+}
+
+// TrackType represents the type of a media track.
+type TrackType int
+
+// Media track types.
+const (
+	MediaTrackUnknown TrackType = iota - 1
+	MediaTrackAudio
+	MediaTrackVideo
+	MediaTrackText
+)
+
+// AudioTrack contains the properties of audio tracks.
+type AudioTrack struct {
+	Channels uint
+	Rate     uint
+}
+
+// VideoTrack contains the properties of video tracks.
+type VideoTrack struct {
+	Width          uint
+	Height         uint
+	AspectRatioNum uint
+	AspectRatioDen uint
+	FrameRateNum   uint
+	FrameRateDen   uint
+
+	// Orientation, projection and viewpoint of the video. Available on
+	// libVLC 3 only, so Viewpoint is nil on libVLC 2.
+	Orientation uint
+	Projection  uint
+	Viewpoint   *Viewpoint
+}
+
+// Viewpoint represents the viewpoint of 360° videos.
+type Viewpoint struct {
+	Yaw   float64
+	Pitch float64
+	Roll  float64
+	FOV   float64
+}
+
+// SubtitleTrack contains the properties of subtitle tracks.
+type SubtitleTrack struct {
+	Encoding string
+}
+
+// MediaTrack contains information about a media track.
+type MediaTrack struct {
+	ID            int
+	Type          TrackType
+	BitRate       uint
+	Codec         uint
+	OriginalCodec uint
+	Profile       int
+	Level         int
+	Language      string
+	Description   string
+
+	// CodecDescription is the human readable description of the codec.
+	// Available on libVLC 3 only.
+	CodecDescription string
+
+	// Type specific properties. Only the field matching the track type
+	// is set.
+	Audio    *AudioTrack
+	Video    *VideoTrack
+	Subtitle *SubtitleTrack
+}
+
+// ScreenOptions contains the options used to create media instances which
+// capture the screen.
+type ScreenOptions struct {
+	X           int
+	Y           int
+	Width       int
+	Height      int
+	FPS         float64
+	FollowMouse bool
+}
+
+// Media represents a media instance.
+type Media interface {
+	// Release releases the media instance.
+	Release() error
+
+	// AddOptions adds libVLC media options (e.g. :no-video).
+	AddOptions(options ...string) error
+
+	// Location returns the location (MRL) of the media.
+	Location() (string, error)
+
+	// Meta returns the value of the specified metadata field.
+	Meta(key MetaKey) (string, error)
+
+	// Type returns the type of the media. Returns ErrUnsupported on
+	// libVLC 2.
+	Type() (MediaType, error)
+
+	// Duration returns the duration of the media. The media must be parsed
+	// or played in order for the duration to be known.
+	Duration() (time.Duration, error)
+
+	// Stats returns the playback statistics of the media.
+	Stats() (*Stats, error)
+
+	// Tracks returns the tracks of the media. The media must be parsed
+	// or played in order for the tracks to be known.
+	Tracks() ([]*MediaTrack, error)
+
+	// Parse parses the media synchronously, fetching local metadata. On
+	// libVLC 3, parsing fails if it does not finish within the specified
+	// timeout. On libVLC 2, the timeout is not enforced.
+	Parse(timeout time.Duration) error
+
+	// ParseStatus returns the parsing status of the media. Returns
+	// ErrUnsupported on libVLC 2.
+	ParseStatus() (ParseStatus, error)
+
+	// EventManager returns the event manager of the media.
+	EventManager() (EventManager, error)
+}
+
+// Player represents a media player.
+type Player interface {
+	// Release stops the playback and releases the player.
+	Release() error
+
+	// Play starts the playback of the current media.
+	Play() error
+
+	// Stop stops the playback.
+	Stop() error
+
+	// SetPause pauses or resumes the playback.
+	SetPause(pause bool) error
+
+	// IsPlaying returns true if the player is playing media.
+	IsPlaying() bool
+
+	// Media returns the current media of the player. The returned media
+	// is owned by the player and must not be released.
+	Media() (Media, error)
+
+	// SetMedia sets the current media of the player.
+	SetMedia(media Media) error
+
+	// LoadMediaFromPath sets the current media of the player from the file
+	// at the specified path. The returned media must be released.
+	LoadMediaFromPath(path string) (Media, error)
+
+	// LoadMediaFromURL sets the current media of the player from the
+	// specified URL. The returned media must be released.
+	LoadMediaFromURL(url string) (Media, error)
+
+	// MediaTime returns the playback time, in milliseconds.
+	MediaTime() (int, error)
+
+	// SetEqualizer sets the equalizer of the player. A nil equalizer
+	// disables equalization.
+	SetEqualizer(equalizer Equalizer) error
+
+	// EventManager returns the event manager of the player.
+	EventManager() (EventManager, error)
+}
+
+// MediaList represents a list of media instances.
+type MediaList interface {
+	// Release releases the media list.
+	Release() error
+
+	// AddMedia adds the specified media to the list.
+	AddMedia(media Media) error
+
+	// AddMediaFromPath adds the media file at the specified path to the list.
+	AddMediaFromPath(path string) error
+
+	// AddMediaFromURL adds the media at the specified URL to the list.
+	AddMediaFromURL(url string) error
+}
+
+// ListPlayer represents a player which plays the media of a media list.
+type ListPlayer interface {
+	// Release stops the playback and releases the list player.
+	Release() error
+
+	// Play starts the playback of the media list.
+	Play() error
+
+	// Stop stops the playback.
+	Stop() error
+
+	// Player returns the underlying player of the list player.
+	Player() (Player, error)
+
+	// SetMediaList sets the media list played by the list player.
+	SetMediaList(list MediaList) error
+
+	// EventManager returns the event manager of the list player.
+	EventManager() (EventManager, error)
+}
+
+// Equalizer represents an audio equalizer.
+type Equalizer interface {
+	// Release releases the equalizer.
+	Release() error
+
+	// PreampValue returns the preamplification value, in dB.
+	PreampValue() (float64, error)
+
+	// SetPreampValue sets the preamplification value, in dB.
+	SetPreampValue(value float64) error
+
+	// AmpValueAtIndex returns the amplification value of the band at the
+	// specified index, in dB.
+	AmpValueAtIndex(index uint) (float64, error)
+
+	// SetAmpValueAtIndex sets the amplification value of the band at the
+	// specified index, in dB.
+	SetAmpValueAtIndex(value float64, index uint) error
+}
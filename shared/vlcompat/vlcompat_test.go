@@ -0,0 +1,83 @@
+package vlcompat
+
+import (
+	"errors"
+	"testing"
+)
+
+var allEvents = []Event{
+	MediaMetaChanged,
+	MediaDurationChanged,
+	MediaParsedChanged,
+	MediaStateChanged,
+	MediaPlayerMediaChanged,
+	MediaPlayerOpening,
+	MediaPlayerBuffering,
+	MediaPlayerPlaying,
+	MediaPlayerPaused,
+	MediaPlayerStopped,
+	MediaPlayerEndReached,
+	MediaPlayerEncounteredError,
+	MediaPlayerTimeChanged,
+	MediaPlayerPositionChanged,
+	MediaPlayerLengthChanged,
+	MediaListPlayerPlayed,
+	MediaListPlayerNextItemSet,
+	MediaListPlayerStopped,
+}
+
+func TestEvents(t *testing.T) {
+	for _, event := range allEvents {
+		if _, ok := events[event]; !ok {
+			t.Errorf("event %d is not mapped to a libVLC %d event", event, MajorVersion)
+		}
+	}
+	if len(events) != len(allEvents) {
+		t.Errorf("got %d mapped events, want %d", len(events), len(allEvents))
+	}
+
+	// Mapped libVLC events must be distinct.
+	seen := map[interface{}]Event{}
+	for event, vlcEvent := range events {
+		if prev, ok := seen[vlcEvent]; ok {
+			t.Errorf("events %d and %d map to the same libVLC event", prev, event)
+		}
+		seen[vlcEvent] = event
+	}
+}
+
+func TestAttachUnsupportedEvent(t *testing.T) {
+	em := &eventManager{}
+	if _, err := em.Attach(Event(-1), func(Event, interface{}) {}, nil); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("got error %v, want %v", err, ErrUnsupported)
+	}
+}
+
+func TestMetaKeys(t *testing.T) {
+	keys := []MetaKey{
+		MediaTitle,
+		MediaArtist,
+		MediaAlbum,
+		MediaAlbumArtist,
+		MediaGenre,
+		MediaDate,
+		MediaTrackNumber,
+		MediaDescription,
+		MediaArtworkURL,
+	}
+
+	for _, key := range keys {
+		_, ok := metaKeys[key]
+
+		// Album artists are only available on libVLC 3.
+		want := key != MediaAlbumArtist || MajorVersion >= 3
+		if ok != want {
+			t.Errorf("meta key %d: got supported %t, want %t", key, ok, want)
+		}
+	}
+
+	m := &media{}
+	if _, err := m.Meta(MetaKey(-1)); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("got error %v, want %v", err, ErrUnsupported)
+	}
+}
@@ -1,3 +1,5 @@
+//go:build gtk2
+
 package main
 
 /*
@@ -15,6 +17,8 @@ import (
 	"github.com/mattn/go-gtk/gdk"
 	"github.com/mattn/go-gtk/glib"
 	"github.com/mattn/go-gtk/gtk"
+
+	"github.com/adrg/libvlc-go-examples/shared/screencap"
 )
 
 func main() {
@@ -136,6 +140,42 @@ func main() {
 	recordingSpacer.PackStart(recordingFrame, true, true, 10)
 	container.PackStart(recordingSpacer, true, true, 10)
 
+	// Create audio options layout.
+	audioSources := screencap.ListAudioSources()
+	audioSourceCombo := gtk.NewComboBoxText()
+	for _, source := range audioSources {
+		audioSourceCombo.AppendText(source.Name)
+	}
+	audioSourceCombo.SetActive(0)
+
+	audioCodecCombo := gtk.NewComboBoxText()
+	for _, codec := range screencap.AudioCodecs {
+		audioCodecCombo.AppendText(codec.Name)
+	}
+	audioCodecCombo.SetActive(0)
+
+	audioOptsBox := gtk.NewHBox(false, 0)
+	audioOptsBox.SetSensitive(false)
+	audioOptsBox.PackStart(audioSourceCombo, true, true, 10)
+	audioOptsBox.PackStart(audioCodecCombo, false, false, 10)
+
+	captureAudioCheck := gtk.NewCheckButtonWithLabel("Capture audio")
+	captureAudioCheck.Connect("toggled", func(ctx *glib.CallbackContext) {
+		audioOptsBox.SetSensitive(captureAudioCheck.GetActive())
+	})
+
+	audioBox := gtk.NewHBox(false, 0)
+	audioBox.PackStart(captureAudioCheck, false, false, 10)
+	audioBox.PackStart(audioOptsBox, true, true, 0)
+	audioBoxSpacer := gtk.NewVBox(false, 0)
+	audioBoxSpacer.PackStart(audioBox, true, true, 10)
+
+	audioFrame := gtk.NewFrame("Audio")
+	audioFrame.Add(audioBoxSpacer)
+	audioSpacer := gtk.NewHBox(false, 0)
+	audioSpacer.PackStart(audioFrame, true, true, 10)
+	container.PackStart(audioSpacer, true, true, 10)
+
 	// Create destination file layout.
 	destInput := gtk.NewEntry()
 	destInput.SetEditable(false)
@@ -181,6 +221,7 @@ func main() {
 			recordButton.SetLabel("gtk-media-record")
 			areaFrame.SetSensitive(true)
 			recordingFrame.SetSensitive(true)
+			audioFrame.SetSensitive(true)
 			destFrame.SetSensitive(true)
 			player.Stop()
 			return
@@ -212,8 +253,25 @@ func main() {
 			log.Fatalf("Cannot load screen media: %s\n", err)
 		}
 
+		// Configure audio capture. The audio source is added as a slave
+		// input of the screen media.
+		transcodeOpts := "vcodec=h264,vb=0,scale=1"
+		if captureAudioCheck.GetActive() {
+			if idx := audioSourceCombo.GetActive(); idx >= 0 && idx < len(audioSources) {
+				if err := media.AddOptions(":input-slave=" + audioSources[idx].MRL); err != nil {
+					log.Fatalf("Cannot add media options: %s\n", err)
+				}
+
+				codec := screencap.AudioCodecs[0]
+				if idx := audioCodecCombo.GetActive(); idx >= 0 && idx < len(screencap.AudioCodecs) {
+					codec = screencap.AudioCodecs[idx]
+				}
+				transcodeOpts += fmt.Sprintf(",acodec=%s,ab=%d,channels=2,samplerate=44100", codec.Codec, codec.Bitrate)
+			}
+		}
+
 		// Configure media to save the recording to the selected destination path.
-		saveOpt := fmt.Sprintf(":sout=#transcode{vcodec=h264,vb=0,scale=1}:duplicate{dst=file{dst=%s}}", destPath)
+		saveOpt := fmt.Sprintf(":sout=#transcode{%s}:duplicate{dst=file{dst=%s}}", transcodeOpts, destPath)
 		if err := media.AddOptions(saveOpt); err != nil {
 			log.Fatalf("Cannot add media options: %s\n", err)
 		}
@@ -232,6 +290,7 @@ func main() {
 		areaFrame.SetSensitive(false)
 		destFrame.SetSensitive(false)
 		recordingFrame.SetSensitive(false)
+		audioFrame.SetSensitive(false)
 	})
 
 	exitButton := gtk.NewButtonFromStock("gtk-close")
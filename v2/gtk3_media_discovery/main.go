@@ -0,0 +1,445 @@
+//go:build gtk3
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	vlc "github.com/adrg/libvlc-go/v2"
+	"github.com/gotk3/gotk3/cairo"
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/adrg/libvlc-go-examples/shared/gtkutil"
+	"github.com/adrg/libvlc-go-examples/v2/internal/gtkvlc"
+)
+
+const appID = "com.github.libvlc-go.gtk3-media-discovery-example"
+
+func clearListBox(l *gtk.ListBox) {
+	l.GetChildren().Foreach(func(row interface{}) {
+		widget, ok := row.(*gtk.Widget)
+		if !ok {
+			return
+		}
+		l.Remove(widget)
+	})
+}
+
+// formatTime returns the specified number of milliseconds as mm:ss.
+func formatTime(ms int) string {
+	seconds := ms / 1000
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+}
+
+func main() {
+	// Initialize libVLC module and create new GTK application.
+	app, err := gtkvlc.NewApp(appID)
+	gtkutil.AssertErr(err)
+
+	player, err := vlc.NewListPlayer()
+	gtkutil.AssertErr(err)
+
+	var (
+		sources         = map[string]*discoverySource{}
+		playingList     *vlc.MediaList
+		playingNode     *mediaNode
+		playingSource   string
+		eventManager    *vlc.EventManager
+		nextItemEventID vlc.EventID
+	)
+
+	app.OnActivate("layout.glade", func(builder *gtk.Builder) {
+		// Get application window.
+		appWin := gtkutil.MustGet[*gtk.ApplicationWindow](builder, "appWindow")
+
+		// Get discovery service category combo box.
+		categoryComboBox := gtkutil.MustGet[*gtk.ComboBoxText](builder, "categoryComboBox")
+
+		// Get discovery services list box.
+		servicesListBox := gtkutil.MustGet[*gtk.ListBox](builder, "servicesListBox")
+
+		// Get media tree view.
+		mediaTreeView := gtkutil.MustGet[*gtk.TreeView](builder, "mediaTreeView")
+
+		mediaTree, err := newMediaTree(mediaTreeView)
+		gtkutil.AssertErr(err)
+
+		// Get media search entry.
+		mediaSearchEntry := gtkutil.MustGet[*gtk.SearchEntry](builder, "mediaSearchEntry")
+
+		// Get transport controls.
+		previousButton := gtkutil.MustGet[*gtk.Button](builder, "previousButton")
+		nextButton := gtkutil.MustGet[*gtk.Button](builder, "nextButton")
+		seekScale := gtkutil.MustGet[*gtk.Scale](builder, "seekScale")
+		timeLabel := gtkutil.MustGet[*gtk.Label](builder, "timeLabel")
+
+		// Get breadcrumb box.
+		breadcrumbBox := gtkutil.MustGet[*gtk.Box](builder, "breadcrumbBox")
+
+		// Get play button.
+		playButton := gtkutil.MustGet[*gtk.Button](builder, "playButton")
+		playButton.SetSensitive(false)
+
+		// Get pause button.
+		pauseButton := gtkutil.MustGet[*gtk.Button](builder, "pauseButton")
+		pauseButton.SetSensitive(false)
+
+		// Highlights the node of the media being played. Called when the
+		// list player moves to another item of the media list.
+		updatePlayingNode := func() {
+			var node *mediaNode
+			if mediaPlayer, err := player.Player(); err == nil {
+				if media, _ := mediaPlayer.Media(); media != nil {
+					location, _ := media.Location()
+					node = mediaTree.Find(playingList, location)
+				}
+			}
+
+			mediaTree.SetPlaying(node)
+			playingNode = node
+
+			selected := mediaTree.Selected()
+			playButton.SetSensitive(selected != nil && selected != playingNode)
+		}
+
+		// Updates the seek bar and the time label periodically.
+		var updatingSeek bool
+		glib.TimeoutAdd(500, func() bool {
+			mediaPlayer, err := player.Player()
+			if err != nil {
+				return true
+			}
+
+			var position float32
+			var mediaTime, mediaLength int
+			if media, _ := mediaPlayer.Media(); media != nil {
+				position, _ = mediaPlayer.MediaPosition()
+				mediaTime, _ = mediaPlayer.MediaTime()
+				mediaLength, _ = mediaPlayer.MediaLength()
+			}
+			if position < 0 {
+				position = 0
+			}
+
+			updatingSeek = true
+			seekScale.SetValue(float64(position))
+			updatingSeek = false
+			seekScale.SetSensitive(mediaPlayer.IsSeekable())
+
+			timeLabel.SetText(formatTime(mediaTime) + " / " + formatTime(mediaLength))
+			return true
+		})
+
+		// Track the item being played by the list player.
+		eventManager, err = player.EventManager()
+		gtkutil.AssertErr(err)
+
+		nextItemEventID, err = eventManager.Attach(vlc.MediaListPlayerNextItemSet, func(event vlc.Event, userData interface{}) {
+			glib.IdleAdd(updatePlayingNode)
+		}, nil)
+		gtkutil.AssertErr(err)
+
+		// Displays the path of the selected node in the breadcrumb box.
+		// Clicking a breadcrumb selects the corresponding node.
+		updateBreadcrumbs := func(node *mediaNode) {
+			breadcrumbBox.GetChildren().Foreach(func(child interface{}) {
+				if widget, ok := child.(*gtk.Widget); ok {
+					widget.Destroy()
+				}
+			})
+			if node == nil {
+				return
+			}
+
+			sourceLabel, err := gtk.LabelNew(node.source)
+			gtkutil.AssertErr(err)
+			breadcrumbBox.Add(sourceLabel)
+
+			for _, ancestor := range node.ancestors() {
+				separator, err := gtk.LabelNew("›")
+				gtkutil.AssertErr(err)
+				breadcrumbBox.Add(separator)
+
+				crumb, err := gtk.ButtonNewWithLabel(ancestor.title)
+				gtkutil.AssertErr(err)
+				crumb.SetRelief(gtk.RELIEF_NONE)
+				crumb.SetSensitive(ancestor != node)
+
+				ancestor := ancestor
+				crumb.Connect("clicked", func() {
+					mediaTree.Select(ancestor)
+				})
+				breadcrumbBox.Add(crumb)
+			}
+			breadcrumbBox.ShowAll()
+		}
+
+		// Plays the media of the specified node. The media list containing
+		// the node becomes the media list of the player.
+		playNode := func(node *mediaNode) {
+			index := mediaTree.Index(node)
+			if index < 0 {
+				return
+			}
+
+			if node.list != playingList {
+				player.Stop()
+				if err := player.SetMediaList(node.list); err != nil {
+					log.Printf("ERROR: %v\n", err)
+					return
+				}
+				playingList = node.list
+			}
+
+			if err := player.PlayAtIndex(uint(index)); err != nil {
+				log.Printf("ERROR: %v\n", err)
+				return
+			}
+			mediaTree.SetPlaying(node)
+			playingNode, playingSource = node, node.source
+
+			playButton.SetSensitive(false)
+			pauseButton.SetSensitive(true)
+			pauseButton.SetLabel("Pause")
+			previousButton.SetSensitive(true)
+			nextButton.SetSensitive(true)
+		}
+
+		// Status labels of the displayed discovery services.
+		statusLabels := map[string]*gtk.Label{}
+
+		updateSourceStatus := func(name string) {
+			label, ok := statusLabels[name]
+			if !ok {
+				return
+			}
+
+			status := "Stopped"
+			if source, ok := sources[name]; ok {
+				status = source.Status()
+			}
+			label.SetText(status)
+		}
+
+		// Starts the specified discovery service. The items found by the
+		// service are added to the media tree.
+		startSource := func(service *discoveryService) error {
+			source, err := startDiscoverySource(service, func(source *discoverySource, event vlc.Event, media *vlc.Media, location string, index int) {
+				switch event {
+				case vlc.MediaListItemAdded:
+					mediaTree.Insert(source.LongName, media, location, source.list, index)
+				case vlc.MediaListItemDeleted:
+					mediaTree.Remove(source.LongName, index)
+				}
+				updateSourceStatus(source.Name)
+			})
+			if err != nil {
+				return err
+			}
+
+			sources[service.Name] = source
+			updateSourceStatus(service.Name)
+			return nil
+		}
+
+		// Stops the discovery service with the specified name and removes
+		// its items from the media tree. Playback is stopped if the media
+		// being played was found by the service.
+		stopSource := func(name string) {
+			source, ok := sources[name]
+			if !ok {
+				return
+			}
+			delete(sources, name)
+
+			if playingSource == source.LongName {
+				player.Stop()
+				mediaTree.SetPlaying(nil)
+				playingList, playingNode, playingSource = nil, nil, ""
+
+				pauseButton.SetSensitive(false)
+				previousButton.SetSensitive(false)
+				nextButton.SetSensitive(false)
+			}
+
+			mediaTree.RemoveSource(source.LongName)
+			if err := source.Stop(); err != nil {
+				log.Printf("ERROR: %v\n", err)
+			}
+			updateSourceStatus(name)
+		}
+
+		// Add builder signal handlers.
+		signals := map[string]interface{}{
+			"onServiceCategoryChange": func() {
+				// Clear discovery service list. Running services are not
+				// affected.
+				clearListBox(servicesListBox)
+				statusLabels = map[string]*gtk.Label{}
+
+				// Get selected discovery service category.
+				category := categoryComboBox.GetActive() - 1
+				if category < 0 || category >= len(discoveryServices) {
+					return
+				}
+
+				// Get discovery services. libVLC 2 cannot list the available
+				// services, so the known services of the category are used.
+				services := discoveryServices[category]
+
+				// Add discovery services to the list. Each service can be
+				// started and stopped using its switch.
+				for _, service := range services {
+					nameLabel, err := gtk.LabelNew(service.Name)
+					gtkutil.AssertErr(err)
+					nameLabel.SetHAlign(gtk.ALIGN_START)
+					nameLabel.SetMarginStart(5)
+					longNameLabel, err := gtk.LabelNew(service.LongName)
+					gtkutil.AssertErr(err)
+					longNameLabel.SetMarginStart(20)
+					longNameLabel.SetHAlign(gtk.ALIGN_START)
+
+					infoBox, err := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 0)
+					gtkutil.AssertErr(err)
+					infoBox.Add(nameLabel)
+					infoBox.Add(longNameLabel)
+
+					statusLabel, err := gtk.LabelNew("")
+					gtkutil.AssertErr(err)
+					statusLabel.SetMarginEnd(10)
+					statusLabels[service.Name] = statusLabel
+					updateSourceStatus(service.Name)
+
+					serviceSwitch, err := gtk.SwitchNew()
+					gtkutil.AssertErr(err)
+					serviceSwitch.SetVAlign(gtk.ALIGN_CENTER)
+					serviceSwitch.SetMarginEnd(5)
+					serviceSwitch.SetActive(sources[service.Name] != nil)
+
+					service := service
+					serviceSwitch.Connect("notify::active", func() {
+						if !serviceSwitch.GetActive() {
+							stopSource(service.Name)
+							return
+						}
+						if _, ok := sources[service.Name]; ok {
+							return
+						}
+
+						if err := startSource(service); err != nil {
+							log.Printf("ERROR: %v\n", err)
+							serviceSwitch.SetActive(false)
+						}
+					})
+
+					rowBox, err := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 0)
+					gtkutil.AssertErr(err)
+					rowBox.SetMarginTop(5)
+					rowBox.SetMarginBottom(5)
+					rowBox.PackStart(infoBox, true, true, 0)
+					rowBox.PackStart(statusLabel, false, false, 0)
+					rowBox.PackStart(serviceSwitch, false, false, 0)
+
+					row, err := gtk.ListBoxRowNew()
+					gtkutil.AssertErr(err)
+
+					row.Add(rowBox)
+					servicesListBox.Add(row)
+					servicesListBox.ShowAll()
+				}
+			},
+			"onMediaTreeSelectionChanged": func() {
+				node := mediaTree.Selected()
+				playButton.SetSensitive(node != nil && node != playingNode)
+				updateBreadcrumbs(node)
+			},
+			"onMediaTreeRowExpanded": func(_ *gtk.TreeView, iter *gtk.TreeIter) {
+				mediaTree.Expand(iter)
+			},
+			"onMediaTreeRowActivated": func() {
+				if node := mediaTree.Selected(); node != nil && !node.folder {
+					playNode(node)
+				}
+			},
+			"onMediaSearchChanged": func() {
+				query, err := mediaSearchEntry.GetText()
+				gtkutil.AssertErr(err)
+				mediaTree.SetQuery(query)
+			},
+			"onMediaPlay": func() {
+				if node := mediaTree.Selected(); node != nil {
+					playNode(node)
+				}
+			},
+			"onRealizePlayerArea": func(playerArea *gtk.DrawingArea) {
+				// Set window for the player.
+				mediaPlayer, err := player.Player()
+				gtkutil.AssertErr(err)
+				playerWindow, err := playerArea.GetWindow()
+				gtkutil.AssertErr(err)
+				err = gtkvlc.SetPlayerWindow(mediaPlayer, playerWindow)
+				gtkutil.AssertErr(err)
+			},
+			"onDrawPlayerArea": func(playerArea *gtk.DrawingArea, cr *cairo.Context) {
+				cr.SetSourceRGB(0, 0, 0)
+				cr.Paint()
+			},
+			"onSeekValueChanged": func(seekScale *gtk.Scale) {
+				if updatingSeek {
+					return
+				}
+
+				mediaPlayer, err := player.Player()
+				if err != nil {
+					return
+				}
+				mediaPlayer.SetMediaPosition(float32(seekScale.GetValue()))
+			},
+			"onMediaPrevious": func() {
+				if err := player.PlayPrevious(); err != nil {
+					log.Printf("ERROR: %v\n", err)
+				}
+			},
+			"onMediaNext": func() {
+				if err := player.PlayNext(); err != nil {
+					log.Printf("ERROR: %v\n", err)
+				}
+			},
+			"onMediaStop": func() {
+				isPlaying := player.IsPlaying()
+				player.TogglePause()
+
+				label := "Pause"
+				if isPlaying {
+					label = "Resume"
+				}
+				pauseButton.SetLabel(label)
+			},
+		}
+		builder.ConnectSignals(signals)
+
+		appWin.ShowAll()
+		app.AddWindow(appWin)
+	})
+
+	// Cleanup on exit.
+	app.OnShutdown(func() {
+		// Release media discovery services.
+		for _, source := range sources {
+			source.Stop()
+		}
+
+		// Release player.
+		if eventManager != nil {
+			eventManager.Detach(nextItemEventID)
+		}
+		player.Stop()
+		player.Release()
+	})
+
+	// Launch the application.
+	os.Exit(app.Run())
+}
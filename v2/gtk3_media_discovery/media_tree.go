@@ -0,0 +1,690 @@
+//go:build gtk3
+
+package main
+
+import (
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	vlc "github.com/adrg/libvlc-go/v2"
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// Media tree store columns.
+const (
+	colNodeID = iota
+	colIcon
+	colTitle
+	colSource
+	colVisible
+	colWeight
+)
+
+// Font weights of the titles of the nodes.
+const (
+	weightNormal = 400
+	weightBold   = 700
+)
+
+// mediaNode represents an item of the media tree.
+type mediaNode struct {
+	id       int
+	title    string
+	location string
+	source   string
+	media    *vlc.Media
+	parent   *mediaNode
+
+	// List containing the media of the node. Used for playback.
+	list *vlc.MediaList
+
+	// Local directories and playlists can contain sub-items, which are
+	// loaded when the node is first expanded.
+	folder   bool
+	loaded   bool
+	loading  bool
+	subItems *vlc.MediaList
+}
+
+// mediaTree displays discovered media in a tree view. The sub-items of
+// folders are loaded lazily, when the folders are expanded. libVLC 2 does
+// not report media types, so only local directories and playlist files are
+// displayed as folders.
+type mediaTree struct {
+	view   *gtk.TreeView
+	store  *gtk.TreeStore
+	filter *gtk.TreeModelFilter
+	query  string
+	nodes  map[int]*mediaNode
+	lastID int
+
+	// Top level nodes of each source, in the order of the media lists of
+	// the sources.
+	sources map[string][]*mediaNode
+
+	// Node of the media being played, highlighted in the tree.
+	playing *mediaNode
+}
+
+func newMediaTree(view *gtk.TreeView) (*mediaTree, error) {
+	store, err := gtk.TreeStoreNew(glib.TYPE_INT, glib.TYPE_STRING, glib.TYPE_STRING, glib.TYPE_STRING, glib.TYPE_BOOLEAN, glib.TYPE_INT)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := store.FilterNew(nil)
+	if err != nil {
+		return nil, err
+	}
+	filter.SetVisibleColumn(colVisible)
+
+	// Add title column, along with the icon of the media type.
+	column, err := gtk.TreeViewColumnNew()
+	if err != nil {
+		return nil, err
+	}
+	column.SetTitle("Title")
+
+	iconRenderer, err := gtk.CellRendererPixbufNew()
+	if err != nil {
+		return nil, err
+	}
+	column.PackStart(iconRenderer, false)
+	column.AddAttribute(iconRenderer, "icon-name", colIcon)
+
+	titleRenderer, err := gtk.CellRendererTextNew()
+	if err != nil {
+		return nil, err
+	}
+	column.PackStart(titleRenderer, true)
+	column.AddAttribute(titleRenderer, "text", colTitle)
+	column.AddAttribute(titleRenderer, "weight", colWeight)
+	column.SetExpand(true)
+	view.AppendColumn(column)
+
+	// Add source column.
+	sourceRenderer, err := gtk.CellRendererTextNew()
+	if err != nil {
+		return nil, err
+	}
+	sourceColumn, err := gtk.TreeViewColumnNewWithAttribute("Source", sourceRenderer, "text", colSource)
+	if err != nil {
+		return nil, err
+	}
+	view.AppendColumn(sourceColumn)
+	view.SetModel(filter)
+
+	return &mediaTree{
+		view:    view,
+		store:   store,
+		filter:  filter,
+		nodes:   map[int]*mediaNode{},
+		sources: map[string][]*mediaNode{},
+	}, nil
+}
+
+// Insert adds a top level node for the specified media, found by the
+// specified source. The list contains the media (e.g. the list of a
+// discovery service) and the index is the position of the media in it.
+// The tree takes ownership of the media and releases it when the node
+// is removed. If the media is nil, a node displaying the specified
+// location is added instead, so that the nodes of the source remain in
+// sync with the list.
+func (t *mediaTree) Insert(source string, media *vlc.Media, location string, list *vlc.MediaList, index int) {
+	nodes := t.sources[source]
+	if index < 0 || index > len(nodes) {
+		index = len(nodes)
+	}
+
+	node := t.newNode(media, list, nil)
+	node.source = source
+	if media == nil {
+		node.title, node.location = location, location
+	}
+
+	// Insert the row next to the rows of the adjacent nodes of the source,
+	// so that the rows are displayed in the order of the list.
+	var iter *gtk.TreeIter
+	if index < len(nodes) {
+		if sibling := t.findIter(nil, nodes[index].id); sibling != nil {
+			iter = t.store.InsertBefore(nil, sibling)
+		}
+	} else if index > 0 {
+		if sibling := t.findIter(nil, nodes[index-1].id); sibling != nil {
+			iter = t.store.InsertAfter(nil, sibling)
+		}
+	}
+	if iter == nil {
+		iter = t.store.Append(nil)
+	}
+
+	nodes = append(nodes, nil)
+	copy(nodes[index+1:], nodes[index:])
+	nodes[index] = node
+	t.sources[source] = nodes
+
+	t.addRow(iter, node)
+	t.refilter()
+}
+
+// Remove removes the top level node found by the specified source, at the
+// specified position of the media list of the source.
+func (t *mediaTree) Remove(source string, index int) {
+	nodes := t.sources[source]
+	if index < 0 || index >= len(nodes) {
+		return
+	}
+	node := nodes[index]
+	t.sources[source] = append(nodes[:index], nodes[index+1:]...)
+
+	if iter := t.findIter(nil, node.id); iter != nil {
+		t.removeNodes(iter)
+		t.store.Remove(iter)
+	}
+	node.media.Release()
+}
+
+// RemoveSource removes all the nodes found by the specified source.
+func (t *mediaTree) RemoveSource(source string) {
+	for _, node := range t.sources[source] {
+		if iter := t.findIter(nil, node.id); iter != nil {
+			t.removeNodes(iter)
+			t.store.Remove(iter)
+		}
+		node.media.Release()
+	}
+	delete(t.sources, source)
+}
+
+// Node returns the node displayed on the row the filtered iterator points to.
+func (t *mediaTree) Node(iter *gtk.TreeIter) *mediaNode {
+	return t.nodeAt(t.filter.ConvertIterToChildIter(iter))
+}
+
+// Selected returns the selected node, if any.
+func (t *mediaTree) Selected() *mediaNode {
+	selection, err := t.view.GetSelection()
+	if err != nil {
+		return nil
+	}
+
+	_, iter, ok := selection.GetSelected()
+	if !ok {
+		return nil
+	}
+
+	return t.Node(iter)
+}
+
+// Select selects the row of the specified node and scrolls it into view.
+func (t *mediaTree) Select(node *mediaNode) {
+	iter := t.findIter(nil, node.id)
+	if iter == nil {
+		return
+	}
+	path, err := t.store.GetPath(iter)
+	if err != nil {
+		return
+	}
+
+	// Convert store path to filter path. The path is nil if the row is
+	// hidden by the search filter.
+	if path = t.filter.ConvertChildPathToPath(path); path == nil {
+		return
+	}
+	t.view.ExpandToPath(path)
+
+	if selection, err := t.view.GetSelection(); err == nil {
+		selection.SelectPath(path)
+	}
+	t.view.ScrollToCell(path, nil, false, 0, 0)
+}
+
+// Find returns the node of the media having the specified location, which
+// is contained by the specified media list.
+func (t *mediaTree) Find(list *vlc.MediaList, location string) *mediaNode {
+	for _, node := range t.nodes {
+		if node.list == list && node.location == location {
+			return node
+		}
+	}
+
+	return nil
+}
+
+// SetPlaying highlights the node of the media being played. No node is
+// highlighted if the specified node is nil.
+func (t *mediaTree) SetPlaying(node *mediaNode) {
+	if t.playing != nil {
+		if iter := t.findIter(nil, t.playing.id); iter != nil {
+			t.store.SetValue(iter, colWeight, weightNormal)
+		}
+	}
+
+	t.playing = node
+	if node == nil {
+		return
+	}
+	if iter := t.findIter(nil, node.id); iter != nil {
+		t.store.SetValue(iter, colWeight, weightBold)
+	}
+}
+
+// Index returns the index of the node in its media list.
+func (t *mediaTree) Index(node *mediaNode) int {
+	if node.parent == nil {
+		for i, n := range t.sources[node.source] {
+			if n == node {
+				return i
+			}
+		}
+		return -1
+	}
+
+	iter := t.findIter(nil, node.id)
+	if iter == nil {
+		return -1
+	}
+	path, err := t.store.GetPath(iter)
+	if err != nil {
+		return -1
+	}
+
+	indices := path.GetIndices()
+	return indices[len(indices)-1]
+}
+
+// Expand loads the sub-items of the node the filtered iterator points to,
+// if they are not loaded already.
+func (t *mediaTree) Expand(iter *gtk.TreeIter) {
+	node := t.Node(iter)
+	if node == nil || !node.folder || node.loaded || node.loading {
+		return
+	}
+	node.loading = true
+
+	// libVLC 2 does not retrieve the items of directories when parsing
+	// them, so local directories are read directly.
+	if path, ok := localDirectory(node.location); ok {
+		node.loading = false
+		if err := t.loadDirectory(node, path); err != nil {
+			log.Printf("Cannot load items of %s: %s\n", node.title, err)
+			t.setPlaceholder(node, "Cannot load items")
+			return
+		}
+		node.loaded = true
+		return
+	}
+
+	err := parseMediaAsync(node.media, func() {
+		node.loading = false
+		if _, ok := t.nodes[node.id]; !ok {
+			// Node removed while loading.
+			return
+		}
+		if err := t.loadSubItems(node); err != nil {
+			log.Printf("Cannot load sub-items of %s: %s\n", node.title, err)
+			t.setPlaceholder(node, "Cannot load items")
+			return
+		}
+		node.loaded = true
+	})
+	if err != nil {
+		node.loading = false
+		log.Printf("Cannot parse %s: %s\n", node.title, err)
+	}
+}
+
+// SetQuery filters the tree, so that only nodes matching the specified
+// search query are visible. Nodes are visible if their title contains the
+// query, if an ancestor matches the query or if a descendant matches it.
+func (t *mediaTree) SetQuery(query string) {
+	t.query = strings.ToLower(strings.TrimSpace(query))
+	t.refilter()
+
+	if t.query != "" {
+		t.view.ExpandAll()
+	}
+}
+
+func (t *mediaTree) refilter() {
+	t.updateVisibility(nil, false)
+	t.filter.Refilter()
+}
+
+// updateVisibility updates the visibility of the children of the specified
+// row. Returns true if any of the children is visible.
+func (t *mediaTree) updateVisibility(parent *gtk.TreeIter, ancestorMatch bool) bool {
+	anyVisible := false
+
+	for i := 0; i < t.store.IterNChildren(parent); i++ {
+		var iter gtk.TreeIter
+		if !t.store.IterNthChild(&iter, parent, i) {
+			continue
+		}
+
+		visible := t.query == "" || ancestorMatch
+		if node := t.nodeAt(&iter); node != nil {
+			match := visible || strings.Contains(strings.ToLower(node.title), t.query)
+			if t.updateVisibility(&iter, match) {
+				visible = true
+			}
+			visible = visible || match
+		}
+
+		t.store.SetValue(&iter, colVisible, visible)
+		anyVisible = anyVisible || visible
+	}
+
+	return anyVisible
+}
+
+func (t *mediaTree) newNode(media *vlc.Media, list *vlc.MediaList, parent *mediaNode) *mediaNode {
+	t.lastID++
+	location, _ := media.Location()
+
+	node := &mediaNode{
+		id:       t.lastID,
+		title:    mediaTitle(media),
+		location: location,
+		media:    media,
+		list:     list,
+		parent:   parent,
+	}
+	if parent != nil {
+		node.source = parent.source
+	}
+	node.folder = isFolder(location)
+	t.nodes[node.id] = node
+
+	return node
+}
+
+func (t *mediaTree) addRow(iter *gtk.TreeIter, node *mediaNode) {
+	icon := "text-x-generic"
+	if node.folder {
+		icon = "folder"
+	}
+
+	t.store.SetValue(iter, colNodeID, node.id)
+	t.store.SetValue(iter, colIcon, icon)
+	t.store.SetValue(iter, colTitle, node.title)
+	if node.parent == nil {
+		t.store.SetValue(iter, colSource, node.source)
+	}
+	t.store.SetValue(iter, colVisible, true)
+	t.store.SetValue(iter, colWeight, weightNormal)
+
+	// Add placeholder row, so that folders can be expanded.
+	if node.folder {
+		t.addPlaceholder(iter, "Loading...")
+	}
+}
+
+func (t *mediaTree) addPlaceholder(parent *gtk.TreeIter, text string) {
+	iter := t.store.Append(parent)
+	t.store.SetValue(iter, colNodeID, 0)
+	t.store.SetValue(iter, colTitle, text)
+	t.store.SetValue(iter, colVisible, true)
+	t.store.SetValue(iter, colWeight, weightNormal)
+}
+
+func (t *mediaTree) setPlaceholder(node *mediaNode, text string) {
+	if iter := t.findIter(nil, node.id); iter != nil {
+		t.clearChildren(iter)
+		t.addPlaceholder(iter, text)
+	}
+}
+
+func (t *mediaTree) loadSubItems(node *mediaNode) error {
+	subItems, err := node.media.SubItems()
+	if err != nil {
+		return err
+	}
+
+	return t.setSubItems(node, subItems)
+}
+
+// loadDirectory loads the files contained by the specified local directory
+// as the sub-items of the node. Hidden files are skipped.
+func (t *mediaTree) loadDirectory(node *mediaNode, dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	subItems, err := vlc.NewMediaList()
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if err := subItems.AddMediaFromPath(filepath.Join(dir, entry.Name())); err != nil {
+			subItems.Release()
+			return err
+		}
+	}
+
+	return t.setSubItems(node, subItems)
+}
+
+// setSubItems displays the media of the specified list as the children of
+// the node. The node takes ownership of the list.
+func (t *mediaTree) setSubItems(node *mediaNode, subItems *vlc.MediaList) error {
+	if node.subItems != nil {
+		node.subItems.Release()
+	}
+	node.subItems = subItems
+
+	iter := t.findIter(nil, node.id)
+	if iter == nil {
+		return nil
+	}
+	t.clearChildren(iter)
+
+	var media []*vlc.Media
+	if subItems != nil {
+		var err error
+		if media, err = mediaListItems(subItems); err != nil {
+			return err
+		}
+	}
+	if len(media) == 0 {
+		t.addPlaceholder(iter, "No items")
+		return nil
+	}
+
+	for _, m := range media {
+		t.addRow(t.store.Append(iter), t.newNode(m, subItems, node))
+	}
+	t.refilter()
+
+	return nil
+}
+
+func (t *mediaTree) clearChildren(parent *gtk.TreeIter) {
+	var iter gtk.TreeIter
+	for t.store.IterNthChild(&iter, parent, 0) {
+		t.removeNodes(&iter)
+		t.store.Remove(&iter)
+	}
+}
+
+// removeNodes removes the nodes displayed on the specified row and on its
+// descendants.
+func (t *mediaTree) removeNodes(iter *gtk.TreeIter) {
+	if node := t.nodeAt(iter); node != nil {
+		delete(t.nodes, node.id)
+		if node == t.playing {
+			t.playing = nil
+		}
+
+		// The sub-items list is retained by the player while it is used
+		// for playback, so it can be released along with the node.
+		if node.subItems != nil {
+			node.subItems.Release()
+			node.subItems = nil
+		}
+	}
+
+	for i := 0; i < t.store.IterNChildren(iter); i++ {
+		var child gtk.TreeIter
+		if t.store.IterNthChild(&child, iter, i) {
+			t.removeNodes(&child)
+		}
+	}
+}
+
+func (t *mediaTree) nodeAt(iter *gtk.TreeIter) *mediaNode {
+	value, err := t.store.GetValue(iter, colNodeID)
+	if err != nil {
+		return nil
+	}
+	id, err := value.GoValue()
+	if err != nil {
+		return nil
+	}
+	nodeID, ok := id.(int)
+	if !ok {
+		return nil
+	}
+
+	return t.nodes[nodeID]
+}
+
+// findIter returns an iterator pointing to the row of the node with the
+// specified ID, searching the descendants of the parent row.
+func (t *mediaTree) findIter(parent *gtk.TreeIter, id int) *gtk.TreeIter {
+	for i := 0; i < t.store.IterNChildren(parent); i++ {
+		iter := &gtk.TreeIter{}
+		if !t.store.IterNthChild(iter, parent, i) {
+			continue
+		}
+		if node := t.nodeAt(iter); node != nil && node.id == id {
+			return iter
+		}
+		if found := t.findIter(iter, id); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// ancestors returns the path from the top level node to the specified node.
+func (n *mediaNode) ancestors() []*mediaNode {
+	var nodes []*mediaNode
+	for node := n; node != nil; node = node.parent {
+		nodes = append([]*mediaNode{node}, nodes...)
+	}
+
+	return nodes
+}
+
+// mediaTitle returns the title of the media, falling back to its location.
+func mediaTitle(media *vlc.Media) string {
+	if title, _ := media.Meta(vlc.MediaTitle); title != "" {
+		return title
+	}
+
+	location, _ := media.Location()
+	return location
+}
+
+// mediaListItems returns the items of the specified media list.
+func mediaListItems(list *vlc.MediaList) ([]*vlc.Media, error) {
+	count, err := list.Count()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*vlc.Media, 0, count)
+	for i := 0; i < count; i++ {
+		media, err := list.MediaAtIndex(uint(i))
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, media)
+	}
+
+	return items, nil
+}
+
+// isFolder returns true if the media having the specified location can
+// contain sub-items (i.e. local directories and playlist files).
+func isFolder(location string) bool {
+	if _, ok := localDirectory(location); ok {
+		return true
+	}
+
+	switch strings.ToLower(filepath.Ext(location)) {
+	case ".m3u", ".m3u8", ".pls", ".xspf", ".asx":
+		return true
+	}
+
+	return false
+}
+
+// localDirectory returns the path of the local directory having the
+// specified location, if the location points to one.
+func localDirectory(location string) (string, bool) {
+	u, err := url.Parse(location)
+	if err != nil || u.Scheme != "file" {
+		return "", false
+	}
+
+	info, err := os.Stat(u.Path)
+	if err != nil || !info.IsDir() {
+		return "", false
+	}
+
+	return u.Path, true
+}
+
+// parseMediaAsync parses the specified media and calls the provided
+// callback function when parsing is done. The callback function is called
+// on the main GTK loop.
+func parseMediaAsync(media *vlc.Media, callback func()) error {
+	if parsed, _ := media.IsParsed(); parsed {
+		glib.IdleAdd(callback)
+		return nil
+	}
+
+	manager, err := media.EventManager()
+	if err != nil {
+		return err
+	}
+
+	var (
+		eventID  vlc.EventID
+		detached bool
+	)
+	eventCallback := func(event vlc.Event, userData interface{}) {
+		// NOTE: the event cannot be detached from the callback function.
+		glib.IdleAdd(func() {
+			if detached {
+				return
+			}
+			manager.Detach(eventID)
+			detached = true
+
+			callback()
+		})
+	}
+
+	if eventID, err = manager.Attach(vlc.MediaParsedChanged, eventCallback, nil); err != nil {
+		return err
+	}
+	if err = media.ParseAsync(); err != nil {
+		manager.Detach(eventID)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+//go:build gtk3
+
+package main
+
+import (
+	"fmt"
+	"log"
+
+	vlc "github.com/adrg/libvlc-go/v2"
+	"github.com/gotk3/gotk3/glib"
+
+	"github.com/adrg/libvlc-go-examples/shared/vlc2discovery"
+	"github.com/adrg/libvlc-go-examples/v2/internal/gtkvlc"
+)
+
+// Interval at which the items of the running sources are synchronized.
+const pollInterval = 1000 // milliseconds
+
+// discoveryService describes a media discovery service. libVLC 2 cannot
+// list the available services, so the services shipped with VLC 2.2 are
+// listed instead. Services whose modules are not installed fail to start.
+type discoveryService struct {
+	Name     string
+	LongName string
+}
+
+// discoveryServices contains the media discovery services of each
+// category, in the order of the category combo box.
+var discoveryServices = [][]*discoveryService{
+	// Devices.
+	{
+		{Name: "disc", LongName: "Discs"},
+		{Name: "v4l", LongName: "Video capture"},
+		{Name: "alsa", LongName: "Audio capture"},
+		{Name: "mtp", LongName: "MTP devices"},
+	},
+	// LAN services.
+	{
+		{Name: "upnp", LongName: "Universal Plug'n'Play"},
+		{Name: "sap", LongName: "Network streams (SAP)"},
+		{Name: "avahi", LongName: "Zeroconf network services"},
+	},
+	// Internet services.
+	{
+		{Name: "podcast", LongName: "Podcasts"},
+	},
+	// Local directories.
+	{
+		{Name: "video_dir", LongName: "My Videos"},
+		{Name: "audio_dir", LongName: "My Music"},
+		{Name: "picture_dir", LongName: "My Pictures"},
+	},
+}
+
+// discoverySource is a running media discovery service. Multiple sources
+// can run at the same time, their items being merged in the media tree.
+type discoverySource struct {
+	Name     string
+	LongName string
+
+	service *vlc2discovery.Discoverer
+	stopped bool
+
+	// The service uses its own libVLC instance, so media is created from
+	// the locations of its items and added to a separate list, which is
+	// used for playback.
+	list      *vlc.MediaList
+	locations []string
+}
+
+// startDiscoverySource creates and starts the specified media discovery
+// service. The callback function is called on the main GTK loop when items
+// are added to or removed from the media list of the source. For added
+// items, the callback receives the media of the item, which must be released
+// by the caller, and its location. For removed items, the media is nil.
+func startDiscoverySource(service *discoveryService,
+	callback func(source *discoverySource, event vlc.Event, media *vlc.Media, location string, index int)) (*discoverySource, error) {
+	discoverer, err := vlc2discovery.New(service.Name, gtkvlc.DefaultVLCArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := vlc.NewMediaList()
+	if err != nil {
+		discoverer.Release()
+		return nil, err
+	}
+
+	source := &discoverySource{
+		Name:     service.Name,
+		LongName: service.LongName,
+		service:  discoverer,
+		list:     list,
+	}
+	if name := discoverer.LocalizedName(); name != "" {
+		source.LongName = name
+	}
+
+	// libvlc-go v2 cannot receive the events of the media list of the
+	// service, so the items of the service are polled instead.
+	glib.TimeoutAdd(pollInterval, func() bool {
+		if source.stopped {
+			return false
+		}
+
+		source.sync(callback)
+		return true
+	})
+
+	return source, nil
+}
+
+// sync updates the media list of the source, so that it contains the items
+// currently found by the service, and calls the callback function for each
+// added or removed item.
+func (s *discoverySource) sync(callback func(source *discoverySource, event vlc.Event, media *vlc.Media, location string, index int)) {
+	items, err := s.service.Items()
+	if err != nil {
+		log.Printf("Cannot retrieve the items of %s: %s\n", s.Name, err)
+		return
+	}
+
+	found := make(map[string]bool, len(items))
+	for _, item := range items {
+		found[item.Location] = true
+	}
+
+	// Remove the items which are not found anymore.
+	for i := len(s.locations) - 1; i >= 0; i-- {
+		if found[s.locations[i]] {
+			continue
+		}
+
+		s.list.RemoveMediaAtIndex(uint(i))
+		s.locations = append(s.locations[:i], s.locations[i+1:]...)
+		callback(s, vlc.MediaListItemDeleted, nil, "", i)
+	}
+
+	// Add the new items, at their positions in the list of the service.
+	known := make(map[string]bool, len(s.locations))
+	for _, location := range s.locations {
+		known[location] = true
+	}
+
+	for i, item := range items {
+		if known[item.Location] {
+			continue
+		}
+		index := i
+		if index > len(s.locations) {
+			index = len(s.locations)
+		}
+
+		media, err := vlc.NewMediaFromURL(item.Location)
+		if err != nil {
+			log.Printf("Cannot create media %s: %s\n", item.Location, err)
+			continue
+		}
+		if item.Title != "" {
+			media.SetMeta(vlc.MediaTitle, item.Title)
+		}
+		if err := s.list.InsertMedia(media, uint(index)); err != nil {
+			log.Printf("Cannot add media %s: %s\n", item.Location, err)
+			media.Release()
+			continue
+		}
+
+		s.locations = append(s.locations, "")
+		copy(s.locations[index+1:], s.locations[index:])
+		s.locations[index] = item.Location
+		known[item.Location] = true
+
+		callback(s, vlc.MediaListItemAdded, media, item.Location, index)
+	}
+}
+
+// Stop stops and releases the media discovery service. The items of the
+// source must not be used afterwards.
+func (s *discoverySource) Stop() error {
+	s.stopped = true
+	s.list.Release()
+	return s.service.Release()
+}
+
+// Status returns a textual representation of the state of the source
+// (e.g. 12 items).
+func (s *discoverySource) Status() string {
+	if len(s.locations) == 1 {
+		return "1 item"
+	}
+
+	return fmt.Sprintf("%d items", len(s.locations))
+}
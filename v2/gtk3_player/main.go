@@ -1,3 +1,5 @@
+//go:build gtk3
+
 package main
 
 import (
@@ -6,70 +8,38 @@ import (
 
 	vlc "github.com/adrg/libvlc-go/v2"
 	"github.com/gotk3/gotk3/cairo"
-	"github.com/gotk3/gotk3/glib"
 	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/adrg/libvlc-go-examples/shared/gtkutil"
+	"github.com/adrg/libvlc-go-examples/v2/internal/gtkvlc"
 )
 
 const appID = "com.github.libvlc-go.gtk3-media-player-example"
 
-func builderGetObject(builder *gtk.Builder, name string) glib.IObject {
-	obj, _ := builder.GetObject(name)
-	return obj
-}
-
-func assertErr(err error) {
-	if err != nil {
-		log.Panic(err)
-	}
-}
-
-func assertConv(ok bool) {
-	if !ok {
-		log.Panic("invalid widget conversion")
-	}
-}
-
-func playerReleaseMedia(player *vlc.Player) {
-	player.Stop()
-	if media, _ := player.Media(); media != nil {
-		media.Release()
-	}
-}
-
 func main() {
-	// Initialize libVLC module.
-	err := vlc.Init("--quiet", "--no-xlib")
-	assertErr(err)
+	// Initialize libVLC module and create new GTK application.
+	app, err := gtkvlc.NewApp(appID)
+	gtkutil.AssertErr(err)
 
 	// Create a new player.
 	player, err := vlc.NewPlayer()
-	assertErr(err)
-
-	// Create new GTK application.
-	app, err := gtk.ApplicationNew(appID, glib.APPLICATION_FLAGS_NONE)
-	assertErr(err)
-
-	app.Connect("activate", func() {
-		// Load application layout.
-		builder, err := gtk.BuilderNewFromFile("layout.glade")
-		assertErr(err)
+	gtkutil.AssertErr(err)
 
+	app.OnActivate("layout.glade", func(builder *gtk.Builder) {
 		// Get application window.
-		appWin, ok := builderGetObject(builder, "appWindow").(*gtk.ApplicationWindow)
-		assertConv(ok)
+		appWin := gtkutil.MustGet[*gtk.ApplicationWindow](builder, "appWindow")
 
 		// Get play button.
-		playButton, ok := builderGetObject(builder, "playButton").(*gtk.Button)
-		assertConv(ok)
+		playButton := gtkutil.MustGet[*gtk.Button](builder, "playButton")
 
 		// Add builder signal handlers.
 		signals := map[string]interface{}{
 			"onRealizePlayerArea": func(playerArea *gtk.DrawingArea) {
 				// Set window for the player.
 				playerWindow, err := playerArea.GetWindow()
-				assertErr(err)
-				err = setPlayerWindow(player, playerWindow)
-				assertErr(err)
+				gtkutil.AssertErr(err)
+				err = gtkvlc.SetPlayerWindow(player, playerWindow)
+				gtkutil.AssertErr(err)
 			},
 			"onDrawPlayerArea": func(playerArea *gtk.DrawingArea, cr *cairo.Context) {
 				cr.SetSourceRGB(0, 0, 0)
@@ -81,11 +51,11 @@ func main() {
 					appWin, gtk.FILE_CHOOSER_ACTION_OPEN,
 					"Cancel", gtk.RESPONSE_DELETE_EVENT,
 					"Open", gtk.RESPONSE_ACCEPT)
-				assertErr(err)
+				gtkutil.AssertErr(err)
 				defer fileDialog.Destroy()
 
 				fileFilter, err := gtk.FileFilterNew()
-				assertErr(err)
+				gtkutil.AssertErr(err)
 				fileFilter.SetName("Media files")
 				fileFilter.AddPattern("*.mp4")
 				fileFilter.AddPattern("*.mp3")
@@ -93,7 +63,7 @@ func main() {
 
 				if result := fileDialog.Run(); result == gtk.RESPONSE_ACCEPT {
 					// Release current media, if any.
-					playerReleaseMedia(player)
+					gtkvlc.ReleaseMedia(player)
 
 					// Get selected filename.
 					filename := fileDialog.GetFilename()
@@ -136,12 +106,10 @@ func main() {
 	})
 
 	// Cleanup on exit.
-	app.Connect("shutdown", func() {
-		playerReleaseMedia(player)
-		player.Release()
-		vlc.Release()
+	app.OnShutdown(func() {
+		gtkvlc.ReleasePlayer(player)
 	})
 
 	// Launch the application.
-	os.Exit(app.Run(os.Args))
+	os.Exit(app.Run())
 }
@@ -1,3 +1,5 @@
+//go:build gtk3
+
 package main
 
 import (
@@ -6,113 +8,107 @@ import (
 	"os"
 
 	vlc "github.com/adrg/libvlc-go/v2"
-	"github.com/gotk3/gotk3/glib"
 	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/adrg/libvlc-go-examples/shared/gtkutil"
+	"github.com/adrg/libvlc-go-examples/shared/screencap"
+	"github.com/adrg/libvlc-go-examples/v2/internal/gtkvlc"
 )
 
 const appID = "com.github.libvlc-go.gtk3-screen-recorder-example"
 
-func builderGetObject(builder *gtk.Builder, name string) glib.IObject {
-	obj, _ := builder.GetObject(name)
-	return obj
-}
-
-func assertErr(err error) {
-	if err != nil {
-		log.Panic(err)
-	}
-}
-
-func assertConv(ok bool) {
-	if !ok {
-		log.Panic("invalid widget conversion")
-	}
-}
-
-func playerReleaseMedia(player *vlc.Player) {
-	player.Stop()
-	if media, _ := player.Media(); media != nil {
-		media.Release()
-	}
-}
-
 func main() {
-	// Initialize libVLC module.
-	err := vlc.Init("--quiet", "--no-xlib")
-	assertErr(err)
+	// Initialize libVLC module and create new GTK application.
+	app, err := gtkvlc.NewApp(appID)
+	gtkutil.AssertErr(err)
 
 	// Create a new player.
 	player, err := vlc.NewPlayer()
-	assertErr(err)
-
-	// Create new GTK application.
-	app, err := gtk.ApplicationNew(appID, glib.APPLICATION_FLAGS_NONE)
-	assertErr(err)
-
-	app.Connect("activate", func() {
-		// Load application layout.
-		builder, err := gtk.BuilderNewFromFile("layout.glade")
-		assertErr(err)
+	gtkutil.AssertErr(err)
 
+	app.OnActivate("layout.glade", func(builder *gtk.Builder) {
 		// Get application window.
-		appWin, ok := builderGetObject(builder, "appWindow").(*gtk.ApplicationWindow)
-		assertConv(ok)
+		appWin := gtkutil.MustGet[*gtk.ApplicationWindow](builder, "appWindow")
 
 		// Get capture area frame controls.
-		captureAreaFrame, ok := builderGetObject(builder, "captureAreaFrame").(*gtk.Frame)
-		assertConv(ok)
+		captureAreaFrame := gtkutil.MustGet[*gtk.Frame](builder, "captureAreaFrame")
 
-		entireScreenRadio, ok := builderGetObject(builder, "radioEntireScreen").(*gtk.RadioButton)
-		assertConv(ok)
+		entireScreenRadio := gtkutil.MustGet[*gtk.RadioButton](builder, "radioEntireScreen")
 
-		xInput, ok := builderGetObject(builder, "xInput").(*gtk.SpinButton)
-		assertConv(ok)
+		xInput := gtkutil.MustGet[*gtk.SpinButton](builder, "xInput")
 
-		yInput, ok := builderGetObject(builder, "yInput").(*gtk.SpinButton)
-		assertConv(ok)
+		yInput := gtkutil.MustGet[*gtk.SpinButton](builder, "yInput")
 
-		wInput, ok := builderGetObject(builder, "widthInput").(*gtk.SpinButton)
-		assertConv(ok)
+		wInput := gtkutil.MustGet[*gtk.SpinButton](builder, "widthInput")
 
-		hInput, ok := builderGetObject(builder, "heightInput").(*gtk.SpinButton)
-		assertConv(ok)
+		hInput := gtkutil.MustGet[*gtk.SpinButton](builder, "heightInput")
 
-		areaRectBox, ok := builderGetObject(builder, "rectangleAreaBox").(*gtk.Box)
-		assertConv(ok)
+		areaRectBox := gtkutil.MustGet[*gtk.Box](builder, "rectangleAreaBox")
 
 		// Get recording options frame controls.
-		recOptionsFrame, ok := builderGetObject(builder, "recordingOptionsFrame").(*gtk.Frame)
-		assertConv(ok)
+		recOptionsFrame := gtkutil.MustGet[*gtk.Frame](builder, "recordingOptionsFrame")
+
+		followMouseCheck := gtkutil.MustGet[*gtk.CheckButton](builder, "followMouseCheck")
 
-		followMouseCheck, ok := builderGetObject(builder, "followMouseCheck").(*gtk.CheckButton)
-		assertConv(ok)
+		fpsInput := gtkutil.MustGet[*gtk.SpinButton](builder, "fpsInput")
 
-		fpsInput, ok := builderGetObject(builder, "fpsInput").(*gtk.SpinButton)
-		assertConv(ok)
+		// Get audio frame controls.
+		audioFrame := gtkutil.MustGet[*gtk.Frame](builder, "audioFrame")
+
+		captureAudioCheck := gtkutil.MustGet[*gtk.CheckButton](builder, "captureAudioCheck")
+
+		audioOptionsBox := gtkutil.MustGet[*gtk.Box](builder, "audioOptionsBox")
+
+		audioSourceComboBox := gtkutil.MustGet[*gtk.ComboBoxText](builder, "audioSourceComboBox")
+
+		audioCodecComboBox := gtkutil.MustGet[*gtk.ComboBoxText](builder, "audioCodecComboBox")
+
+		// Fill audio sources combo box.
+		var audioSources []*screencap.AudioSource
+		fillAudioSources := func() {
+			audioSources = screencap.ListAudioSources()
+
+			audioSourceComboBox.RemoveAll()
+			for _, source := range audioSources {
+				audioSourceComboBox.AppendText(source.Name)
+			}
+			audioSourceComboBox.SetActive(0)
+		}
+		fillAudioSources()
+
+		// Fill audio codecs combo box.
+		for _, codec := range screencap.AudioCodecs {
+			audioCodecComboBox.AppendText(codec.Name)
+		}
+		audioCodecComboBox.SetActive(0)
 
 		// Get destination file frame controls.
-		destFileFrame, ok := builderGetObject(builder, "destinationFileFrame").(*gtk.Frame)
-		assertConv(ok)
+		destFileFrame := gtkutil.MustGet[*gtk.Frame](builder, "destinationFileFrame")
 
-		destInput, ok := builderGetObject(builder, "destinationInput").(*gtk.Entry)
-		assertConv(ok)
+		destInput := gtkutil.MustGet[*gtk.Entry](builder, "destinationInput")
 
 		// Add builder signal handlers.
 		signals := map[string]interface{}{
 			"onClickAreaSelect": func() {
 				areaRectBox.SetSensitive(!entireScreenRadio.GetActive())
 			},
+			"onToggleCaptureAudio": func() {
+				audioOptionsBox.SetSensitive(captureAudioCheck.GetActive())
+			},
+			"onClickRefreshAudioSources": func() {
+				fillAudioSources()
+			},
 			"onClickChooseDestinationFile": func() {
 				fileDialog, err := gtk.FileChooserDialogNewWith2Buttons(
 					"Choose file...",
 					appWin, gtk.FILE_CHOOSER_ACTION_SAVE,
 					"Cancel", gtk.RESPONSE_DELETE_EVENT,
 					"Save", gtk.RESPONSE_ACCEPT)
-				assertErr(err)
+				gtkutil.AssertErr(err)
 				defer fileDialog.Destroy()
 
 				fileFilter, err := gtk.FileFilterNew()
-				assertErr(err)
+				gtkutil.AssertErr(err)
 				fileFilter.SetName("Media files")
 				fileFilter.AddPattern("*.mp4")
 				fileDialog.AddFilter(fileFilter)
@@ -126,6 +122,7 @@ func main() {
 					recordButton.SetLabel("gtk-media-record")
 					captureAreaFrame.SetSensitive(true)
 					recOptionsFrame.SetSensitive(true)
+					audioFrame.SetSensitive(true)
 					destFileFrame.SetSensitive(true)
 					player.Stop()
 					return
@@ -161,8 +158,25 @@ func main() {
 					log.Fatalf("Cannot load screen media: %s\n", err)
 				}
 
+				// Configure audio capture. The audio source is added as a
+				// slave input of the screen media.
+				transcodeOpts := "vcodec=h264,vb=0,scale=1"
+				if captureAudioCheck.GetActive() {
+					if idx := audioSourceComboBox.GetActive(); idx >= 0 && idx < len(audioSources) {
+						if err := media.AddOptions(":input-slave=" + audioSources[idx].MRL); err != nil {
+							log.Fatalf("Cannot add media options: %s\n", err)
+						}
+
+						codec := screencap.AudioCodecs[0]
+						if idx := audioCodecComboBox.GetActive(); idx >= 0 && idx < len(screencap.AudioCodecs) {
+							codec = screencap.AudioCodecs[idx]
+						}
+						transcodeOpts += fmt.Sprintf(",acodec=%s,ab=%d,channels=2,samplerate=44100", codec.Codec, codec.Bitrate)
+					}
+				}
+
 				// Configure media to save the recording to the selected destination path.
-				saveOpt := fmt.Sprintf(":sout=#transcode{vcodec=h264,vb=0,scale=1}:duplicate{dst=file{dst=%s}}", destPath)
+				saveOpt := fmt.Sprintf(":sout=#transcode{%s}:duplicate{dst=file{dst=%s}}", transcodeOpts, destPath)
 				if err := media.AddOptions(saveOpt); err != nil {
 					log.Fatalf("Cannot add media options: %s\n", err)
 				}
@@ -180,6 +194,7 @@ func main() {
 				recordButton.SetLabel("gtk-media-stop")
 				captureAreaFrame.SetSensitive(false)
 				recOptionsFrame.SetSensitive(false)
+				audioFrame.SetSensitive(false)
 				destFileFrame.SetSensitive(false)
 			},
 			"onClickClose": func() {
@@ -193,12 +208,10 @@ func main() {
 	})
 
 	// Cleanup on exit.
-	app.Connect("shutdown", func() {
-		playerReleaseMedia(player)
-		player.Release()
-		vlc.Release()
+	app.OnShutdown(func() {
+		gtkvlc.ReleasePlayer(player)
 	})
 
 	// Launch the application.
-	os.Exit(app.Run(os.Args))
+	os.Exit(app.Run())
 }
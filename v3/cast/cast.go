@@ -0,0 +1,319 @@
+package main
+
+/*
+ * Renderer casting tool.
+ * libVLC renderer discovery modules (e.g. microdns_renderer) must be
+ * installed. See https://github.com/adrg/libvlc-go/wiki for installation
+ * instructions.
+ *
+ * Usage:
+ *   cast list [-timeout 5s]
+ *   cast play [-timeout 10s] -renderer <name> <media path or URL>
+ *   cast stop
+ */
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+
+	"github.com/adrg/libvlc-go-examples/v3/vlcutil"
+)
+
+// pidFile stores the process ID of the running `cast play` command, which
+// is used by `cast stop`.
+var pidFile = filepath.Join(os.TempDir(), "libvlc-go-cast.pid")
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage:
+  %[1]s list [-timeout 5s]
+  %[1]s play [-timeout 10s] -renderer <name> <media path or URL>
+  %[1]s stop
+`, filepath.Base(os.Args[0]))
+}
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "list":
+		err = listCmd(args)
+	case "play":
+		err = playCmd(args)
+	case "stop":
+		err = stopCmd(args)
+	case "-h", "-help", "--help", "help":
+		usage()
+	default:
+		log.Printf("Unknown command %q\n", cmd)
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func listCmd(args []string) error {
+	flags := flag.NewFlagSet("list", flag.ExitOnError)
+	timeout := flags.Duration("timeout", 5*time.Second, "discovery duration")
+	flags.Parse(args)
+
+	// Initialize libVLC.
+	if err := vlc.Init("--quiet"); err != nil {
+		return err
+	}
+	defer vlc.Release()
+
+	discoverer, err := vlcutil.NewRendererDiscoverer()
+	if err != nil {
+		return err
+	}
+	defer discoverer.Release()
+
+	return runList(os.Stdout, discoverer, *timeout)
+}
+
+// runList discovers renderers for the specified duration and writes them
+// to w, one per line.
+func runList(w io.Writer, d rendererDiscoverer, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	renderers, err := d.Discover(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if len(renderers) == 0 {
+		return errors.New("no renderers found")
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTYPE\tFLAGS\tICON")
+	for _, r := range renderers {
+		icon := r.IconURI
+		if icon == "" {
+			icon = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Name, r.Type, r.Capabilities(), icon)
+	}
+
+	return tw.Flush()
+}
+
+// selectRenderer discovers renderers until one having the specified name
+// is found or until the timeout elapses.
+func selectRenderer(d rendererDiscoverer, name string, timeout time.Duration) (*vlcutil.Renderer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	renderers, err := d.Discover(ctx, func(r *vlcutil.Renderer) bool {
+		return strings.EqualFold(r.Name, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return findRenderer(renderers, name)
+}
+
+func playCmd(args []string) error {
+	flags := flag.NewFlagSet("play", flag.ExitOnError)
+	timeout := flags.Duration("timeout", 10*time.Second, "maximum discovery duration")
+	rendererName := flags.String("renderer", "", "name of the renderer to cast to (required)")
+	flags.Parse(args)
+
+	if *rendererName == "" || flags.NArg() != 1 {
+		usage()
+		os.Exit(2)
+	}
+	mediaPath := flags.Arg(0)
+
+	// Initialize libVLC.
+	if err := vlc.Init("--quiet"); err != nil {
+		return err
+	}
+	defer vlc.Release()
+
+	discoverer, err := vlcutil.NewRendererDiscoverer()
+	if err != nil {
+		return err
+	}
+	defer discoverer.Release()
+
+	// Select renderer.
+	log.Printf("Searching for renderer %q...\n", *rendererName)
+	renderer, err := selectRenderer(discoverer, *rendererName, *timeout)
+	if err != nil {
+		return err
+	}
+
+	// Create a new player.
+	player, err := vlc.NewPlayer()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		player.Stop()
+		player.Release()
+	}()
+
+	// Load player media.
+	loadMedia := player.LoadMediaFromPath
+	if strings.Contains(mediaPath, "://") {
+		loadMedia = player.LoadMediaFromURL
+	}
+
+	media, err := loadMedia(mediaPath)
+	if err != nil {
+		return err
+	}
+	defer media.Release()
+
+	// Set renderer.
+	if err := player.SetRenderer(renderer.Renderer); err != nil {
+		return err
+	}
+
+	// Retrieve player event manager.
+	manager, err := player.EventManager()
+	if err != nil {
+		return err
+	}
+
+	// Register the player error and end reached events with the
+	// event manager.
+	done := make(chan error, 1)
+	eventCallback := func(event vlc.Event, userData interface{}) {
+		var err error
+		if event == vlc.MediaPlayerEncounteredError {
+			err = errors.New("playback failed")
+		}
+
+		// Do not block the libVLC event thread.
+		select {
+		case done <- err:
+		default:
+		}
+	}
+
+	eventIDs := make([]vlc.EventID, 0, 2)
+	for _, event := range []vlc.Event{vlc.MediaPlayerEncounteredError, vlc.MediaPlayerEndReached} {
+		eventID, err := manager.Attach(event, eventCallback, nil)
+		if err != nil {
+			return err
+		}
+		eventIDs = append(eventIDs, eventID)
+	}
+	defer manager.Detach(eventIDs...)
+
+	// Record the process ID, so that playback can be stopped using the
+	// stop command.
+	if err := os.WriteFile(pidFile, []byte(strconv.Itoa(os.Getpid())), 0644); err != nil {
+		return err
+	}
+	defer os.Remove(pidFile)
+
+	// Stop playback on SIGINT and SIGTERM.
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	// Read playback control keys.
+	raw := isTerminal(os.Stdin)
+	if raw {
+		restore, err := enableRawInput()
+		if err != nil {
+			raw = false
+			log.Printf("Cannot read single key presses: %s\n", err)
+		} else {
+			defer restore()
+		}
+	}
+	keys := readKeys(os.Stdin, raw)
+
+	// Start media playback.
+	if err := player.Play(); err != nil {
+		return err
+	}
+	log.Printf("Casting %s to %s (%s).\n", mediaPath, renderer.Name, renderer.Type)
+	log.Println(controlsHelp)
+
+	for {
+		select {
+		case key, ok := <-keys:
+			if !ok {
+				// Input closed. Keep casting until playback ends.
+				keys = nil
+				continue
+			}
+
+			quit, err := handleKey(player, key)
+			if err != nil {
+				log.Printf("Cannot handle key %q: %s\n", key, err)
+			}
+			if quit {
+				return nil
+			}
+		case sig := <-sigCh:
+			log.Printf("Received %s, stopping playback.\n", sig)
+			return nil
+		case err := <-done:
+			return err
+		}
+	}
+}
+
+func stopCmd(args []string) error {
+	flags := flag.NewFlagSet("stop", flag.ExitOnError)
+	flags.Parse(args)
+
+	data, err := os.ReadFile(pidFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return errors.New("no active casting session")
+	}
+	if err != nil {
+		return err
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return fmt.Errorf("invalid PID file %s: %w", pidFile, err)
+	}
+
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	if err := process.Signal(syscall.SIGTERM); err != nil {
+		// Signals other than kill are not supported on Windows.
+		if killErr := process.Kill(); killErr != nil {
+			os.Remove(pidFile)
+			return fmt.Errorf("cannot stop casting session (PID %d): %w", pid, err)
+		}
+	}
+
+	// Killed processes cannot remove their PID file, so remove it here.
+	// Stopped processes might not have removed it yet either.
+	os.Remove(pidFile)
+	log.Printf("Stopped casting session (PID %d).\n", pid)
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrg/libvlc-go-examples/v3/vlcutil"
+)
+
+// fakeDiscoverer is a renderer discoverer which returns a predefined list
+// of renderers, so that no real devices are needed.
+type fakeDiscoverer struct {
+	renderers []*vlcutil.Renderer
+}
+
+func (d *fakeDiscoverer) Discover(ctx context.Context, match func(*vlcutil.Renderer) bool) ([]*vlcutil.Renderer, error) {
+	var renderers []*vlcutil.Renderer
+	for _, r := range d.renderers {
+		renderers = append(renderers, r)
+		if match != nil && match(r) {
+			break
+		}
+	}
+
+	return renderers, nil
+}
+
+func (d *fakeDiscoverer) Release() error {
+	return nil
+}
+
+func newFakeDiscoverer() *fakeDiscoverer {
+	return &fakeDiscoverer{
+		renderers: []*vlcutil.Renderer{
+			{Name: "Living Room TV", Type: "chromecast", IconURI: "http://192.168.1.10:8008/setup/icon.png", Audio: true, Video: true},
+			{Name: "Kitchen Speaker", Type: "chromecast", Audio: true},
+			{Name: "Bedroom", Type: "upnp"},
+		},
+	}
+}
+
+func TestRunList(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runList(&buf, newFakeDiscoverer(), time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %d:\n%s", len(lines), buf.String())
+	}
+
+	expected := [][]string{
+		{"NAME", "TYPE", "FLAGS", "ICON"},
+		{"Living", "Room", "TV", "chromecast", "audio+video", "http://192.168.1.10:8008/setup/icon.png"},
+		{"Kitchen", "Speaker", "chromecast", "audio", "-"},
+		{"Bedroom", "upnp", "-", "-"},
+	}
+	for i, line := range lines {
+		if fields := strings.Fields(line); strings.Join(fields, " ") != strings.Join(expected[i], " ") {
+			t.Errorf("line %d: expected %q, got %q", i, expected[i], fields)
+		}
+	}
+}
+
+func TestRunListNoRenderers(t *testing.T) {
+	var buf bytes.Buffer
+	if err := runList(&buf, &fakeDiscoverer{}, time.Second); err == nil {
+		t.Fatal("expected error when no renderers are found")
+	}
+}
+
+func TestSelectRenderer(t *testing.T) {
+	d := newFakeDiscoverer()
+
+	r, err := selectRenderer(d, "kitchen speaker", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Name != "Kitchen Speaker" {
+		t.Errorf("expected Kitchen Speaker, got %q", r.Name)
+	}
+
+	_, err = selectRenderer(d, "Garage", time.Second)
+	if err == nil {
+		t.Fatal("expected error for unknown renderer")
+	}
+	if !strings.Contains(err.Error(), `"Living Room TV"`) {
+		t.Errorf("expected error to list available renderers, got %q", err)
+	}
+}
+
+// fakePlayer records the playback operations performed by the control keys.
+type fakePlayer struct {
+	paused bool
+	time   int
+	volume int
+}
+
+func (p *fakePlayer) TogglePause() error       { p.paused = !p.paused; return nil }
+func (p *fakePlayer) MediaTime() (int, error)  { return p.time, nil }
+func (p *fakePlayer) SetMediaTime(t int) error { p.time = t; return nil }
+func (p *fakePlayer) Volume() (int, error)     { return p.volume, nil }
+func (p *fakePlayer) SetVolume(v int) error    { p.volume = v; return nil }
+
+func TestHandleKey(t *testing.T) {
+	p := &fakePlayer{time: 5000, volume: 98}
+
+	steps := []struct {
+		key    string
+		quit   bool
+		paused bool
+		time   int
+		volume int
+	}{
+		{key: " ", paused: true, time: 5000, volume: 98},
+		{key: "p", time: 5000, volume: 98},
+		{key: keyRight, time: 15000, volume: 98},
+		{key: "b", time: 5000, volume: 98},
+		{key: keyLeft, time: 0, volume: 98},
+		{key: "+", time: 0, volume: 100},
+		{key: "-", time: 0, volume: 95},
+		{key: "x", time: 0, volume: 95},
+		{key: "q", quit: true, time: 0, volume: 95},
+	}
+	for _, step := range steps {
+		quit, err := handleKey(p, step.key)
+		if err != nil {
+			t.Fatalf("key %q: %s", step.key, err)
+		}
+		if quit != step.quit || p.paused != step.paused || p.time != step.time || p.volume != step.volume {
+			t.Errorf("key %q: got quit=%t paused=%t time=%d volume=%d, expected quit=%t paused=%t time=%d volume=%d",
+				step.key, quit, p.paused, p.time, p.volume, step.quit, step.paused, step.time, step.volume)
+		}
+	}
+}
+
+func TestReadKeys(t *testing.T) {
+	tests := []struct {
+		input    string
+		raw      bool
+		expected []string
+	}{
+		{input: "p\x1b[Cq", raw: true, expected: []string{"p", keyRight, "q"}},
+		{input: " \x1b[D+", raw: true, expected: []string{" ", keyLeft, "+"}},
+		{input: "p\n\nf\nq", raw: false, expected: []string{"p", "f", "q"}},
+	}
+	for _, test := range tests {
+		var keys []string
+		for key := range readKeys(strings.NewReader(test.input), test.raw) {
+			keys = append(keys, key)
+		}
+
+		if strings.Join(keys, ",") != strings.Join(test.expected, ",") {
+			t.Errorf("input %q: expected %q, got %q", test.input, test.expected, keys)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// playbackController represents the player operations used by the
+// playback control keys. It is implemented by *vlc.Player.
+type playbackController interface {
+	TogglePause() error
+	MediaTime() (int, error)
+	SetMediaTime(t int) error
+	Volume() (int, error)
+	SetVolume(volume int) error
+}
+
+const (
+	seekStep   = 10000 // milliseconds
+	volumeStep = 5
+)
+
+// Playback control keys. Arrow keys are reported as keyLeft and keyRight.
+const (
+	keyLeft  = "left"
+	keyRight = "right"
+)
+
+const controlsHelp = "Controls: [space/p] pause  [b/left] -10s  [f/right] +10s  [-/+] volume  [q] quit"
+
+// handleKey performs the playback action associated with the specified key.
+// Returns true if the key requests playback to be stopped.
+func handleKey(p playbackController, key string) (quit bool, err error) {
+	switch key {
+	case " ", "p":
+		return false, p.TogglePause()
+	case "b", keyLeft:
+		return false, seek(p, -seekStep)
+	case "f", keyRight:
+		return false, seek(p, seekStep)
+	case "-":
+		return false, changeVolume(p, -volumeStep)
+	case "+", "=":
+		return false, changeVolume(p, volumeStep)
+	case "q":
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func seek(p playbackController, offset int) error {
+	t, err := p.MediaTime()
+	if err != nil {
+		return err
+	}
+
+	if t += offset; t < 0 {
+		t = 0
+	}
+	return p.SetMediaTime(t)
+}
+
+func changeVolume(p playbackController, offset int) error {
+	volume, err := p.Volume()
+	if err != nil {
+		return err
+	}
+
+	volume += offset
+	if volume < 0 {
+		volume = 0
+	}
+	if volume > 100 {
+		volume = 100
+	}
+	return p.SetVolume(volume)
+}
+
+// readKeys reads keys from r and sends them on the returned channel, which
+// is closed when r is exhausted. In raw mode, keys are read one byte at a
+// time and arrow key escape sequences are decoded. Otherwise, each line is
+// treated as a key, which allows controlling playback when the input is
+// not a terminal (e.g. a pipe). In line mode, the pause key is `p`.
+func readKeys(r io.Reader, raw bool) <-chan string {
+	keys := make(chan string)
+
+	go func() {
+		defer close(keys)
+
+		reader := bufio.NewReader(r)
+		if !raw {
+			for {
+				line, err := reader.ReadString('\n')
+				if key := strings.TrimSpace(line); key != "" {
+					keys <- key
+				}
+				if err != nil {
+					return
+				}
+			}
+		}
+
+		for {
+			b, err := reader.ReadByte()
+			if err != nil {
+				return
+			}
+			if b != 0x1b {
+				keys <- string(b)
+				continue
+			}
+
+			// Decode arrow keys (ESC [ C and ESC [ D).
+			seq := make([]byte, 2)
+			if _, err := io.ReadFull(reader, seq); err != nil {
+				return
+			}
+			switch string(seq) {
+			case "[C":
+				keys <- keyRight
+			case "[D":
+				keys <- keyLeft
+			}
+		}
+	}()
+
+	return keys
+}
+
+// isTerminal returns true if the specified file is a character device.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// enableRawInput switches the terminal connected to the standard input to
+// cbreak mode, so that keys can be read without waiting for a newline.
+// The returned function restores the previous terminal state.
+func enableRawInput() (func(), error) {
+	state, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty("cbreak", "-echo"); err != nil {
+		return nil, err
+	}
+
+	return func() { stty(strings.TrimSpace(state)) }, nil
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("stty %s: %w", strings.Join(args, " "), err)
+	}
+
+	return string(output), nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/adrg/libvlc-go-examples/v3/vlcutil"
+)
+
+// rendererDiscoverer discovers renderers on the local network.
+type rendererDiscoverer interface {
+	// Discover searches for renderers until the context is done or until
+	// the match function returns true for a discovered renderer. The match
+	// function can be nil. Returns all the renderers found.
+	Discover(ctx context.Context, match func(*vlcutil.Renderer) bool) ([]*vlcutil.Renderer, error)
+
+	// Release releases the discoverer. The renderers returned by Discover
+	// must not be used after the discoverer is released.
+	Release() error
+}
+
+// findRenderer returns the renderer having the specified name. The name
+// is matched case-insensitively.
+func findRenderer(renderers []*vlcutil.Renderer, name string) (*vlcutil.Renderer, error) {
+	for _, renderer := range renderers {
+		if strings.EqualFold(renderer.Name, name) {
+			return renderer, nil
+		}
+	}
+
+	names := make([]string, 0, len(renderers))
+	for _, renderer := range renderers {
+		names = append(names, fmt.Sprintf("%q", renderer.Name))
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("renderer %q not found: no renderers discovered", name)
+	}
+
+	return nil, fmt.Errorf("renderer %q not found. Available renderers: %s", name, strings.Join(names, ", "))
+}
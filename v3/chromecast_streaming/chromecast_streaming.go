@@ -1,13 +1,38 @@
 package main
 
+/*
+ * Usage:
+ *   chromecast_streaming [-timeout 10s] [-name <renderer name>]
+ *                        [-type chromecast] [-wait-all]
+ *
+ * By default, the media is streamed to the first renderer matching the
+ * filters. With -wait-all, all the renderers found within the timeout are
+ * printed and no media is streamed. The program fails if no renderer
+ * matches the filters within the timeout.
+ */
 import (
-	"errors"
+	"context"
+	"flag"
+	"fmt"
 	"log"
+	"time"
 
 	vlc "github.com/adrg/libvlc-go/v3"
+
+	"github.com/adrg/libvlc-go-examples/v3/vlcutil"
 )
 
 func main() {
+	var (
+		timeout      = flag.Duration("timeout", 10*time.Second, "maximum renderer discovery duration")
+		name         = flag.String("name", "", "name of the renderer (case insensitive)")
+		rendererType = flag.String("type", string(vlc.RendererChromecast), "type of the renderer (empty matches all types)")
+		waitAll      = flag.Bool("wait-all", false, "print all the renderers found within the timeout and exit")
+	)
+	flag.Parse()
+
+	filter := vlcutil.RendererFilter{Name: *name, Type: *rendererType}
+
 	// Initialize libVLC. Additional command line arguments can be passed in
 	// to libVLC by specifying them in the Init function.
 	if err := vlc.Init("--quiet"); err != nil {
@@ -15,18 +40,43 @@ func main() {
 	}
 	defer vlc.Release()
 
-	// Get discovery service.
-	discoverer, err := getDiscoverer()
-	if err != nil {
-		log.Fatal(err)
+	// Renderers are discovered using all the discovery services provided
+	// by libVLC (e.g. microdns_renderer).
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	// List renderers.
+	if *waitAll {
+		discoverer, err := vlcutil.NewRendererDiscoverer()
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer discoverer.Release()
+
+		renderers, err := discoverer.Discover(ctx, nil)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		var found bool
+		for _, renderer := range renderers {
+			if filter.Match(renderer) {
+				fmt.Printf("%s (%s)\n", renderer.Name, renderer.Type)
+				found = true
+			}
+		}
+		if !found {
+			log.Fatalf("No renderer matching filter (%s) found within %s\n", filter, *timeout)
+		}
+		return
 	}
-	defer discoverer.Release()
 
-	// Get Chromecast renderer.
-	renderer, err := getRenderer(discoverer)
+	// Get renderer.
+	renderer, err := vlcutil.DiscoverRenderer(ctx, filter)
 	if err != nil {
 		log.Fatal(err)
 	}
+	defer renderer.Release()
 
 	// Create a new player.
 	player, err := vlc.NewPlayer()
@@ -46,7 +96,7 @@ func main() {
 	defer media.Release()
 
 	// Set renderer.
-	if err := player.SetRenderer(renderer); err != nil {
+	if err := player.SetRenderer(renderer.Renderer); err != nil {
 		log.Fatal(err)
 	}
 
@@ -75,64 +125,3 @@ func main() {
 
 	<-quit
 }
-
-func getDiscoverer() (*vlc.RendererDiscoverer, error) {
-	// Get discovery service descriptors.
-	descriptors, err := vlc.ListRendererDiscoverers()
-	if err != nil {
-		return nil, err
-	}
-
-	// Search for mDNS discovery service.
-	for _, descriptor := range descriptors {
-		if descriptor.Name != "microdns_renderer" {
-			continue
-		}
-
-		discoverer, err := vlc.NewRendererDiscoverer(descriptor.Name)
-		if err != nil {
-			return nil, err
-		}
-
-		return discoverer, nil
-	}
-
-	return nil, errors.New("could not find discovery service")
-}
-
-func getRenderer(discoverer *vlc.RendererDiscoverer) (*vlc.Renderer, error) {
-	// Start renderer discovery.
-	var renderer *vlc.Renderer
-
-	stop := make(chan error)
-	if err := discoverer.Start(func(event vlc.Event, r *vlc.Renderer) {
-		// NOTE: the discovery service cannot be stopped or released from
-		// the callback function. Doing so will result in undefined behavior.
-
-		switch event {
-		case vlc.RendererDiscovererItemAdded:
-			// New renderer (`r`) found.
-			rendererType, err := r.Type()
-			if err != nil {
-				stop <- err
-			}
-			if rendererType == vlc.RendererChromecast {
-				renderer = r
-				stop <- nil
-			}
-		case vlc.RendererDiscovererItemDeleted:
-			// The renderer (`r`) is no longer available.
-		}
-	}); err != nil {
-		return nil, err
-	}
-
-	if err := <-stop; err != nil {
-		return nil, err
-	}
-	if err := discoverer.Stop(); err != nil {
-		return nil, err
-	}
-
-	return renderer, nil
-}
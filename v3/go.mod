@@ -0,0 +1,18 @@
+module github.com/adrg/libvlc-go-examples/v3
+
+go 1.21
+
+require (
+	github.com/adrg/libvlc-go-examples/shared v0.0.0-00010101000000-000000000000
+	github.com/adrg/libvlc-go/v3 v3.1.5
+	github.com/gotk3/gotk3 v0.6.2
+	github.com/mattn/go-gtk v0.0.0-20190405072524-4deadb416788
+	go.etcd.io/bbolt v1.3.10
+)
+
+require (
+	github.com/mattn/go-pointer v0.0.1 // indirect
+	golang.org/x/sys v0.20.0 // indirect
+)
+
+replace github.com/adrg/libvlc-go-examples/shared => ../shared
@@ -0,0 +1,107 @@
+//go:build gtk2
+
+package main
+
+import (
+	"unsafe"
+
+	"github.com/adrg/libvlc-go-examples/shared/screencap"
+	"github.com/mattn/go-gtk/gdk"
+	"github.com/mattn/go-gtk/glib"
+	"github.com/mattn/go-gtk/gtk"
+)
+
+// selectScreenArea opens a translucent overlay covering the screen, on
+// which the user can select an area by dragging a rectangle. The callback
+// is called with the selected area once the mouse button is released. The
+// selection is cancelled by pressing Escape or the right mouse button, in
+// which case the callback is called with ok set to false. A compositing
+// window manager is required for the overlay to be translucent.
+func selectScreenArea(callback func(area screencap.Area, ok bool)) {
+	overlay := gtk.NewWindow(gtk.WINDOW_TOPLEVEL)
+	overlay.SetDecorated(false)
+	overlay.SetKeepAbove(true)
+	overlay.SetSkipTaskbarHint(true)
+	overlay.SetOpacity(0.4)
+	overlay.ModifyBG(gtk.STATE_NORMAL, gdk.NewColor("black"))
+	overlay.SetEvents(int(gdk.BUTTON_PRESS_MASK | gdk.BUTTON_RELEASE_MASK | gdk.POINTER_MOTION_MASK | gdk.KEY_PRESS_MASK))
+
+	// Cover the whole screen, including all monitors.
+	overlay.Move(0, 0)
+	overlay.Resize(gdk.ScreenWidth(), gdk.ScreenHeight())
+
+	var (
+		dragging       bool
+		startX, startY int
+		area           screencap.Area
+		done           bool
+	)
+
+	finish := func(ok bool) {
+		if done {
+			return
+		}
+		done = true
+
+		overlay.Destroy()
+		callback(area, ok && !area.IsEmpty())
+	}
+	overlay.Connect("destroy", func(ctx *glib.CallbackContext) {
+		finish(false)
+	})
+
+	overlay.Connect("expose-event", func(ctx *glib.CallbackContext) {
+		if area.IsEmpty() {
+			return
+		}
+
+		// Highlight the selected area.
+		drawable := overlay.GetWindow().GetDrawable()
+		gc := gdk.NewGC(drawable)
+		gc.SetRgbFgColor(gdk.NewColor("white"))
+		drawable.DrawRectangle(gc, true, area.X, area.Y, area.Width, area.Height)
+	})
+	overlay.Connect("button-press-event", func(ctx *glib.CallbackContext) {
+		arg := ctx.Args(0)
+		event := *(**gdk.EventButton)(unsafe.Pointer(&arg))
+		if event.Button != 1 {
+			finish(false)
+			return
+		}
+
+		dragging = true
+		startX, startY = int(event.XRoot), int(event.YRoot)
+		area = screencap.Area{X: startX, Y: startY}
+	})
+	overlay.Connect("motion-notify-event", func(ctx *glib.CallbackContext) {
+		if !dragging {
+			return
+		}
+
+		arg := ctx.Args(0)
+		event := *(**gdk.EventMotion)(unsafe.Pointer(&arg))
+		area = screencap.NewArea(startX, startY, int(event.XRoot), int(event.YRoot))
+		overlay.QueueDraw()
+	})
+	overlay.Connect("button-release-event", func(ctx *glib.CallbackContext) {
+		if !dragging {
+			return
+		}
+		dragging = false
+
+		arg := ctx.Args(0)
+		event := *(**gdk.EventButton)(unsafe.Pointer(&arg))
+		area = screencap.NewArea(startX, startY, int(event.XRoot), int(event.YRoot))
+		finish(true)
+	})
+	overlay.Connect("key-press-event", func(ctx *glib.CallbackContext) {
+		arg := ctx.Args(0)
+		event := *(**gdk.EventKey)(unsafe.Pointer(&arg))
+		if event.Keyval == gdk.KEY_Escape {
+			finish(false)
+		}
+	})
+
+	overlay.ShowAll()
+	overlay.Present()
+}
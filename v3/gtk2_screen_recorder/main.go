@@ -1,3 +1,5 @@
+//go:build gtk2
+
 package main
 
 /*
@@ -10,11 +12,16 @@ package main
 import (
 	"fmt"
 	"log"
+	"os"
+	"time"
 
 	vlc "github.com/adrg/libvlc-go/v3"
 	"github.com/mattn/go-gtk/gdk"
 	"github.com/mattn/go-gtk/glib"
 	"github.com/mattn/go-gtk/gtk"
+
+	"github.com/adrg/libvlc-go-examples/shared/screencap"
+	"github.com/adrg/libvlc-go-examples/v3/vlcutil"
 )
 
 func main() {
@@ -99,9 +106,77 @@ func main() {
 	areaBox.PackStart(yBox, false, false, 10)
 	areaBox.PackStart(wBox, false, false, 10)
 	areaBox.PackStart(hBox, false, false, 10)
+
+	// Create area picking controls.
+	setCaptureArea := func(area screencap.Area) {
+		xInput.SetValue(float64(area.X))
+		yInput.SetValue(float64(area.Y))
+		wInput.SetValue(float64(area.Width))
+		hInput.SetValue(float64(area.Height))
+
+		areaSelectRadio2.SetActive(true)
+		areaBox.SetSensitive(true)
+	}
+
+	var statusLabel *gtk.Label
+	selectAreaButton := gtk.NewButtonWithLabel("Select area...")
+	selectAreaButton.SetTooltipText("Drag a rectangle on the screen to select the capture area")
+	selectAreaButton.Connect("clicked", func(ctx *glib.CallbackContext) {
+		// Hide the application window while selecting the area.
+		window.Hide()
+		selectScreenArea(func(area screencap.Area, ok bool) {
+			window.Show()
+			if ok {
+				setCaptureArea(area)
+				statusLabel.SetText("Capture area: " + area.String())
+			}
+		})
+	})
+
+	pickWindowButton := gtk.NewButtonWithLabel("Pick window...")
+	pickWindowButton.SetTooltipText("Click on a window to capture the area occupied by it")
+	pickWindowButton.Connect("clicked", func(ctx *glib.CallbackContext) {
+		statusLabel.SetText("Click on the window to capture...")
+		pickWindowButton.SetSensitive(false)
+
+		type pickResult struct {
+			area screencap.Area
+			err  error
+		}
+
+		// Wait for the window to be selected without blocking the main loop.
+		resultCh := make(chan pickResult, 1)
+		go func() {
+			area, err := screencap.PickWindowArea()
+			resultCh <- pickResult{area: area, err: err}
+		}()
+
+		glib.TimeoutAdd(100, func() bool {
+			select {
+			case result := <-resultCh:
+				pickWindowButton.SetSensitive(true)
+				if result.err != nil {
+					statusLabel.SetText(result.err.Error())
+					return false
+				}
+
+				setCaptureArea(result.area)
+				statusLabel.SetText("Capture area: " + result.area.String())
+				return false
+			default:
+				return true
+			}
+		})
+	})
+
+	areaPickBox := gtk.NewHBox(true, 0)
+	areaPickBox.PackStart(selectAreaButton, true, true, 10)
+	areaPickBox.PackStart(pickWindowButton, true, true, 10)
+
 	areaBoxSpacer := gtk.NewVBox(false, 0)
 	areaBoxSpacer.PackStart(areaSelectBox, false, false, 10)
 	areaBoxSpacer.PackStart(areaBox, true, true, 10)
+	areaBoxSpacer.PackStart(areaPickBox, false, false, 10)
 
 	areaFrame := gtk.NewFrame("Capture area")
 	areaFrame.Add(areaBoxSpacer)
@@ -136,6 +211,42 @@ func main() {
 	recordingSpacer.PackStart(recordingFrame, true, true, 10)
 	container.PackStart(recordingSpacer, true, true, 10)
 
+	// Create audio options layout.
+	audioSources := screencap.ListAudioSources()
+	audioSourceCombo := gtk.NewComboBoxText()
+	for _, source := range audioSources {
+		audioSourceCombo.AppendText(source.Name)
+	}
+	audioSourceCombo.SetActive(0)
+
+	audioCodecCombo := gtk.NewComboBoxText()
+	for _, codec := range screencap.AudioCodecs {
+		audioCodecCombo.AppendText(codec.Name)
+	}
+	audioCodecCombo.SetActive(0)
+
+	audioOptsBox := gtk.NewHBox(false, 0)
+	audioOptsBox.SetSensitive(false)
+	audioOptsBox.PackStart(audioSourceCombo, true, true, 10)
+	audioOptsBox.PackStart(audioCodecCombo, false, false, 10)
+
+	captureAudioCheck := gtk.NewCheckButtonWithLabel("Capture audio")
+	captureAudioCheck.Connect("toggled", func(ctx *glib.CallbackContext) {
+		audioOptsBox.SetSensitive(captureAudioCheck.GetActive())
+	})
+
+	audioBox := gtk.NewHBox(false, 0)
+	audioBox.PackStart(captureAudioCheck, false, false, 10)
+	audioBox.PackStart(audioOptsBox, true, true, 0)
+	audioBoxSpacer := gtk.NewVBox(false, 0)
+	audioBoxSpacer.PackStart(audioBox, true, true, 10)
+
+	audioFrame := gtk.NewFrame("Audio")
+	audioFrame.Add(audioBoxSpacer)
+	audioSpacer := gtk.NewHBox(false, 0)
+	audioSpacer.PackStart(audioFrame, true, true, 10)
+	container.PackStart(audioSpacer, true, true, 10)
+
 	// Create destination file layout.
 	destInput := gtk.NewEntry()
 	destInput.SetEditable(false)
@@ -174,15 +285,112 @@ func main() {
 	destSpacer.PackStart(destFrame, true, true, 10)
 	container.PackStart(destSpacer, true, true, 10)
 
+	// Create recording limits layout.
+	maxDurationLabel := gtk.NewLabel("Max minutes")
+	maxDurationInput := gtk.NewSpinButtonWithRange(0, 100000, 1)
+	maxDurationInput.SetValue(0)
+	maxDurationBox := gtk.NewHBox(false, 0)
+	maxDurationBox.PackStart(maxDurationInput, false, false, 5)
+	maxDurationBox.PackStart(maxDurationLabel, false, false, 0)
+
+	maxSizeLabel := gtk.NewLabel("Max MiB")
+	maxSizeInput := gtk.NewSpinButtonWithRange(0, 10000000, 10)
+	maxSizeInput.SetValue(0)
+	maxSizeBox := gtk.NewHBox(false, 0)
+	maxSizeBox.PackStart(maxSizeInput, false, false, 5)
+	maxSizeBox.PackStart(maxSizeLabel, false, false, 0)
+
+	limitsBox := gtk.NewHBox(false, 0)
+	limitsBox.PackStart(maxDurationBox, false, false, 10)
+	limitsBox.PackEnd(maxSizeBox, false, false, 10)
+	limitsBoxSpacer := gtk.NewVBox(false, 0)
+	limitsBoxSpacer.PackStart(limitsBox, true, true, 10)
+
+	limitsFrame := gtk.NewFrame("Limits (0 for unlimited)")
+	limitsFrame.Add(limitsBoxSpacer)
+	limitsSpacer := gtk.NewHBox(false, 0)
+	limitsSpacer.PackStart(limitsFrame, true, true, 10)
+	container.PackStart(limitsSpacer, true, true, 10)
+
+	// Create status layout.
+	statusLabel = gtk.NewLabel("")
+	statusLabel.SetAlignment(0, 0)
+	statusBox := gtk.NewHBox(false, 0)
+	statusBox.PackStart(statusLabel, true, true, 10)
+	container.PackStart(statusBox, false, false, 0)
+
 	// Create controls layout.
+	var (
+		recording     bool
+		recordingID   int
+		recordingPath string
+	)
+
 	recordButton := gtk.NewButtonFromStock("gtk-media-record")
+	setControlsSensitive := func(sensitive bool) {
+		areaFrame.SetSensitive(sensitive)
+		recordingFrame.SetSensitive(sensitive)
+		audioFrame.SetSensitive(sensitive)
+		limitsFrame.SetSensitive(sensitive)
+		destFrame.SetSensitive(sensitive)
+	}
+
+	stopRecording := func(reason string) {
+		if !recording {
+			return
+		}
+		recording = false
+
+		// Stopping the player finalizes the output file.
+		player.Stop()
+		if media, _ := player.Media(); media != nil {
+			media.Release()
+		}
+
+		recordButton.SetLabel("gtk-media-record")
+		setControlsSensitive(true)
+
+		status := fmt.Sprintf("Saved %s", recordingPath)
+		if info, err := os.Stat(recordingPath); err == nil {
+			status += fmt.Sprintf(" (%s)", vlcutil.FormatFileSize(info.Size()))
+		}
+		if reason != "" {
+			status = reason + " " + status
+		}
+		statusLabel.SetText(status)
+	}
+
+	// Periodically updates the recording status and stops the recording
+	// when one of the configured limits is reached.
+	watchRecording := func(id int, limits vlcutil.RecordingLimits) {
+		start := time.Now()
+		statusLabel.SetText(vlcutil.NewRecordingStatus(player, time.Since(start), recordingPath).String())
+
+		glib.TimeoutAdd(500, func() bool {
+			if !recording || id != recordingID {
+				return false
+			}
+
+			// Stop recording if the player stopped unexpectedly.
+			if state, err := player.MediaState(); err == nil && (state == vlc.MediaEnded || state == vlc.MediaError) {
+				stopRecording("Recording stopped unexpectedly.")
+				return false
+			}
+
+			status := vlcutil.NewRecordingStatus(player, time.Since(start), recordingPath)
+			if status.Exceeds(limits) {
+				stopRecording("Recording limit reached.")
+				return false
+			}
+			statusLabel.SetText(status.String())
+
+			return true
+		})
+	}
+
 	recordButton.Connect("clicked", func(ctx *glib.CallbackContext) {
-		if player.IsPlaying() {
-			recordButton.SetLabel("gtk-media-record")
-			areaFrame.SetSensitive(true)
-			recordingFrame.SetSensitive(true)
-			destFrame.SetSensitive(true)
-			player.Stop()
+		if recording {
+			stopRecording("")
 			return
 		}
 
@@ -212,8 +420,25 @@ func main() {
 			log.Fatalf("Cannot load screen media: %s\n", err)
 		}
 
+		// Configure audio capture. The audio source is added as a slave
+		// input of the screen media.
+		transcodeOpts := "vcodec=h264,vb=0,scale=1"
+		if captureAudioCheck.GetActive() {
+			if idx := audioSourceCombo.GetActive(); idx >= 0 && idx < len(audioSources) {
+				if err := media.AddOptions(":input-slave=" + audioSources[idx].MRL); err != nil {
+					log.Fatalf("Cannot add media options: %s\n", err)
+				}
+
+				codec := screencap.AudioCodecs[0]
+				if idx := audioCodecCombo.GetActive(); idx >= 0 && idx < len(screencap.AudioCodecs) {
+					codec = screencap.AudioCodecs[idx]
+				}
+				transcodeOpts += fmt.Sprintf(",acodec=%s,ab=%d,channels=2,samplerate=44100", codec.Codec, codec.Bitrate)
+			}
+		}
+
 		// Configure media to save the recording to the selected destination path.
-		saveOpt := fmt.Sprintf(":sout=#transcode{vcodec=h264,vb=0,scale=1}:duplicate{dst=file{dst=%s}}", destPath)
+		saveOpt := fmt.Sprintf(":sout=#transcode{%s}:duplicate{dst=file{dst=%s}}", transcodeOpts, destPath)
 		if err := media.AddOptions(saveOpt); err != nil {
 			log.Fatalf("Cannot add media options: %s\n", err)
 		}
@@ -228,10 +453,18 @@ func main() {
 			log.Fatalf("Cannot play media: %s\n", err)
 		}
 
+		recording = true
+		recordingID++
+		recordingPath = destPath
+
 		recordButton.SetLabel("gtk-media-stop")
-		areaFrame.SetSensitive(false)
-		destFrame.SetSensitive(false)
-		recordingFrame.SetSensitive(false)
+		setControlsSensitive(false)
+
+		// Monitor recording.
+		watchRecording(recordingID, vlcutil.RecordingLimits{
+			MaxDuration: time.Duration(maxDurationInput.GetValue()) * time.Minute,
+			MaxSize:     int64(maxSizeInput.GetValue()) * 1024 * 1024,
+		})
 	})
 
 	exitButton := gtk.NewButtonFromStock("gtk-close")
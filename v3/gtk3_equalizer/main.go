@@ -1,44 +1,27 @@
+//go:build gtk3
+
 package main
 
 import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	vlc "github.com/adrg/libvlc-go/v3"
-	"github.com/gotk3/gotk3/glib"
 	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/adrg/libvlc-go-examples/shared/gtkutil"
+	"github.com/adrg/libvlc-go-examples/v3/internal/eqprofile"
+	"github.com/adrg/libvlc-go-examples/v3/internal/gtkvlc"
+	"github.com/adrg/libvlc-go-examples/v3/vlcutil"
 )
 
 const appID = "com.github.libvlc-go.gtk3-equalizer-example"
 
-func builderGetObject(builder *gtk.Builder, name string) glib.IObject {
-	obj, _ := builder.GetObject(name)
-	return obj
-}
-
-func assertErr(err error) {
-	if err != nil {
-		log.Panic(err)
-	}
-}
-
-func assertConv(ok bool) {
-	if !ok {
-		log.Panic("invalid widget conversion")
-	}
-}
-
-func playerReleaseMedia(player *vlc.Player) {
-	player.Stop()
-	if media, _ := player.Media(); media != nil {
-		media.Release()
-	}
-}
-
 func addFreqScale(label string, container *gtk.Box) *gtk.Scale {
 	freqScale, err := gtk.ScaleNewWithRange(gtk.ORIENTATION_VERTICAL, -20, 20, 0.1)
-	assertErr(err)
+	gtkutil.AssertErr(err)
 	freqScale.SetValue(0)
 	freqScale.SetVExpand(true)
 	freqScale.SetHAlign(gtk.ALIGN_CENTER)
@@ -46,14 +29,14 @@ func addFreqScale(label string, container *gtk.Box) *gtk.Scale {
 	freqScale.SetIncrements(0.1, 0.5)
 
 	freqLabel, err := gtk.LabelNew(label)
-	assertErr(err)
+	gtkutil.AssertErr(err)
 	freqLabel.SetHAlign(gtk.ALIGN_CENTER)
 	freqLabel.SetMarginTop(5)
 
 	freqBox, err := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 0)
 	freqBox.SetVExpand(true)
 	freqBox.SetHExpand(true)
-	assertErr(err)
+	gtkutil.AssertErr(err)
 	freqBox.Add(freqScale)
 	freqBox.Add(freqLabel)
 	freqBox.SetMarginTop(5)
@@ -64,13 +47,13 @@ func addFreqScale(label string, container *gtk.Box) *gtk.Scale {
 }
 
 func main() {
-	// Initialize libVLC module.
-	err := vlc.Init("--quiet", "--no-xlib")
-	assertErr(err)
+	// Initialize libVLC module and create new GTK application.
+	app, err := gtkvlc.NewApp(appID)
+	gtkutil.AssertErr(err)
 
 	// Create a new player.
 	player, err := vlc.NewPlayer()
-	assertErr(err)
+	gtkutil.AssertErr(err)
 
 	// Get equalizer preset names and band frequencies.
 	var (
@@ -79,31 +62,33 @@ func main() {
 		equalizer   *vlc.Equalizer
 	)
 
+	// Load equalizer profile rules.
+	rules, err := eqprofile.LoadRules()
+	if err != nil {
+		log.Printf("Cannot load equalizer profile rules: %s\n", err)
+	}
+
+	var (
+		mediaPath   string
+		mediaGenre  string
+		cancelParse func()
+	)
+
 	releaseEqualizer := func() {
 		if equalizer != nil {
 			err = player.SetEqualizer(nil)
-			assertErr(err)
+			gtkutil.AssertErr(err)
 			equalizer.Release()
 			equalizer = nil
 		}
 	}
 
-	// Create new GTK application.
-	app, err := gtk.ApplicationNew(appID, glib.APPLICATION_FLAGS_NONE)
-	assertErr(err)
-
-	app.Connect("activate", func() {
-		// Load application layout.
-		builder, err := gtk.BuilderNewFromFile("layout.glade")
-		assertErr(err)
-
+	app.OnActivate("layout.glade", func(builder *gtk.Builder) {
 		// Get application window.
-		appWin, ok := builderGetObject(builder, "appWindow").(*gtk.ApplicationWindow)
-		assertConv(ok)
+		appWin := gtkutil.MustGet[*gtk.ApplicationWindow](builder, "appWindow")
 
 		// Get presets combo box.
-		presetsComboBox, ok := builderGetObject(builder, "presetsComboBox").(*gtk.ComboBoxText)
-		assertConv(ok)
+		presetsComboBox := gtkutil.MustGet[*gtk.ComboBoxText](builder, "presetsComboBox")
 
 		// Fill presets combo box.
 		for _, presetName := range presetNames {
@@ -111,35 +96,32 @@ func main() {
 		}
 
 		// Get adjustments box.
-		adjustmentsBox, ok := builderGetObject(builder, "adjustmentsBox").(*gtk.Box)
-		assertConv(ok)
+		adjustmentsBox := gtkutil.MustGet[*gtk.Box](builder, "adjustmentsBox")
 		adjustmentsBox.SetSensitive(false)
 
 		// Fill adjustments box.
-		scaleValueChanged := func(scale *gtk.Scale, userData interface{}) {
+		// The index of the band is -1 for the preamp scale.
+		scaleValueChanged := func(scale *gtk.Scale, idx int) {
 			if equalizer == nil || scale == nil {
 				return
 			}
 
-			idx, ok := userData.(int)
-			if !ok || idx < -1 || idx >= len(bandFreqs) {
-				return
-			}
-
 			val := scale.GetValue()
 			if idx == -1 {
 				err = equalizer.SetPreampValue(val)
-				assertErr(err)
+				gtkutil.AssertErr(err)
 			} else {
 				err = equalizer.SetAmpValueAtIndex(val, uint(idx))
-				assertErr(err)
+				gtkutil.AssertErr(err)
 			}
 			err = player.SetEqualizer(equalizer)
-			assertErr(err)
+			gtkutil.AssertErr(err)
 		}
 
 		preampScale := addFreqScale("Preamp", adjustmentsBox)
-		preampScale.Connect("value-changed", scaleValueChanged, -1)
+		preampScale.Connect("value-changed", func(scale *gtk.Scale) {
+			scaleValueChanged(scale, -1)
+		})
 
 		freqScales := make([]*gtk.Scale, 0, len(bandFreqs))
 		for i, bandFreq := range bandFreqs {
@@ -151,33 +133,138 @@ func main() {
 
 			freqScale := addFreqScale(strconv.FormatFloat(bandFreq, 'f', -1, 64)+" "+suffix, adjustmentsBox)
 			freqScales = append(freqScales, freqScale)
-			freqScale.Connect("value-changed", scaleValueChanged, i)
+			idx := i
+			freqScale.Connect("value-changed", func(scale *gtk.Scale) {
+				scaleValueChanged(scale, idx)
+			})
 		}
 
 		setScaleValues := func() {
 			preampVal, err := equalizer.PreampValue()
-			assertErr(err)
+			gtkutil.AssertErr(err)
 
 			preampScale.SetValue(preampVal)
 			for i, freqScale := range freqScales {
 				freqVal, err := equalizer.AmpValueAtIndex(uint(i))
-				assertErr(err)
+				gtkutil.AssertErr(err)
 				freqScale.SetValue(freqVal)
 			}
 		}
 
 		// Get reset button.
-		resetButton, ok := builderGetObject(builder, "resetButton").(*gtk.Button)
-		assertConv(ok)
+		resetButton := gtkutil.MustGet[*gtk.Button](builder, "resetButton")
 		resetButton.SetSensitive(false)
 
+		// Get profile rule buttons.
+		saveMediaRuleButton := gtkutil.MustGet[*gtk.Button](builder, "saveMediaRuleButton")
+		saveMediaRuleButton.SetSensitive(false)
+
+		saveGenreRuleButton := gtkutil.MustGet[*gtk.Button](builder, "saveGenreRuleButton")
+		saveGenreRuleButton.SetSensitive(false)
+
 		// Get media location entry.
-		mediaLocationEntry := builderGetObject(builder, "mediaLocationEntry").(*gtk.Entry)
-		assertConv(ok)
+		mediaLocationEntry := gtkutil.MustGet[*gtk.Entry](builder, "mediaLocationEntry")
 
 		// Get play button.
-		playButton, ok := builderGetObject(builder, "playButton").(*gtk.Button)
-		assertConv(ok)
+		playButton := gtkutil.MustGet[*gtk.Button](builder, "playButton")
+
+		resetPreset := func() {
+			idx := presetsComboBox.GetActive() - 1
+			if idx < 0 || idx >= len(presetNames) {
+				return
+			}
+
+			// Release previous equalizer.
+			releaseEqualizer()
+
+			// Create new equalizer from preset.
+			equalizer, err = vlc.NewEqualizerFromPreset(uint(idx))
+			gtkutil.AssertErr(err)
+			setScaleValues()
+
+			// Set player equalizer.
+			err = player.SetEqualizer(equalizer)
+			gtkutil.AssertErr(err)
+		}
+
+		// Returns an equalizer profile containing the current settings.
+		currentProfile := func() eqprofile.Profile {
+			if equalizer == nil {
+				return eqprofile.Profile{}
+			}
+
+			profile := eqprofile.Profile{
+				Preset: presetsComboBox.GetActiveText(),
+				Preamp: preampScale.GetValue(),
+			}
+			for _, freqScale := range freqScales {
+				profile.Bands = append(profile.Bands, freqScale.GetValue())
+			}
+
+			return profile
+		}
+
+		// Applies the settings of the specified equalizer profile.
+		applyProfile := func(profile eqprofile.Profile) {
+			active := 0
+			if idx, ok := vlcutil.PresetIndex(profile.Preset); ok {
+				active = int(idx) + 1
+			}
+
+			if presetsComboBox.GetActive() != active {
+				presetsComboBox.SetActive(active)
+			} else {
+				resetPreset()
+			}
+			if len(profile.Bands) == 0 {
+				return
+			}
+
+			// Profiles without a preset only contain custom band values, so
+			// a flat equalizer is created for them.
+			if equalizer == nil {
+				equalizer, err = vlc.NewEqualizer()
+				gtkutil.AssertErr(err)
+				adjustmentsBox.SetSensitive(true)
+			}
+
+			preampScale.SetValue(profile.Preamp)
+			for i, freqScale := range freqScales {
+				if i < len(profile.Bands) {
+					freqScale.SetValue(profile.Bands[i])
+				}
+			}
+
+			// Set player equalizer.
+			err = player.SetEqualizer(equalizer)
+			gtkutil.AssertErr(err)
+		}
+
+		// Applies the equalizer profile matching the loaded media.
+		applyMediaProfile := func(path string, media *vlc.Media) {
+			mediaPath, mediaGenre = path, ""
+			saveMediaRuleButton.SetSensitive(true)
+			saveGenreRuleButton.SetSensitive(false)
+
+			if rule := eqprofile.MatchRule(rules, mediaPath, ""); rule != nil {
+				applyProfile(rule.Profile)
+				return
+			}
+
+			// Parse media in order to retrieve its genre.
+			cancelParse, err = eqprofile.ParseMediaGenre(media, func(genre string) {
+				cancelParse = nil
+				mediaGenre = strings.TrimSpace(genre)
+				saveGenreRuleButton.SetSensitive(mediaGenre != "")
+
+				if rule := eqprofile.MatchRule(rules, "", mediaGenre); rule != nil {
+					applyProfile(rule.Profile)
+				}
+			})
+			if err != nil {
+				log.Printf("Cannot parse media: %s\n", err)
+			}
+		}
 
 		// Add builder signal handlers.
 		signals := map[string]interface{}{
@@ -202,31 +289,39 @@ func main() {
 				} else {
 					// Create new equalizer from preset.
 					equalizer, err = vlc.NewEqualizerFromPreset(uint(idx))
-					assertErr(err)
+					gtkutil.AssertErr(err)
 					setScaleValues()
 				}
 
 				// Set player equalizer.
 				err = player.SetEqualizer(equalizer)
-				assertErr(err)
+				gtkutil.AssertErr(err)
 			},
-			"onReset": func() {
-				idx := presetsComboBox.GetActive() - 1
-				if idx < 0 || idx >= len(presetNames) {
+			"onReset": resetPreset,
+			"onSaveMediaRule": func() {
+				if mediaPath == "" {
 					return
 				}
 
-				// Release previous equalizer.
-				releaseEqualizer()
-
-				// Create new equalizer from preset.
-				equalizer, err = vlc.NewEqualizerFromPreset(uint(idx))
-				assertErr(err)
-				setScaleValues()
+				rules = eqprofile.SetRule(rules, eqprofile.KindMedia, mediaPath, currentProfile())
+				if err := eqprofile.SaveRules(rules); err != nil {
+					log.Printf("Cannot save equalizer profile rules: %s\n", err)
+				}
+			},
+			"onSaveGenreRule": func() {
+				if mediaGenre == "" {
+					return
+				}
 
-				// Set player equalizer.
-				err = player.SetEqualizer(equalizer)
-				assertErr(err)
+				rules = eqprofile.SetRule(rules, eqprofile.KindGenre, mediaGenre, currentProfile())
+				if err := eqprofile.SaveRules(rules); err != nil {
+					log.Printf("Cannot save equalizer profile rules: %s\n", err)
+				}
+			},
+			"onEditRules": func() {
+				if rules, err = eqprofile.RunRulesDialog(appWin, rules); err != nil {
+					log.Printf("Cannot save equalizer profile rules: %s\n", err)
+				}
 			},
 			"onChooseFile": func() {
 				fileDialog, err := gtk.FileChooserDialogNewWith2Buttons(
@@ -234,11 +329,11 @@ func main() {
 					appWin, gtk.FILE_CHOOSER_ACTION_SAVE,
 					"Cancel", gtk.RESPONSE_DELETE_EVENT,
 					"Save", gtk.RESPONSE_ACCEPT)
-				assertErr(err)
+				gtkutil.AssertErr(err)
 				defer fileDialog.Destroy()
 
 				fileFilter, err := gtk.FileFilterNew()
-				assertErr(err)
+				gtkutil.AssertErr(err)
 				fileFilter.SetName("Media files")
 				fileFilter.AddPattern("*.mp4")
 				fileFilter.AddPattern("*.mp3")
@@ -246,19 +341,26 @@ func main() {
 
 				if result := fileDialog.Run(); result == gtk.RESPONSE_ACCEPT {
 					// Release previous media instance.
-					playerReleaseMedia(player)
+					if cancelParse != nil {
+						cancelParse()
+						cancelParse = nil
+					}
+					gtkvlc.ReleaseMedia(player)
 
 					location := fileDialog.GetFilename()
 					mediaLocationEntry.SetText(location)
 
 					// Set player media and start playback.
 					media, err := vlc.NewMediaFromPath(location)
-					assertErr(err)
+					gtkutil.AssertErr(err)
 					err = player.SetMedia(media)
-					assertErr(err)
+					gtkutil.AssertErr(err)
 					err = player.Play()
-					assertErr(err)
+					gtkutil.AssertErr(err)
 					playButton.SetLabel("Pause")
+
+					// Apply matching equalizer profile, if any.
+					applyMediaProfile(location, media)
 				}
 			},
 			"onPlay": func() {
@@ -283,13 +385,14 @@ func main() {
 	})
 
 	// Cleanup on exit.
-	app.Connect("shutdown", func() {
+	app.OnShutdown(func() {
+		if cancelParse != nil {
+			cancelParse()
+		}
 		releaseEqualizer()
-		playerReleaseMedia(player)
-		player.Release()
-		vlc.Release()
+		gtkvlc.ReleasePlayer(player)
 	})
 
 	// Launch the application.
-	os.Exit(app.Run(os.Args))
+	os.Exit(app.Run())
 }
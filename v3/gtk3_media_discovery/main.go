@@ -1,33 +1,23 @@
+//go:build gtk3
+
 package main
 
 import (
+	"fmt"
 	"log"
 	"os"
 
 	vlc "github.com/adrg/libvlc-go/v3"
+	"github.com/gotk3/gotk3/cairo"
 	"github.com/gotk3/gotk3/glib"
 	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/adrg/libvlc-go-examples/shared/gtkutil"
+	"github.com/adrg/libvlc-go-examples/v3/internal/gtkvlc"
 )
 
 const appID = "com.github.libvlc-go.gtk3-media-discovery-example"
 
-func builderGetObject(builder *gtk.Builder, name string) glib.IObject {
-	obj, _ := builder.GetObject(name)
-	return obj
-}
-
-func assertErr(err error) {
-	if err != nil {
-		log.Panic(err)
-	}
-}
-
-func assertConv(ok bool) {
-	if !ok {
-		log.Panic("invalid widget conversion")
-	}
-}
-
 func clearListBox(l *gtk.ListBox) {
 	l.GetChildren().Foreach(func(row interface{}) {
 		widget, ok := row.(*gtk.Widget)
@@ -38,249 +28,385 @@ func clearListBox(l *gtk.ListBox) {
 	})
 }
 
-func activateListBoxRows(l *gtk.ListBox) {
-	l.GetChildren().Foreach(func(row interface{}) {
-		widget, ok := row.(*gtk.Widget)
-		if !ok {
-			return
-		}
-		widget.SetSensitive(true)
-	})
+// formatTime returns the specified number of milliseconds as mm:ss.
+func formatTime(ms int) string {
+	seconds := ms / 1000
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
 }
 
 func main() {
-	// Initialize libVLC module.
-	err := vlc.Init("--quiet", "--no-xlib")
-	assertErr(err)
+	// Initialize libVLC module and create new GTK application.
+	app, err := gtkvlc.NewApp(appID)
+	gtkutil.AssertErr(err)
 
 	player, err := vlc.NewListPlayer()
-	assertErr(err)
+	gtkutil.AssertErr(err)
 
 	var (
-		serviceIdx         int = -1
-		service            *vlc.MediaDiscoverer
-		serviceDescriptors []*vlc.MediaDiscovererDescriptor
+		sources         = map[string]*discoverySource{}
+		playingList     *vlc.MediaList
+		playingNode     *mediaNode
+		playingSource   string
+		eventManager    *vlc.EventManager
+		nextItemEventID vlc.EventID
 	)
 
-	// Create new GTK application.
-	app, err := gtk.ApplicationNew(appID, glib.APPLICATION_FLAGS_NONE)
-	assertErr(err)
-
-	app.Connect("activate", func() {
-		// Load application layout.
-		builder, err := gtk.BuilderNewFromFile("layout.glade")
-		assertErr(err)
-
+	app.OnActivate("layout.glade", func(builder *gtk.Builder) {
 		// Get application window.
-		appWin, ok := builderGetObject(builder, "appWindow").(*gtk.ApplicationWindow)
-		assertConv(ok)
+		appWin := gtkutil.MustGet[*gtk.ApplicationWindow](builder, "appWindow")
 
 		// Get discovery service category combo box.
-		categoryComboBox, ok := builderGetObject(builder, "categoryComboBox").(*gtk.ComboBoxText)
-		assertConv(ok)
+		categoryComboBox := gtkutil.MustGet[*gtk.ComboBoxText](builder, "categoryComboBox")
 
 		// Get discovery services list box.
-		servicesListBox, ok := builderGetObject(builder, "servicesListBox").(*gtk.ListBox)
-		assertConv(ok)
+		servicesListBox := gtkutil.MustGet[*gtk.ListBox](builder, "servicesListBox")
+
+		// Get media tree view.
+		mediaTreeView := gtkutil.MustGet[*gtk.TreeView](builder, "mediaTreeView")
+
+		mediaTree, err := newMediaTree(mediaTreeView)
+		gtkutil.AssertErr(err)
 
-		// Get start button.
-		startButton, ok := builderGetObject(builder, "startButton").(*gtk.Button)
-		assertConv(ok)
-		startButton.SetSensitive(false)
+		// Get media search entry.
+		mediaSearchEntry := gtkutil.MustGet[*gtk.SearchEntry](builder, "mediaSearchEntry")
 
-		// Get media list box.
-		mediaListBox, ok := builderGetObject(builder, "mediaListBox").(*gtk.ListBox)
-		assertConv(ok)
+		// Get transport controls.
+		previousButton := gtkutil.MustGet[*gtk.Button](builder, "previousButton")
+		nextButton := gtkutil.MustGet[*gtk.Button](builder, "nextButton")
+		seekScale := gtkutil.MustGet[*gtk.Scale](builder, "seekScale")
+		timeLabel := gtkutil.MustGet[*gtk.Label](builder, "timeLabel")
+
+		// Get breadcrumb box.
+		breadcrumbBox := gtkutil.MustGet[*gtk.Box](builder, "breadcrumbBox")
 
 		// Get play button.
-		playButton, ok := builderGetObject(builder, "playButton").(*gtk.Button)
-		assertConv(ok)
+		playButton := gtkutil.MustGet[*gtk.Button](builder, "playButton")
 		playButton.SetSensitive(false)
 
 		// Get pause button.
-		pauseButton, ok := builderGetObject(builder, "pauseButton").(*gtk.Button)
-		assertConv(ok)
+		pauseButton := gtkutil.MustGet[*gtk.Button](builder, "pauseButton")
 		pauseButton.SetSensitive(false)
 
-		// Add builder signal handlers.
-		signals := map[string]interface{}{
-			"onServiceCategoryChange": func() {
-				// Deactivate buttons.
-				playButton.SetSensitive(false)
-				pauseButton.SetSensitive(false)
+		// Highlights the node of the media being played. Called when the
+		// list player moves to another item of the media list.
+		updatePlayingNode := func() {
+			var node *mediaNode
+			if mediaPlayer, err := player.Player(); err == nil {
+				if media, _ := mediaPlayer.Media(); media != nil {
+					location, _ := media.Location()
+					node = mediaTree.Find(playingList, location)
+				}
+			}
 
-				// Clear discovery service list.
-				clearListBox(servicesListBox)
+			mediaTree.SetPlaying(node)
+			playingNode = node
+
+			selected := mediaTree.Selected()
+			playButton.SetSensitive(selected != nil && selected != playingNode)
+		}
+
+		// Updates the seek bar and the time label periodically.
+		var updatingSeek bool
+		glib.TimeoutAdd(500, func() bool {
+			mediaPlayer, err := player.Player()
+			if err != nil {
+				return true
+			}
+
+			var position float32
+			var mediaTime, mediaLength int
+			if media, _ := mediaPlayer.Media(); media != nil {
+				position, _ = mediaPlayer.MediaPosition()
+				mediaTime, _ = mediaPlayer.MediaTime()
+				mediaLength, _ = mediaPlayer.MediaLength()
+			}
+			if position < 0 {
+				position = 0
+			}
+
+			updatingSeek = true
+			seekScale.SetValue(float64(position))
+			updatingSeek = false
+			seekScale.SetSensitive(mediaPlayer.IsSeekable())
+
+			timeLabel.SetText(formatTime(mediaTime) + " / " + formatTime(mediaLength))
+			return true
+		})
+
+		// Track the item being played by the list player.
+		eventManager, err = player.EventManager()
+		gtkutil.AssertErr(err)
+
+		nextItemEventID, err = eventManager.Attach(vlc.MediaListPlayerNextItemSet, func(event vlc.Event, userData interface{}) {
+			glib.IdleAdd(updatePlayingNode)
+		}, nil)
+		gtkutil.AssertErr(err)
+
+		// Displays the path of the selected node in the breadcrumb box.
+		// Clicking a breadcrumb selects the corresponding node.
+		updateBreadcrumbs := func(node *mediaNode) {
+			breadcrumbBox.GetChildren().Foreach(func(child interface{}) {
+				if widget, ok := child.(*gtk.Widget); ok {
+					widget.Destroy()
+				}
+			})
+			if node == nil {
+				return
+			}
+
+			sourceLabel, err := gtk.LabelNew(node.source)
+			gtkutil.AssertErr(err)
+			breadcrumbBox.Add(sourceLabel)
+
+			for _, ancestor := range node.ancestors() {
+				separator, err := gtk.LabelNew("›")
+				gtkutil.AssertErr(err)
+				breadcrumbBox.Add(separator)
+
+				crumb, err := gtk.ButtonNewWithLabel(ancestor.title)
+				gtkutil.AssertErr(err)
+				crumb.SetRelief(gtk.RELIEF_NONE)
+				crumb.SetSensitive(ancestor != node)
+
+				ancestor := ancestor
+				crumb.Connect("clicked", func() {
+					mediaTree.Select(ancestor)
+				})
+				breadcrumbBox.Add(crumb)
+			}
+			breadcrumbBox.ShowAll()
+		}
 
-				// Clear media list.
-				clearListBox(mediaListBox)
+		// Plays the media of the specified node. The media list containing
+		// the node becomes the media list of the player.
+		playNode := func(node *mediaNode) {
+			index := mediaTree.Index(node)
+			if index < 0 {
+				return
+			}
 
-				// Stop player.
+			if node.list != playingList {
 				player.Stop()
+				if err := player.SetMediaList(node.list); err != nil {
+					log.Printf("ERROR: %v\n", err)
+					return
+				}
+				playingList = node.list
+			}
+
+			if err := player.PlayAtIndex(uint(index)); err != nil {
+				log.Printf("ERROR: %v\n", err)
+				return
+			}
+			mediaTree.SetPlaying(node)
+			playingNode, playingSource = node, node.source
+
+			playButton.SetSensitive(false)
+			pauseButton.SetSensitive(true)
+			pauseButton.SetLabel("Pause")
+			previousButton.SetSensitive(true)
+			nextButton.SetSensitive(true)
+		}
+
+		// Status labels of the displayed discovery services.
+		statusLabels := map[string]*gtk.Label{}
 
-				// Release current service, if any.
-				if service != nil {
-					assertErr(service.Release())
-					service = nil
-					serviceIdx = -1
+		updateSourceStatus := func(name string) {
+			label, ok := statusLabels[name]
+			if !ok {
+				return
+			}
+
+			status := "Stopped"
+			if source, ok := sources[name]; ok {
+				status = source.Status()
+			}
+			label.SetText(status)
+		}
+
+		// Starts the discovery service with the specified descriptor. The
+		// items found by the service are added to the media tree.
+		startSource := func(descriptor *vlc.MediaDiscovererDescriptor) error {
+			source, err := startDiscoverySource(descriptor, func(source *discoverySource, event vlc.Event, media *vlc.Media, location string, index int) {
+				switch event {
+				case vlc.MediaListItemAdded:
+					mediaTree.Insert(source.LongName, media, location, source.list, index)
+				case vlc.MediaListItemDeleted:
+					mediaTree.Remove(source.LongName, index)
 				}
+				updateSourceStatus(source.Name)
+			})
+			if err != nil {
+				return err
+			}
+
+			sources[descriptor.Name] = source
+			updateSourceStatus(descriptor.Name)
+			return nil
+		}
+
+		// Stops the discovery service with the specified name and removes
+		// its items from the media tree. Playback is stopped if the media
+		// being played was found by the service.
+		stopSource := func(name string) {
+			source, ok := sources[name]
+			if !ok {
+				return
+			}
+			delete(sources, name)
+
+			if playingSource == source.LongName {
+				player.Stop()
+				mediaTree.SetPlaying(nil)
+				playingList, playingNode, playingSource = nil, nil, ""
+
+				pauseButton.SetSensitive(false)
+				previousButton.SetSensitive(false)
+				nextButton.SetSensitive(false)
+			}
+
+			mediaTree.RemoveSource(source.LongName)
+			if err := source.Stop(); err != nil {
+				log.Printf("ERROR: %v\n", err)
+			}
+			updateSourceStatus(name)
+		}
+
+		// Add builder signal handlers.
+		signals := map[string]interface{}{
+			"onServiceCategoryChange": func() {
+				// Clear discovery service list. Running services are not
+				// affected.
+				clearListBox(servicesListBox)
+				statusLabels = map[string]*gtk.Label{}
 
 				// Get selected discovery service category.
 				category := categoryComboBox.GetActive() - 1
 				if category < 0 {
-					startButton.SetSensitive(false)
 					return
 				}
 
 				// Get discovery service descriptors.
-				serviceDescriptors, err = vlc.ListMediaDiscoverers(vlc.MediaDiscoveryCategory(category))
-				assertErr(err)
+				serviceDescriptors, err := vlc.ListMediaDiscoverers(vlc.MediaDiscoveryCategory(category))
+				gtkutil.AssertErr(err)
 
-				// Add discovery services to the list.
+				// Add discovery services to the list. Each service can be
+				// started and stopped using its switch.
 				for _, serviceDescriptor := range serviceDescriptors {
 					nameLabel, err := gtk.LabelNew(serviceDescriptor.Name)
-					assertErr(err)
+					gtkutil.AssertErr(err)
 					nameLabel.SetHAlign(gtk.ALIGN_START)
 					nameLabel.SetMarginStart(5)
 					longNameLabel, err := gtk.LabelNew(serviceDescriptor.LongName)
-					assertErr(err)
+					gtkutil.AssertErr(err)
 					longNameLabel.SetMarginStart(20)
 					longNameLabel.SetHAlign(gtk.ALIGN_START)
 
-					rowBox, err := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 0)
-					assertErr(err)
+					infoBox, err := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 0)
+					gtkutil.AssertErr(err)
+					infoBox.Add(nameLabel)
+					infoBox.Add(longNameLabel)
+
+					statusLabel, err := gtk.LabelNew("")
+					gtkutil.AssertErr(err)
+					statusLabel.SetMarginEnd(10)
+					statusLabels[serviceDescriptor.Name] = statusLabel
+					updateSourceStatus(serviceDescriptor.Name)
+
+					serviceSwitch, err := gtk.SwitchNew()
+					gtkutil.AssertErr(err)
+					serviceSwitch.SetVAlign(gtk.ALIGN_CENTER)
+					serviceSwitch.SetMarginEnd(5)
+					serviceSwitch.SetActive(sources[serviceDescriptor.Name] != nil)
+
+					descriptor := serviceDescriptor
+					serviceSwitch.Connect("notify::active", func() {
+						if !serviceSwitch.GetActive() {
+							stopSource(descriptor.Name)
+							return
+						}
+						if _, ok := sources[descriptor.Name]; ok {
+							return
+						}
+
+						if err := startSource(descriptor); err != nil {
+							log.Printf("ERROR: %v\n", err)
+							serviceSwitch.SetActive(false)
+						}
+					})
+
+					rowBox, err := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 0)
+					gtkutil.AssertErr(err)
 					rowBox.SetMarginTop(5)
 					rowBox.SetMarginBottom(5)
-					rowBox.Add(nameLabel)
-					rowBox.Add(longNameLabel)
+					rowBox.PackStart(infoBox, true, true, 0)
+					rowBox.PackStart(statusLabel, false, false, 0)
+					rowBox.PackStart(serviceSwitch, false, false, 0)
 
 					row, err := gtk.ListBoxRowNew()
-					assertErr(err)
+					gtkutil.AssertErr(err)
 
 					row.Add(rowBox)
 					servicesListBox.Add(row)
 					servicesListBox.ShowAll()
 				}
 			},
-			"onServiceListRowSelected": func() {
-				row := servicesListBox.GetSelectedRow()
-				if row == nil {
-					startButton.SetSensitive(false)
-					return
+			"onMediaTreeSelectionChanged": func() {
+				node := mediaTree.Selected()
+				playButton.SetSensitive(node != nil && node != playingNode)
+				updateBreadcrumbs(node)
+			},
+			"onMediaTreeRowExpanded": func(_ *gtk.TreeView, iter *gtk.TreeIter) {
+				mediaTree.Expand(iter)
+			},
+			"onMediaTreeRowActivated": func() {
+				if node := mediaTree.Selected(); node != nil && !node.folder {
+					playNode(node)
 				}
-
-				idx := row.GetIndex()
-				startButton.SetSensitive(idx >= 0 && idx != serviceIdx)
 			},
-			"onServiceStart": func() {
-				row := servicesListBox.GetSelectedRow()
-				if row == nil {
-					return
+			"onMediaSearchChanged": func() {
+				query, err := mediaSearchEntry.GetText()
+				gtkutil.AssertErr(err)
+				mediaTree.SetQuery(query)
+			},
+			"onMediaPlay": func() {
+				if node := mediaTree.Selected(); node != nil {
+					playNode(node)
 				}
-
-				idx := row.GetIndex()
-				if idx < 0 || idx > len(serviceDescriptors)-1 || idx == serviceIdx {
+			},
+			"onRealizePlayerArea": func(playerArea *gtk.DrawingArea) {
+				// Set window for the player.
+				mediaPlayer, err := player.Player()
+				gtkutil.AssertErr(err)
+				playerWindow, err := playerArea.GetWindow()
+				gtkutil.AssertErr(err)
+				err = gtkvlc.SetPlayerWindow(mediaPlayer, playerWindow)
+				gtkutil.AssertErr(err)
+			},
+			"onDrawPlayerArea": func(playerArea *gtk.DrawingArea, cr *cairo.Context) {
+				cr.SetSourceRGB(0, 0, 0)
+				cr.Paint()
+			},
+			"onSeekValueChanged": func(seekScale *gtk.Scale) {
+				if updatingSeek {
 					return
 				}
 
-				// Deactivate buttons.
-				playButton.SetSensitive(false)
-				pauseButton.SetSensitive(false)
-
-				// Stop player.
-				player.Stop()
-
-				// Release current service, if any.
-				if service != nil {
-					assertErr(service.Release())
-					service = nil
-					serviceIdx = -1
-				}
-
-				// Clear media list.
-				clearListBox(mediaListBox)
-
-				// Create media discovery service.
-				service, err = vlc.NewMediaDiscoverer(serviceDescriptors[idx].Name)
+				mediaPlayer, err := player.Player()
 				if err != nil {
-					log.Printf("ERROR: %v\n", err)
 					return
 				}
-				serviceIdx = idx
-
-				activateListBoxRows(servicesListBox)
-				row.SetSensitive(false)
-				startButton.SetSensitive(false)
-
-				// Start media discovery service.
-				if err = service.Start(func(event vlc.Event, media *vlc.Media, index int) {
-					switch event {
-					case vlc.MediaListItemAdded:
-						location, err := media.Location()
-						assertErr(err)
-
-						nameLabel, err := gtk.LabelNew(location)
-						assertErr(err)
-						nameLabel.SetHAlign(gtk.ALIGN_START)
-						nameLabel.SetMarginStart(5)
-
-						rowBox, err := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 0)
-						assertErr(err)
-						rowBox.SetMarginTop(5)
-						rowBox.SetMarginBottom(5)
-						rowBox.Add(nameLabel)
-
-						row, err := gtk.ListBoxRowNew()
-						assertErr(err)
-
-						row.Add(rowBox)
-						mediaListBox.Insert(row, index)
-						mediaListBox.ShowAll()
-					case vlc.MediaListItemDeleted:
-						row := mediaListBox.GetRowAtIndex(index)
-						if row != nil {
-							mediaListBox.Remove(row)
-							mediaListBox.ShowAll()
-						}
-					}
-				}); err != nil {
-					log.Printf("ERROR: %v\n", err)
-					return
-				}
-
-				mediaList, err := service.MediaList()
-				assertErr(err)
-				player.SetMediaList(mediaList)
+				mediaPlayer.SetMediaPosition(float32(seekScale.GetValue()))
 			},
-			"onMediaListRowSelected": func() {
-				row := mediaListBox.GetSelectedRow()
-				if row == nil {
-					playButton.SetSensitive(false)
-					return
+			"onMediaPrevious": func() {
+				if err := player.PlayPrevious(); err != nil {
+					log.Printf("ERROR: %v\n", err)
 				}
-
-				idx := row.GetIndex()
-				playButton.SetSensitive(idx >= 0)
 			},
-			"onMediaPlay": func() {
-				row := mediaListBox.GetSelectedRow()
-				if row == nil {
-					return
-				}
-
-				idx := row.GetIndex()
-				if idx < 0 {
-					return
+			"onMediaNext": func() {
+				if err := player.PlayNext(); err != nil {
+					log.Printf("ERROR: %v\n", err)
 				}
-
-				err = player.PlayAtIndex(uint(idx))
-				assertErr(err)
-
-				activateListBoxRows(mediaListBox)
-				row.SetSensitive(false)
-				playButton.SetSensitive(false)
-				pauseButton.SetSensitive(true)
-				pauseButton.SetLabel("Pause")
 			},
 			"onMediaStop": func() {
 				isPlaying := player.IsPlaying()
@@ -300,20 +426,20 @@ func main() {
 	})
 
 	// Cleanup on exit.
-	app.Connect("shutdown", func() {
-		// Release media discovery service.
-		if service != nil {
-			service.Release()
+	app.OnShutdown(func() {
+		// Release media discovery services.
+		for _, source := range sources {
+			source.Stop()
 		}
 
 		// Release player.
+		if eventManager != nil {
+			eventManager.Detach(nextItemEventID)
+		}
 		player.Stop()
 		player.Release()
-
-		// Release VLC module.
-		vlc.Release()
 	})
 
 	// Launch the application.
-	os.Exit(app.Run(os.Args))
+	os.Exit(app.Run())
 }
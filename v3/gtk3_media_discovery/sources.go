@@ -0,0 +1,114 @@
+//go:build gtk3
+
+package main
+
+import (
+	"fmt"
+	"log"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+	"github.com/gotk3/gotk3/glib"
+)
+
+// discoverySource is a running media discovery service. Multiple sources
+// can run at the same time, their items being merged in the media tree.
+type discoverySource struct {
+	Name     string
+	LongName string
+
+	service *vlc.MediaDiscoverer
+	list    *vlc.MediaList
+	items   int
+	stopped bool
+}
+
+// startDiscoverySource creates and starts the media discovery service with
+// the specified descriptor. The callback function is called on the main GTK
+// loop when items are added to or removed from the media list of the service.
+// For added items, the callback receives a copy of the media, which must be
+// released by the caller, and its location. The media is nil if it cannot
+// be copied. For removed items, the media is nil.
+func startDiscoverySource(descriptor *vlc.MediaDiscovererDescriptor,
+	callback func(source *discoverySource, event vlc.Event, media *vlc.Media, location string, index int)) (*discoverySource, error) {
+	service, err := vlc.NewMediaDiscoverer(descriptor.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := service.MediaList()
+	if err != nil {
+		service.Release()
+		return nil, err
+	}
+
+	source := &discoverySource{
+		Name:     descriptor.Name,
+		LongName: descriptor.LongName,
+		service:  service,
+		list:     list,
+	}
+
+	// The callback is not called on the main GTK loop, so the events are
+	// forwarded using glib.IdleAdd. The media of the events is only valid
+	// while the callback runs. Added media is duplicated, so that it can be
+	// used on the main loop, while deleted media is not forwarded at all,
+	// as libVLC releases it once the callback returns. Events are always
+	// forwarded, even if the media cannot be duplicated, in order to keep
+	// the item indices in sync. The location of the media is forwarded
+	// instead in that case.
+	err = service.Start(func(event vlc.Event, media *vlc.Media, index int) {
+		var location string
+		switch event {
+		case vlc.MediaListItemAdded:
+			location, _ = media.Location()
+
+			var err error
+			if media, err = media.Duplicate(); err != nil {
+				log.Printf("Cannot duplicate media %s: %s\n", location, err)
+				media = nil
+			}
+		default:
+			media = nil
+		}
+
+		glib.IdleAdd(func() {
+			if source.stopped {
+				if media != nil {
+					media.Release()
+				}
+				return
+			}
+
+			switch event {
+			case vlc.MediaListItemAdded:
+				source.items++
+			case vlc.MediaListItemDeleted:
+				source.items--
+			}
+			callback(source, event, media, location, index)
+		})
+	})
+	if err != nil {
+		service.Release()
+		return nil, err
+	}
+
+	return source, nil
+}
+
+// Stop stops and releases the media discovery service. The items of the
+// source must not be used afterwards.
+func (s *discoverySource) Stop() error {
+	s.stopped = true
+	return s.service.Release()
+}
+
+// Status returns a textual representation of the state of the source
+// (e.g. 12 items).
+func (s *discoverySource) Status() string {
+	if s.items == 1 {
+		return "1 item"
+	}
+
+	return fmt.Sprintf("%d items", s.items)
+}
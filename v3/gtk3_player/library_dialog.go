@@ -0,0 +1,283 @@
+//go:build gtk3
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/adrg/libvlc-go-examples/v3/medialib"
+)
+
+// Media library dialog store columns.
+const (
+	libColIndex = iota
+	libColTitle
+	libColArtist
+	libColAlbum
+	libColGenre
+	libColDuration
+)
+
+// libraryQueryFields contains the labels of the fields the library can be
+// searched by, in the order they are displayed in the field combo box.
+var libraryQueryFields = []string{"Any", "Artist", "Album", "Genre"}
+
+func newLibraryQuery(field int, text string) medialib.Query {
+	switch field {
+	case 1:
+		return medialib.Query{Artist: text}
+	case 2:
+		return medialib.Query{Album: text}
+	case 3:
+		return medialib.Query{Genre: text}
+	}
+
+	return medialib.Query{Text: text}
+}
+
+// runLibraryDialog displays a dialog which allows searching and rescanning
+// the local media library. Returns the path of the file chosen for playback
+// or an empty string if the dialog was closed without choosing a file.
+func runLibraryDialog(parent gtk.IWindow) (string, error) {
+	dbPath, err := medialib.DefaultPath()
+	if err != nil {
+		return "", err
+	}
+	lib, err := medialib.Open(dbPath)
+	if err != nil {
+		return "", err
+	}
+	defer lib.Close()
+
+	dialog, err := gtk.DialogNew()
+	if err != nil {
+		return "", err
+	}
+	defer dialog.Destroy()
+
+	dialog.SetTitle("Media library")
+	dialog.SetTransientFor(parent)
+	dialog.SetModal(true)
+	dialog.SetDefaultSize(800, 480)
+	if _, err = dialog.AddButton("Close", gtk.RESPONSE_CLOSE); err != nil {
+		return "", err
+	}
+	if _, err = dialog.AddButton("Play", gtk.RESPONSE_ACCEPT); err != nil {
+		return "", err
+	}
+
+	contentArea, err := dialog.GetContentArea()
+	if err != nil {
+		return "", err
+	}
+	contentArea.SetSpacing(10)
+	contentArea.SetMarginStart(10)
+	contentArea.SetMarginEnd(10)
+	contentArea.SetMarginTop(10)
+
+	// Create search controls.
+	fieldComboBox, err := gtk.ComboBoxTextNew()
+	if err != nil {
+		return "", err
+	}
+	for _, field := range libraryQueryFields {
+		fieldComboBox.AppendText(field)
+	}
+	fieldComboBox.SetActive(0)
+
+	searchEntry, err := gtk.SearchEntryNew()
+	if err != nil {
+		return "", err
+	}
+	searchEntry.SetPlaceholderText("Search media library")
+	searchEntry.SetHExpand(true)
+
+	rescanButton, err := gtk.ButtonNewWithLabel("Rescan")
+	if err != nil {
+		return "", err
+	}
+
+	searchBox, err := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 10)
+	if err != nil {
+		return "", err
+	}
+	searchBox.PackStart(fieldComboBox, false, false, 0)
+	searchBox.PackStart(searchEntry, true, true, 0)
+	searchBox.PackStart(rescanButton, false, false, 0)
+	contentArea.PackStart(searchBox, false, false, 0)
+
+	// Create results view.
+	store, err := gtk.ListStoreNew(glib.TYPE_INT, glib.TYPE_STRING, glib.TYPE_STRING,
+		glib.TYPE_STRING, glib.TYPE_STRING, glib.TYPE_STRING)
+	if err != nil {
+		return "", err
+	}
+
+	treeView, err := gtk.TreeViewNewWithModel(store)
+	if err != nil {
+		return "", err
+	}
+	for _, column := range []struct {
+		title string
+		id    int
+	}{
+		{"Title", libColTitle},
+		{"Artist", libColArtist},
+		{"Album", libColAlbum},
+		{"Genre", libColGenre},
+		{"Duration", libColDuration},
+	} {
+		renderer, err := gtk.CellRendererTextNew()
+		if err != nil {
+			return "", err
+		}
+		viewColumn, err := gtk.TreeViewColumnNewWithAttribute(column.title, renderer, "text", column.id)
+		if err != nil {
+			return "", err
+		}
+		viewColumn.SetResizable(true)
+		treeView.AppendColumn(viewColumn)
+	}
+
+	scrolledWin, err := gtk.ScrolledWindowNew(nil, nil)
+	if err != nil {
+		return "", err
+	}
+	scrolledWin.SetPolicy(gtk.POLICY_AUTOMATIC, gtk.POLICY_AUTOMATIC)
+	scrolledWin.SetVExpand(true)
+	scrolledWin.Add(treeView)
+	contentArea.PackStart(scrolledWin, true, true, 0)
+
+	statusLabel, err := gtk.LabelNew("")
+	if err != nil {
+		return "", err
+	}
+	statusLabel.SetXAlign(0)
+	statusLabel.SetMarginBottom(10)
+	contentArea.PackStart(statusLabel, false, false, 0)
+
+	// Fills the results view with the entries matching the search controls.
+	var entries []*medialib.Entry
+	search := func() {
+		text, _ := searchEntry.GetText()
+		results, err := lib.Search(newLibraryQuery(fieldComboBox.GetActive(), strings.TrimSpace(text)))
+		if err != nil {
+			statusLabel.SetText(fmt.Sprintf("Cannot search media library: %s", err))
+			return
+		}
+
+		entries = results
+		store.Clear()
+		for i, entry := range entries {
+			iter := store.Append()
+			store.SetValue(iter, libColIndex, i)
+			store.SetValue(iter, libColTitle, entry.DisplayTitle())
+			store.SetValue(iter, libColArtist, entry.Artist)
+			store.SetValue(iter, libColAlbum, entry.Album)
+			store.SetValue(iter, libColGenre, entry.Genre)
+			store.SetValue(iter, libColDuration, entry.Duration.Round(time.Second).String())
+		}
+		statusLabel.SetText(fmt.Sprintf("%d results", len(entries)))
+	}
+
+	searchEntry.Connect("search-changed", search)
+	fieldComboBox.Connect("changed", search)
+	treeView.Connect("row-activated", func() {
+		dialog.Response(gtk.RESPONSE_ACCEPT)
+	})
+
+	// Rescan the local media directories in the background. The scan is
+	// cancelled when the dialog is closed.
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	rescanButton.Connect("clicked", func() {
+		rescanButton.SetSensitive(false)
+		statusLabel.SetText("Scanning local media directories...")
+
+		scanner := &medialib.Scanner{
+			Library: lib,
+			Progress: func(path string, err error) {
+				glib.IdleAdd(func() {
+					if ctx.Err() != nil {
+						return
+					}
+					statusLabel.SetText(fmt.Sprintf("Scanning %s...", path))
+				})
+			},
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var (
+				stats *medialib.ScanStats
+				dirs  []string
+				err   error
+			)
+			if dirs, err = medialib.LocalDirs(2 * time.Second); err == nil {
+				stats, err = scanner.Scan(ctx, dirs...)
+			}
+
+			glib.IdleAdd(func() {
+				if ctx.Err() != nil {
+					return
+				}
+
+				rescanButton.SetSensitive(true)
+				search()
+				if err != nil {
+					statusLabel.SetText(fmt.Sprintf("Cannot scan media library: %s", err))
+					return
+				}
+				statusLabel.SetText(fmt.Sprintf("Added %d, updated %d, removed %d, failed %d",
+					stats.Added, stats.Updated, stats.Removed, stats.Failed))
+			})
+		}()
+	})
+
+	search()
+	if len(entries) == 0 {
+		statusLabel.SetText("The media library is empty. Press Rescan to index the local media directories.")
+	}
+
+	dialog.ShowAll()
+	if dialog.Run() != gtk.RESPONSE_ACCEPT {
+		return "", nil
+	}
+
+	// Get selected entry.
+	selection, err := treeView.GetSelection()
+	if err != nil {
+		return "", err
+	}
+	_, iter, ok := selection.GetSelected()
+	if !ok {
+		return "", nil
+	}
+	value, err := store.GetValue(iter, libColIndex)
+	if err != nil {
+		return "", err
+	}
+	idx, err := value.GoValue()
+	if err != nil {
+		return "", err
+	}
+	if i, ok := idx.(int); ok && i >= 0 && i < len(entries) {
+		return entries[i].Path, nil
+	}
+
+	return "", nil
+}
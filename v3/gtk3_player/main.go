@@ -1,3 +1,5 @@
+//go:build gtk3
+
 package main
 
 import (
@@ -6,70 +8,188 @@ import (
 
 	vlc "github.com/adrg/libvlc-go/v3"
 	"github.com/gotk3/gotk3/cairo"
-	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gdk"
 	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/adrg/libvlc-go-examples/shared/gtkutil"
+	"github.com/adrg/libvlc-go-examples/v3/internal/eqprofile"
+	"github.com/adrg/libvlc-go-examples/v3/internal/gtkvlc"
+	"github.com/adrg/libvlc-go-examples/v3/vlcutil"
 )
 
 const appID = "com.github.libvlc-go.gtk3-media-player-example"
 
-func builderGetObject(builder *gtk.Builder, name string) glib.IObject {
-	obj, _ := builder.GetObject(name)
-	return obj
-}
+func main() {
+	// Initialize libVLC module and create new GTK application.
+	app, err := gtkvlc.NewApp(appID)
+	gtkutil.AssertErr(err)
 
-func assertErr(err error) {
+	// Create a new player.
+	player, err := vlc.NewPlayer()
+	gtkutil.AssertErr(err)
+
+	// Load equalizer profile rules.
+	rules, err := eqprofile.LoadRules()
 	if err != nil {
-		log.Panic(err)
+		log.Printf("Cannot load equalizer profile rules: %s\n", err)
 	}
-}
 
-func assertConv(ok bool) {
-	if !ok {
-		log.Panic("invalid widget conversion")
-	}
-}
+	var (
+		equalizer    *vlc.Equalizer
+		cancelParse  func()
+		renderers    *rendererMenu
+		playerWindow *gdk.Window
+	)
 
-func playerReleaseMedia(player *vlc.Player) {
-	player.Stop()
-	if media, _ := player.Media(); media != nil {
-		media.Release()
-	}
-}
+	// Applies the settings of the specified equalizer profile.
+	applyProfile := func(profile eqprofile.Profile) {
+		eq, err := eqprofile.NewEqualizer(profile)
+		if err != nil {
+			log.Printf("Cannot create equalizer: %s\n", err)
+			return
+		}
+		if err := player.SetEqualizer(eq); err != nil {
+			log.Printf("Cannot set player equalizer: %s\n", err)
+		}
 
-func main() {
-	// Initialize libVLC module.
-	err := vlc.Init("--quiet", "--no-xlib")
-	assertErr(err)
+		// Release previous equalizer.
+		if equalizer != nil {
+			equalizer.Release()
+		}
+		equalizer = eq
+	}
 
-	// Create a new player.
-	player, err := vlc.NewPlayer()
-	assertErr(err)
+	// Applies the equalizer profile matching the loaded media.
+	applyMediaProfile := func(path string, media *vlc.Media) {
+		if rule := eqprofile.MatchRule(rules, path, ""); rule != nil {
+			applyProfile(rule.Profile)
+			return
+		}
 
-	// Create new GTK application.
-	app, err := gtk.ApplicationNew(appID, glib.APPLICATION_FLAGS_NONE)
-	assertErr(err)
+		// Parse media in order to retrieve its genre.
+		cancelParse, err = eqprofile.ParseMediaGenre(media, func(genre string) {
+			cancelParse = nil
 
-	app.Connect("activate", func() {
-		// Load application layout.
-		builder, err := gtk.BuilderNewFromFile("layout.glade")
-		assertErr(err)
+			var profile eqprofile.Profile
+			if rule := eqprofile.MatchRule(rules, "", genre); rule != nil {
+				profile = rule.Profile
+			}
+			applyProfile(profile)
+		})
+		if err != nil {
+			log.Printf("Cannot parse media: %s\n", err)
+		}
+	}
 
+	app.OnActivate("layout.glade", func(builder *gtk.Builder) {
 		// Get application window.
-		appWin, ok := builderGetObject(builder, "appWindow").(*gtk.ApplicationWindow)
-		assertConv(ok)
+		appWin := gtkutil.MustGet[*gtk.ApplicationWindow](builder, "appWindow")
 
 		// Get play button.
-		playButton, ok := builderGetObject(builder, "playButton").(*gtk.Button)
-		assertConv(ok)
+		playButton := gtkutil.MustGet[*gtk.Button](builder, "playButton")
+
+		// Sets the output of the player. The renderer cannot be removed
+		// from the player, so switching to local output replaces the player
+		// with a new one, having the same media, window and equalizer.
+		applyRenderer := func(renderer *vlc.Renderer) error {
+			if renderer != nil {
+				return player.SetRenderer(renderer)
+			}
+
+			newPlayer, err := vlcutil.ClearRenderer(player, func(p *vlc.Player) error {
+				if playerWindow != nil {
+					if err := gtkvlc.SetPlayerWindow(p, playerWindow); err != nil {
+						return err
+					}
+				}
+				if equalizer != nil {
+					return p.SetEqualizer(equalizer)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			player = newPlayer
+			return nil
+		}
+
+		// Switches the player output to the specified renderer. Local
+		// output is used if the renderer is nil.
+		setRenderer := func(renderer *vlc.Renderer) {
+			state, err := player.MediaState()
+			if err != nil || (state != vlc.MediaPlaying && state != vlc.MediaPaused) {
+				if err := applyRenderer(renderer); err != nil {
+					log.Printf("Cannot set renderer: %s\n", err)
+				}
+				return
+			}
+
+			// The renderer is only used when playback starts, so playback
+			// is restarted from the current position.
+			mediaTime, _ := player.MediaTime()
+			player.Stop()
+			if err := applyRenderer(renderer); err != nil {
+				log.Printf("Cannot set renderer: %s\n", err)
+			}
+			if err := player.Play(); err != nil {
+				log.Printf("Cannot restart playback: %s\n", err)
+				playButton.SetLabel("gtk-media-play")
+				return
+			}
+			player.SetMediaTime(mediaTime)
+
+			if state == vlc.MediaPaused {
+				player.SetPause(true)
+				playButton.SetLabel("gtk-media-play")
+			} else {
+				playButton.SetLabel("gtk-media-pause")
+			}
+		}
+
+		// Populate the renderer menu with the discovered renderers.
+		castMenu := gtkutil.MustGet[*gtk.Menu](builder, "castMenu")
+
+		renderers, err = newRendererMenu(castMenu, setRenderer)
+		gtkutil.AssertErr(err)
+		if err := renderers.Start(); err != nil {
+			log.Printf("Cannot start renderer discovery: %s\n", err)
+		}
+
+		// Loads the specified media file and starts playback.
+		openMedia := func(filename string) {
+			// Release current media, if any.
+			if cancelParse != nil {
+				cancelParse()
+				cancelParse = nil
+			}
+			gtkvlc.ReleaseMedia(player)
+
+			// Load media and start playback.
+			media, err := player.LoadMediaFromPath(filename)
+			if err != nil {
+				log.Printf("Cannot load selected media: %s\n", err)
+				return
+			}
+
+			// Apply matching equalizer profile, if any.
+			applyMediaProfile(filename, media)
+
+			player.Play()
+			playButton.SetLabel("gtk-media-pause")
+		}
 
 		// Add builder signal handlers.
 		signals := map[string]interface{}{
 			"onRealizePlayerArea": func(playerArea *gtk.DrawingArea) {
-				// Set window for the player.
-				playerWindow, err := playerArea.GetWindow()
-				assertErr(err)
-				err = setPlayerWindow(player, playerWindow)
-				assertErr(err)
+				// Set window for the player. The window is also set on the
+				// players created when switching back to local output.
+				var err error
+				playerWindow, err = playerArea.GetWindow()
+				gtkutil.AssertErr(err)
+				err = gtkvlc.SetPlayerWindow(player, playerWindow)
+				gtkutil.AssertErr(err)
 			},
 			"onDrawPlayerArea": func(playerArea *gtk.DrawingArea, cr *cairo.Context) {
 				cr.SetSourceRGB(0, 0, 0)
@@ -81,31 +201,33 @@ func main() {
 					appWin, gtk.FILE_CHOOSER_ACTION_OPEN,
 					"Cancel", gtk.RESPONSE_DELETE_EVENT,
 					"Open", gtk.RESPONSE_ACCEPT)
-				assertErr(err)
+				gtkutil.AssertErr(err)
 				defer fileDialog.Destroy()
 
 				fileFilter, err := gtk.FileFilterNew()
-				assertErr(err)
+				gtkutil.AssertErr(err)
 				fileFilter.SetName("Media files")
 				fileFilter.AddPattern("*.mp4")
 				fileFilter.AddPattern("*.mp3")
 				fileDialog.AddFilter(fileFilter)
 
 				if result := fileDialog.Run(); result == gtk.RESPONSE_ACCEPT {
-					// Release current media, if any.
-					playerReleaseMedia(player)
-
-					// Get selected filename.
-					filename := fileDialog.GetFilename()
-
-					// Load media and start playback.
-					if _, err := player.LoadMediaFromPath(filename); err != nil {
-						log.Printf("Cannot load selected media: %s\n", err)
-						return
-					}
-
-					player.Play()
-					playButton.SetLabel("gtk-media-pause")
+					openMedia(fileDialog.GetFilename())
+				}
+			},
+			"onActivateMediaLibrary": func() {
+				filename, err := runLibraryDialog(appWin)
+				if err != nil {
+					log.Printf("Cannot open media library: %s\n", err)
+					return
+				}
+				if filename != "" {
+					openMedia(filename)
+				}
+			},
+			"onActivateEqProfiles": func() {
+				if rules, err = eqprofile.RunRulesDialog(appWin, rules); err != nil {
+					log.Printf("Cannot save equalizer profile rules: %s\n", err)
 				}
 			},
 			"onActivateQuit": func() {
@@ -136,12 +258,22 @@ func main() {
 	})
 
 	// Cleanup on exit.
-	app.Connect("shutdown", func() {
-		playerReleaseMedia(player)
-		player.Release()
-		vlc.Release()
+	app.OnShutdown(func() {
+		if cancelParse != nil {
+			cancelParse()
+		}
+		gtkvlc.ReleasePlayer(player)
+
+		// The renderer used by the player, if any, is released along with
+		// the discovery services, so the player must be released first.
+		if renderers != nil {
+			renderers.Release()
+		}
+		if equalizer != nil {
+			equalizer.Release()
+		}
 	})
 
 	// Launch the application.
-	os.Exit(app.Run(os.Args))
+	os.Exit(app.Run())
 }
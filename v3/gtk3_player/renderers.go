@@ -0,0 +1,185 @@
+//go:build gtk3
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// rendererMenu keeps a menu in sync with the renderers discovered on the
+// local network. The menu always contains a "Local" entry, which selects
+// local playback.
+//
+// Renderers are released by libVLC as soon as they are deleted, while the
+// menu is updated later, on the GTK main thread. The menu entries do not
+// reference renderers directly. Instead, renderers are looked up by key
+// when an entry is selected, and are removed from the lookup table from
+// the discovery callback, before being released.
+type rendererMenu struct {
+	menu        *gtk.Menu
+	localItem   *gtk.RadioMenuItem
+	items       map[string]*gtk.RadioMenuItem
+	discoverers []*vlc.RendererDiscoverer
+	released    bool
+
+	// Renderers which have not been deleted, by key.
+	renderers   map[string]*vlc.Renderer
+	renderersMu sync.Mutex
+
+	// Called when a menu entry is selected. The renderer is nil if the
+	// "Local" entry is selected.
+	onSelect func(renderer *vlc.Renderer)
+}
+
+func newRendererMenu(menu *gtk.Menu, onSelect func(renderer *vlc.Renderer)) (*rendererMenu, error) {
+	localItem, err := gtk.RadioMenuItemNewWithLabel(nil, "Local")
+	if err != nil {
+		return nil, err
+	}
+	localItem.SetActive(true)
+	localItem.Connect("toggled", func() {
+		if localItem.GetActive() {
+			onSelect(nil)
+		}
+	})
+	menu.Append(localItem)
+	localItem.Show()
+
+	return &rendererMenu{
+		menu:      menu,
+		localItem: localItem,
+		items:     map[string]*gtk.RadioMenuItem{},
+		renderers: map[string]*vlc.Renderer{},
+		onSelect:  onSelect,
+	}, nil
+}
+
+// Start starts all the renderer discovery services provided by libVLC.
+// The menu is updated as renderers are added or removed.
+func (m *rendererMenu) Start() error {
+	descriptors, err := vlc.ListRendererDiscoverers()
+	if err != nil {
+		return err
+	}
+
+	for _, descriptor := range descriptors {
+		discoverer, err := vlc.NewRendererDiscoverer(descriptor.Name)
+		if err != nil {
+			log.Printf("Cannot create %s discovery service: %s\n", descriptor.Name, err)
+			continue
+		}
+		if err := discoverer.Start(m.handleEvent); err != nil {
+			log.Printf("Cannot start %s discovery service: %s\n", descriptor.Name, err)
+			discoverer.Release()
+			continue
+		}
+
+		m.discoverers = append(m.discoverers, discoverer)
+	}
+
+	return nil
+}
+
+// Release stops and releases the discovery services. The renderers listed
+// in the menu cannot be selected afterwards.
+func (m *rendererMenu) Release() {
+	for _, discoverer := range m.discoverers {
+		discoverer.Stop()
+		discoverer.Release()
+	}
+	m.discoverers = nil
+	m.released = true
+}
+
+// handleEvent is called by libVLC when a renderer is added or removed.
+func (m *rendererMenu) handleEvent(event vlc.Event, r *vlc.Renderer) {
+	// NOTE: the discovery service cannot be stopped or released from
+	// the callback function. Doing so will result in undefined behavior.
+	// Also, the callback is not called on the GTK main thread, so the
+	// menu is updated using glib.IdleAdd.
+	name, err := r.Name()
+	if err != nil {
+		return
+	}
+	rendererType, err := r.Type()
+	if err != nil {
+		return
+	}
+	key := fmt.Sprintf("%s\x00%s", name, rendererType)
+
+	m.renderersMu.Lock()
+	defer m.renderersMu.Unlock()
+
+	switch event {
+	case vlc.RendererDiscovererItemAdded:
+		m.renderers[key] = r
+
+		label := fmt.Sprintf("%s (%s)", name, rendererType)
+		glib.IdleAdd(func() {
+			m.addRenderer(key, label)
+		})
+	case vlc.RendererDiscovererItemDeleted:
+		// The renderer is released by libVLC after the callback returns,
+		// so it must not be used by the menu from this point on.
+		if m.renderers[key] == r {
+			delete(m.renderers, key)
+		}
+
+		glib.IdleAdd(func() {
+			m.removeRenderer(key)
+		})
+	}
+}
+
+// selectRenderer calls the selection callback of the menu with the renderer
+// having the specified key, if it has not been deleted in the meantime. The
+// renderer cannot be released while the selection callback is running.
+func (m *rendererMenu) selectRenderer(key string) {
+	m.renderersMu.Lock()
+	defer m.renderersMu.Unlock()
+
+	if r, ok := m.renderers[key]; ok {
+		m.onSelect(r)
+	}
+}
+
+func (m *rendererMenu) addRenderer(key, label string) {
+	if _, ok := m.items[key]; ok || m.released {
+		return
+	}
+
+	item, err := gtk.RadioMenuItemNewWithLabelFromWidget(m.localItem, label)
+	if err != nil {
+		log.Printf("Cannot add renderer menu item: %s\n", err)
+		return
+	}
+	item.Connect("toggled", func() {
+		if item.GetActive() {
+			m.selectRenderer(key)
+		}
+	})
+	m.menu.Append(item)
+	item.Show()
+
+	m.items[key] = item
+}
+
+func (m *rendererMenu) removeRenderer(key string) {
+	item, ok := m.items[key]
+	if !ok {
+		return
+	}
+	delete(m.items, key)
+
+	// Fall back to local playback if the removed renderer is in use.
+	if item.GetActive() {
+		m.localItem.SetActive(true)
+	}
+	item.Destroy()
+}
@@ -0,0 +1,167 @@
+//go:build gtk3
+
+package main
+
+import (
+	"log"
+
+	"github.com/adrg/libvlc-go-examples/shared/screencap"
+	"github.com/gotk3/gotk3/cairo"
+	"github.com/gotk3/gotk3/gdk"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// selectScreenArea opens a translucent overlay covering the screen, on
+// which the user can select an area by dragging a rectangle. The callback
+// is called with the selected area once the mouse button is released. The
+// selection is cancelled by pressing Escape or the right mouse button, in
+// which case the callback is called with ok set to false. A compositing
+// window manager is required for the overlay to be translucent.
+func selectScreenArea(callback func(area screencap.Area, ok bool)) error {
+	overlay, err := gtk.WindowNew(gtk.WINDOW_TOPLEVEL)
+	if err != nil {
+		return err
+	}
+	overlay.SetDecorated(false)
+	overlay.SetKeepAbove(true)
+	overlay.SetSkipTaskbarHint(true)
+	overlay.SetAppPaintable(true)
+	overlay.AddEvents(int(gdk.BUTTON_PRESS_MASK | gdk.BUTTON_RELEASE_MASK | gdk.POINTER_MOTION_MASK | gdk.KEY_PRESS_MASK))
+
+	// Cover the whole screen, including all monitors.
+	screen, err := gdk.ScreenGetDefault()
+	if err != nil {
+		overlay.Destroy()
+		return err
+	}
+	if visual, err := screen.GetRGBAVisual(); err == nil && visual != nil {
+		overlay.SetVisual(visual)
+	} else {
+		log.Println("Screen does not support transparency. Area selection overlay will be opaque.")
+	}
+	bounds, err := screenBounds(screen)
+	if err != nil {
+		overlay.Destroy()
+		return err
+	}
+	overlay.Move(bounds.X, bounds.Y)
+	overlay.Resize(bounds.Width, bounds.Height)
+
+	var (
+		dragging       bool
+		startX, startY int
+		area           screencap.Area
+		done           bool
+	)
+
+	finish := func(ok bool) {
+		if done {
+			return
+		}
+		done = true
+
+		overlay.Destroy()
+		callback(area, ok && !area.IsEmpty())
+	}
+	overlay.Connect("destroy", func() {
+		finish(false)
+	})
+
+	overlay.Connect("draw", func(overlay *gtk.Window, cr *cairo.Context) bool {
+		// Dim the screen.
+		cr.SetOperator(cairo.OPERATOR_SOURCE)
+		cr.SetSourceRGBA(0, 0, 0, 0.4)
+		cr.Paint()
+
+		if area.IsEmpty() {
+			return true
+		}
+
+		// Clear the selected area and draw its border. The area uses root
+		// window coordinates, so it is translated to overlay coordinates.
+		x, y := float64(area.X-bounds.X), float64(area.Y-bounds.Y)
+		w, h := float64(area.Width), float64(area.Height)
+
+		cr.SetSourceRGBA(0, 0, 0, 0)
+		cr.Rectangle(x, y, w, h)
+		cr.Fill()
+
+		cr.SetOperator(cairo.OPERATOR_OVER)
+		cr.SetSourceRGBA(0.2, 0.6, 1, 1)
+		cr.SetLineWidth(2)
+		cr.Rectangle(x, y, w, h)
+		cr.Stroke()
+
+		return true
+	})
+	overlay.Connect("button-press-event", func(overlay *gtk.Window, ev *gdk.Event) bool {
+		event := gdk.EventButtonNewFromEvent(ev)
+		if event.Button() != gdk.BUTTON_PRIMARY {
+			finish(false)
+			return true
+		}
+
+		dragging = true
+		startX, startY = int(event.XRoot()), int(event.YRoot())
+		area = screencap.Area{X: startX, Y: startY}
+		return true
+	})
+	overlay.Connect("motion-notify-event", func(overlay *gtk.Window, ev *gdk.Event) bool {
+		if !dragging {
+			return false
+		}
+
+		x, y := gdk.EventMotionNewFromEvent(ev).MotionValRoot()
+		area = screencap.NewArea(startX, startY, int(x), int(y))
+		overlay.QueueDraw()
+		return true
+	})
+	overlay.Connect("button-release-event", func(overlay *gtk.Window, ev *gdk.Event) bool {
+		if !dragging {
+			return false
+		}
+		dragging = false
+
+		event := gdk.EventButtonNewFromEvent(ev)
+		area = screencap.NewArea(startX, startY, int(event.XRoot()), int(event.YRoot()))
+		finish(true)
+		return true
+	})
+	overlay.Connect("key-press-event", func(overlay *gtk.Window, ev *gdk.Event) bool {
+		if gdk.EventKeyNewFromEvent(ev).KeyVal() == gdk.KEY_Escape {
+			finish(false)
+			return true
+		}
+
+		return false
+	})
+
+	overlay.ShowAll()
+	overlay.Present()
+	return nil
+}
+
+// screenBounds returns the area covered by all the monitors of the specified
+// screen. The geometry of the monitor containing the root window is used if
+// the monitors cannot be enumerated.
+func screenBounds(screen *gdk.Screen) (screencap.Area, error) {
+	display, err := screen.GetDisplay()
+	if err != nil {
+		return screencap.Area{}, err
+	}
+	if monitors, err := listMonitors(display); err == nil && len(monitors) > 0 {
+		return screencap.AllMonitors(monitors).Area(), nil
+	}
+
+	rootWindow, err := screen.GetRootWindow()
+	if err != nil {
+		return screencap.Area{}, err
+	}
+	monitor, err := display.GetMonitorAtWindow(rootWindow)
+	if err != nil {
+		return screencap.Area{}, err
+	}
+
+	x, y, w, h := monitor.GetGeometry().GetRectangleInt()
+	return screencap.Area{X: x, Y: y, Width: w, Height: h}, nil
+}
@@ -1,106 +1,450 @@
+//go:build gtk3
+
 package main
 
 import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	vlc "github.com/adrg/libvlc-go/v3"
+	"github.com/gotk3/gotk3/cairo"
+	"github.com/gotk3/gotk3/gdk"
 	"github.com/gotk3/gotk3/glib"
 	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/adrg/libvlc-go-examples/shared/gtkutil"
+	"github.com/adrg/libvlc-go-examples/shared/screencap"
+	"github.com/adrg/libvlc-go-examples/v3/internal/gtkvlc"
+	"github.com/adrg/libvlc-go-examples/v3/vlcutil"
 )
 
 const appID = "com.github.libvlc-go.gtk3-screen-recorder-example"
 
-func builderGetObject(builder *gtk.Builder, name string) glib.IObject {
-	obj, _ := builder.GetObject(name)
-	return obj
-}
+func main() {
+	// Initialize libVLC module and create new GTK application.
+	app, err := gtkvlc.NewApp(appID)
+	gtkutil.AssertErr(err)
+
+	// Create a new player.
+	player, err := vlc.NewPlayer()
+	gtkutil.AssertErr(err)
+
+	// Create screen recorder.
+	rec := newRecorder(player)
 
-func assertErr(err error) {
+	// Load output profiles.
+	profiles, err := loadOutputProfiles()
 	if err != nil {
-		log.Panic(err)
+		log.Printf("Cannot load user defined output profiles: %s\n", err)
 	}
-}
 
-func assertConv(ok bool) {
-	if !ok {
-		log.Panic("invalid widget conversion")
-	}
-}
+	app.OnActivate("layout.glade", func(builder *gtk.Builder) {
+		// Get application window.
+		appWin := gtkutil.MustGet[*gtk.ApplicationWindow](builder, "appWindow")
 
-func playerReleaseMedia(player *vlc.Player) {
-	player.Stop()
-	if media, _ := player.Media(); media != nil {
-		media.Release()
-	}
-}
+		// Get capture area frame controls.
+		captureAreaFrame := gtkutil.MustGet[*gtk.Frame](builder, "captureAreaFrame")
 
-func main() {
-	// Initialize libVLC module.
-	err := vlc.Init("--quiet", "--no-xlib")
-	assertErr(err)
+		entireScreenRadio := gtkutil.MustGet[*gtk.RadioButton](builder, "radioEntireScreen")
 
-	// Create a new player.
-	player, err := vlc.NewPlayer()
-	assertErr(err)
+		rectangleRadio := gtkutil.MustGet[*gtk.RadioButton](builder, "radioSelectRectangle")
 
-	// Create new GTK application.
-	app, err := gtk.ApplicationNew(appID, glib.APPLICATION_FLAGS_NONE)
-	assertErr(err)
+		xInput := gtkutil.MustGet[*gtk.SpinButton](builder, "xInput")
 
-	app.Connect("activate", func() {
-		// Load application layout.
-		builder, err := gtk.BuilderNewFromFile("layout.glade")
-		assertErr(err)
+		yInput := gtkutil.MustGet[*gtk.SpinButton](builder, "yInput")
 
-		// Get application window.
-		appWin, ok := builderGetObject(builder, "appWindow").(*gtk.ApplicationWindow)
-		assertConv(ok)
+		wInput := gtkutil.MustGet[*gtk.SpinButton](builder, "widthInput")
 
-		// Get capture area frame controls.
-		captureAreaFrame, ok := builderGetObject(builder, "captureAreaFrame").(*gtk.Frame)
-		assertConv(ok)
+		hInput := gtkutil.MustGet[*gtk.SpinButton](builder, "heightInput")
 
-		entireScreenRadio, ok := builderGetObject(builder, "radioEntireScreen").(*gtk.RadioButton)
-		assertConv(ok)
+		areaRectBox := gtkutil.MustGet[*gtk.Box](builder, "rectangleAreaBox")
 
-		xInput, ok := builderGetObject(builder, "xInput").(*gtk.SpinButton)
-		assertConv(ok)
+		pickWindowButton := gtkutil.MustGet[*gtk.Button](builder, "pickWindowButton")
 
-		yInput, ok := builderGetObject(builder, "yInput").(*gtk.SpinButton)
-		assertConv(ok)
+		monitorComboBox := gtkutil.MustGet[*gtk.ComboBoxText](builder, "monitorComboBox")
 
-		wInput, ok := builderGetObject(builder, "widthInput").(*gtk.SpinButton)
-		assertConv(ok)
+		// Fill monitors combo box. The first entry is a placeholder, which
+		// is selected after a monitor is picked. The second one covers all
+		// monitors.
+		var monitors []*screencap.Monitor
+		fillMonitors := func() {
+			display, err := gdk.DisplayGetDefault()
+			if err == nil {
+				monitors, err = listMonitors(display)
+			}
+			if err != nil {
+				log.Printf("Cannot list monitors: %s\n", err)
+			}
 
-		hInput, ok := builderGetObject(builder, "heightInput").(*gtk.SpinButton)
-		assertConv(ok)
+			monitorComboBox.RemoveAll()
+			monitorComboBox.AppendText("Select monitor...")
+			monitorComboBox.AppendText("All monitors")
+			for _, m := range monitors {
+				monitorComboBox.AppendText(m.String())
+			}
+			monitorComboBox.SetActive(0)
+		}
+		fillMonitors()
 
-		areaRectBox, ok := builderGetObject(builder, "rectangleAreaBox").(*gtk.Box)
-		assertConv(ok)
+		// Refresh monitors when the monitor configuration changes.
+		if display, err := gdk.DisplayGetDefault(); err == nil {
+			display.Connect("monitor-added", fillMonitors)
+			display.Connect("monitor-removed", fillMonitors)
+		}
 
 		// Get recording options frame controls.
-		recOptionsFrame, ok := builderGetObject(builder, "recordingOptionsFrame").(*gtk.Frame)
-		assertConv(ok)
+		recOptionsFrame := gtkutil.MustGet[*gtk.Frame](builder, "recordingOptionsFrame")
+
+		followMouseCheck := gtkutil.MustGet[*gtk.CheckButton](builder, "followMouseCheck")
+
+		fpsInput := gtkutil.MustGet[*gtk.SpinButton](builder, "fpsInput")
+
+		profileComboBox := gtkutil.MustGet[*gtk.ComboBoxText](builder, "profileComboBox")
+
+		// Fill output profiles combo box.
+		for _, profile := range profiles {
+			profileComboBox.AppendText(profile.Name)
+		}
+		profileComboBox.SetActive(0)
+
+		selectedProfile := func() *vlcutil.OutputProfile {
+			idx := profileComboBox.GetActive()
+			if idx < 0 || idx >= len(profiles) {
+				return profiles[0]
+			}
+
+			return profiles[idx]
+		}
+
+		// Get audio frame controls.
+		audioFrame := gtkutil.MustGet[*gtk.Frame](builder, "audioFrame")
+
+		captureAudioCheck := gtkutil.MustGet[*gtk.CheckButton](builder, "captureAudioCheck")
+
+		audioOptionsBox := gtkutil.MustGet[*gtk.Box](builder, "audioOptionsBox")
+
+		audioSourceComboBox := gtkutil.MustGet[*gtk.ComboBoxText](builder, "audioSourceComboBox")
+
+		audioCodecComboBox := gtkutil.MustGet[*gtk.ComboBoxText](builder, "audioCodecComboBox")
+
+		// Fill audio sources combo box.
+		var audioSources []*screencap.AudioSource
+		fillAudioSources := func() {
+			audioSources = screencap.ListAudioSources()
+
+			audioSourceComboBox.RemoveAll()
+			for _, source := range audioSources {
+				audioSourceComboBox.AppendText(source.Name)
+			}
+			audioSourceComboBox.SetActive(0)
+		}
+		fillAudioSources()
+
+		// Fill audio codecs combo box.
+		for _, codec := range screencap.AudioCodecs {
+			audioCodecComboBox.AppendText(codec.Name)
+		}
+		audioCodecComboBox.SetActive(screencap.AudioCodecIndex(selectedProfile().AudioCodec))
+
+		// Get preview frame controls.
+		previewArea := gtkutil.MustGet[*gtk.DrawingArea](builder, "previewArea")
+
+		showPreviewCheck := gtkutil.MustGet[*gtk.CheckButton](builder, "showPreviewCheck")
+
+		// Get streaming frame controls.
+		streamFrame := gtkutil.MustGet[*gtk.Frame](builder, "streamFrame")
+
+		streamCheck := gtkutil.MustGet[*gtk.CheckButton](builder, "streamCheck")
 
-		followMouseCheck, ok := builderGetObject(builder, "followMouseCheck").(*gtk.CheckButton)
-		assertConv(ok)
+		streamOptionsBox := gtkutil.MustGet[*gtk.Box](builder, "streamOptionsBox")
 
-		fpsInput, ok := builderGetObject(builder, "fpsInput").(*gtk.SpinButton)
-		assertConv(ok)
+		streamProtocolComboBox := gtkutil.MustGet[*gtk.ComboBoxText](builder, "streamProtocolComboBox")
+
+		streamPortInput := gtkutil.MustGet[*gtk.SpinButton](builder, "streamPortInput")
+
+		// Fill streaming protocols combo box.
+		for _, protocol := range vlcutil.StreamProtocols {
+			streamProtocolComboBox.AppendText(protocol.Description)
+		}
+		streamProtocolComboBox.SetActive(0)
 
 		// Get destination file frame controls.
-		destFileFrame, ok := builderGetObject(builder, "destinationFileFrame").(*gtk.Frame)
-		assertConv(ok)
+		destFileFrame := gtkutil.MustGet[*gtk.Frame](builder, "destinationFileFrame")
+
+		destInput := gtkutil.MustGet[*gtk.Entry](builder, "destinationInput")
+
+		// Get recording limits frame controls.
+		limitsFrame := gtkutil.MustGet[*gtk.Frame](builder, "limitsFrame")
+
+		maxDurationInput := gtkutil.MustGet[*gtk.SpinButton](builder, "maxDurationInput")
+
+		maxSizeInput := gtkutil.MustGet[*gtk.SpinButton](builder, "maxSizeInput")
+
+		segmentLengthInput := gtkutil.MustGet[*gtk.SpinButton](builder, "segmentLengthInput")
+
+		// Get status label.
+		statusLabel := gtkutil.MustGet[*gtk.Label](builder, "statusLabel")
+
+		// Get control buttons.
+		recordButton := gtkutil.MustGet[*gtk.Button](builder, "recordButton")
+
+		pauseButton := gtkutil.MustGet[*gtk.Button](builder, "pauseButton")
+
+		var recordingID int
+
+		// Returns the screen capture options configured by the user.
+		screenOptions := func() vlc.MediaScreenOptions {
+			opts := vlc.MediaScreenOptions{
+				FPS:         fpsInput.GetValue(),
+				FollowMouse: followMouseCheck.GetActive(),
+			}
+			if opts.FPS <= 0 {
+				opts.FPS = 30
+				fpsInput.SetValue(opts.FPS)
+			}
+
+			// Configure screen capture area. The area is converted from
+			// application pixels to device pixels, as expected by libVLC.
+			if !entireScreenRadio.GetActive() {
+				area := screencap.Area{
+					X:      int(xInput.GetValue()),
+					Y:      int(yInput.GetValue()),
+					Width:  int(wInput.GetValue()),
+					Height: int(hInput.GetValue()),
+				}.Scale(float64(deviceScale()))
 
-		destInput, ok := builderGetObject(builder, "destinationInput").(*gtk.Entry)
-		assertConv(ok)
+				opts.X, opts.Y = area.X, area.Y
+				opts.Width, opts.Height = area.Width, area.Height
+			}
+
+			return opts
+		}
+
+		// Starts or stops the preview of the capture area, based on the
+		// state of the preview check button. While recording, the preview
+		// is handled by the stream output chain of the recording.
+		refreshPreview := func() {
+			if rec.IsRecording() {
+				return
+			}
+
+			if !showPreviewCheck.GetActive() {
+				if err := rec.StopPreview(); err != nil {
+					log.Printf("Cannot stop preview: %s\n", err)
+				}
+				previewArea.QueueDraw()
+				return
+			}
+			if err := rec.StartPreview(screenOptions()); err != nil {
+				log.Printf("Cannot start preview: %s\n", err)
+			}
+		}
+
+		setCaptureArea := func(area screencap.Area) {
+			xInput.SetValue(float64(area.X))
+			yInput.SetValue(float64(area.Y))
+			wInput.SetValue(float64(area.Width))
+			hInput.SetValue(float64(area.Height))
+
+			rectangleRadio.SetActive(true)
+			areaRectBox.SetSensitive(true)
+			refreshPreview()
+
+			// Let the user know which monitor is being captured.
+			status := "Capture area: " + area.String()
+			if len(monitors) > 1 && area == screencap.AllMonitors(monitors).Area() {
+				status += " on all monitors"
+			} else if m := screencap.MonitorAt(monitors, area); m != nil {
+				status += " on monitor " + m.Name
+			}
+			statusLabel.SetText(status)
+		}
+
+		setControlsSensitive := func(sensitive bool) {
+			captureAreaFrame.SetSensitive(sensitive)
+			recOptionsFrame.SetSensitive(sensitive)
+			audioFrame.SetSensitive(sensitive)
+			streamFrame.SetSensitive(sensitive)
+			limitsFrame.SetSensitive(sensitive)
+			destFileFrame.SetSensitive(sensitive)
+			showPreviewCheck.SetSensitive(sensitive)
+		}
+
+		currentStatus := func() vlcutil.RecordingStatus {
+			status := vlcutil.NewRecordingStatus(player, rec.Elapsed(), rec.Segments()...)
+			status.Paused = rec.IsPaused()
+			return status
+		}
+
+		// Returns the text of the status line for the specified status,
+		// including the URL of the live stream, if streaming.
+		statusText := func(status vlcutil.RecordingStatus) string {
+			text := status.String()
+			if urls := rec.StreamURLs(); len(urls) > 0 {
+				text += "\nStreaming at " + urls[0]
+			}
+
+			return text
+		}
+
+		stopRecording := func(reason string) {
+			if !rec.IsRecording() {
+				return
+			}
+
+			// Stopping the player finalizes the output file.
+			if err := rec.Stop(); err != nil {
+				log.Printf("Cannot stop recording: %s\n", err)
+			}
+
+			recordButton.SetLabel("gtk-media-record")
+			pauseButton.SetLabel("gtk-media-pause")
+			pauseButton.SetSensitive(false)
+			setControlsSensitive(true)
+
+			segments := rec.Segments()
+			status := "No output saved"
+			switch {
+			case len(segments) == 1:
+				status = fmt.Sprintf("Saved %s", segments[0])
+			case len(segments) > 1:
+				status = fmt.Sprintf("Saved %d segments (%s to %s)",
+					len(segments), segments[0], segments[len(segments)-1])
+			}
+
+			var size int64
+			for _, segment := range segments {
+				if info, err := os.Stat(segment); err == nil {
+					size += info.Size()
+				}
+			}
+			status += fmt.Sprintf(" (%s)", vlcutil.FormatFileSize(size))
+
+			if reason != "" {
+				status = reason + " " + status
+			}
+			statusLabel.SetText(status)
+
+			refreshPreview()
+		}
+
+		// Periodically updates the recording status and stops the recording
+		// when one of the configured limits is reached.
+		watchRecording := func(id int, limits vlcutil.RecordingLimits) {
+			statusLabel.SetText(statusText(currentStatus()))
+
+			glib.TimeoutAdd(500, func() bool {
+				if !rec.IsRecording() || id != recordingID {
+					return false
+				}
+
+				// Stop recording if the player stopped unexpectedly.
+				if !rec.IsPaused() {
+					if state, err := player.MediaState(); err == nil && (state == vlc.MediaEnded || state == vlc.MediaError) {
+						stopRecording("Recording stopped unexpectedly.")
+						return false
+					}
+				}
+
+				status := currentStatus()
+				if status.Exceeds(limits) {
+					stopRecording("Recording limit reached.")
+					return false
+				}
+
+				statusLabel.SetText(statusText(status))
+
+				return true
+			})
+		}
 
 		// Add builder signal handlers.
 		signals := map[string]interface{}{
 			"onClickAreaSelect": func() {
 				areaRectBox.SetSensitive(!entireScreenRadio.GetActive())
+				refreshPreview()
+			},
+			"onRealizePreviewArea": func(previewArea *gtk.DrawingArea) {
+				// Set window for the player.
+				previewWindow, err := previewArea.GetWindow()
+				gtkutil.AssertErr(err)
+				err = gtkvlc.SetPlayerWindow(player, previewWindow)
+				gtkutil.AssertErr(err)
+			},
+			"onDrawPreviewArea": func(previewArea *gtk.DrawingArea, cr *cairo.Context) {
+				cr.SetSourceRGB(0, 0, 0)
+				cr.Paint()
+			},
+			"onTogglePreview": func() {
+				refreshPreview()
+			},
+			"onClickSelectArea": func() {
+				// Hide the application window while selecting the area.
+				appWin.Hide()
+				err := selectScreenArea(func(area screencap.Area, ok bool) {
+					appWin.Show()
+					if ok {
+						setCaptureArea(area)
+					}
+				})
+				if err != nil {
+					appWin.Show()
+					log.Printf("Cannot select screen area: %s\n", err)
+				}
+			},
+			"onClickPickWindow": func() {
+				statusLabel.SetText("Click on the window to capture...")
+				pickWindowButton.SetSensitive(false)
+
+				go func() {
+					area, err := screencap.PickWindowArea()
+					glib.IdleAdd(func() {
+						pickWindowButton.SetSensitive(true)
+						if err != nil {
+							statusLabel.SetText(err.Error())
+							return
+						}
+
+						// The window geometry is reported in device pixels.
+						setCaptureArea(area.Scale(1 / float64(deviceScale())))
+					})
+				}()
+			},
+			"onMonitorChanged": func() {
+				idx := monitorComboBox.GetActive()
+				switch {
+				case idx == 1:
+					setCaptureArea(screencap.AllMonitors(monitors).Area())
+				case idx > 1 && idx-2 < len(monitors):
+					setCaptureArea(monitors[idx-2].Area())
+				default:
+					return
+				}
+
+				// Select the placeholder entry, so that the same monitor can
+				// be selected again after the capture area is changed.
+				monitorComboBox.SetActive(0)
+			},
+			"onProfileChanged": func() {
+				profile := selectedProfile()
+
+				// Update destination file extension.
+				destPath, _ := destInput.GetText()
+				destInput.SetText(profile.FilePath(destPath))
+
+				// Select default audio codec of the profile.
+				audioCodecComboBox.SetActive(screencap.AudioCodecIndex(profile.AudioCodec))
+			},
+			"onToggleStream": func() {
+				streamOptionsBox.SetSensitive(streamCheck.GetActive())
+			},
+			"onToggleCaptureAudio": func() {
+				audioOptionsBox.SetSensitive(captureAudioCheck.GetActive())
+			},
+			"onClickRefreshAudioSources": func() {
+				fillAudioSources()
 			},
 			"onClickChooseDestinationFile": func() {
 				fileDialog, err := gtk.FileChooserDialogNewWith2Buttons(
@@ -108,26 +452,24 @@ func main() {
 					appWin, gtk.FILE_CHOOSER_ACTION_SAVE,
 					"Cancel", gtk.RESPONSE_DELETE_EVENT,
 					"Save", gtk.RESPONSE_ACCEPT)
-				assertErr(err)
+				gtkutil.AssertErr(err)
 				defer fileDialog.Destroy()
 
+				profile := selectedProfile()
+
 				fileFilter, err := gtk.FileFilterNew()
-				assertErr(err)
-				fileFilter.SetName("Media files")
-				fileFilter.AddPattern("*.mp4")
+				gtkutil.AssertErr(err)
+				fileFilter.SetName(profile.Name + " files")
+				fileFilter.AddPattern("*." + profile.Extension)
 				fileDialog.AddFilter(fileFilter)
 
 				if result := fileDialog.Run(); result == gtk.RESPONSE_ACCEPT {
-					destInput.SetText(fileDialog.GetFilename())
+					destInput.SetText(profile.FilePath(fileDialog.GetFilename()))
 				}
 			},
-			"onClickRecord": func(recordButton *gtk.Button) {
-				if player.IsPlaying() {
-					recordButton.SetLabel("gtk-media-record")
-					captureAreaFrame.SetSensitive(true)
-					recOptionsFrame.SetSensitive(true)
-					destFileFrame.SetSensitive(true)
-					player.Stop()
+			"onClickRecord": func() {
+				if rec.IsRecording() {
+					stopRecording("")
 					return
 				}
 
@@ -137,50 +479,79 @@ func main() {
 					return
 				}
 
-				// Create screen media options.
-				mediaOpts := &vlc.MediaScreenOptions{
-					FPS:         fpsInput.GetValue(),
-					FollowMouse: followMouseCheck.GetActive(),
+				opts := recordingOptions{
+					Screen:        screenOptions(),
+					Profile:       selectedProfile(),
+					Path:          destPath,
+					SegmentLength: time.Duration(segmentLengthInput.GetValue()) * time.Minute,
+					Preview:       showPreviewCheck.GetActive(),
 				}
-				if mediaOpts.FPS <= 0 {
-					mediaOpts.FPS = 30
-					fpsInput.SetValue(mediaOpts.FPS)
+
+				// Configure streaming.
+				if streamCheck.GetActive() {
+					opts.Stream = &vlcutil.StreamOptions{
+						Protocol: vlcutil.StreamProtocols[0].Protocol,
+						Port:     int(streamPortInput.GetValue()),
+					}
+					if idx := streamProtocolComboBox.GetActive(); idx >= 0 && idx < len(vlcutil.StreamProtocols) {
+						opts.Stream.Protocol = vlcutil.StreamProtocols[idx].Protocol
+					}
 				}
 
-				// Configure screen capture area.
-				if !entireScreenRadio.GetActive() {
-					mediaOpts.X = int(xInput.GetValue())
-					mediaOpts.Y = int(yInput.GetValue())
-					mediaOpts.Width = int(wInput.GetValue())
-					mediaOpts.Height = int(hInput.GetValue())
+				// Configure audio capture.
+				if captureAudioCheck.GetActive() {
+					if idx := audioSourceComboBox.GetActive(); idx >= 0 && idx < len(audioSources) {
+						opts.AudioSource = audioSources[idx]
+
+						opts.AudioCodec = screencap.AudioCodecs[0]
+						if idx := audioCodecComboBox.GetActive(); idx >= 0 && idx < len(screencap.AudioCodecs) {
+							opts.AudioCodec = screencap.AudioCodecs[idx]
+						}
+					}
 				}
 
-				// Create media from screen.
-				media, err := vlc.NewMediaFromScreen(mediaOpts)
-				if err != nil {
-					log.Fatalf("Cannot load screen media: %s\n", err)
+				// Start screen recording.
+				if err := rec.Start(opts); err != nil {
+					statusLabel.SetText("Cannot start recording: " + err.Error())
+					refreshPreview()
+					return
 				}
+				recordingID++
 
-				// Configure media to save the recording to the selected destination path.
-				saveOpt := fmt.Sprintf(":sout=#transcode{vcodec=h264,vb=0,scale=1}:duplicate{dst=file{dst=%s}}", destPath)
-				if err := media.AddOptions(saveOpt); err != nil {
-					log.Fatalf("Cannot add media options: %s\n", err)
+				for _, url := range rec.StreamURLs() {
+					log.Printf("Streaming at %s\n", url)
 				}
 
-				// Set player media.
-				if err := player.SetMedia(media); err != nil {
-					log.Fatalf("Cannot set player media: %s\n", err)
+				recordButton.SetLabel("gtk-media-stop")
+				pauseButton.SetSensitive(true)
+				setControlsSensitive(false)
+
+				// Monitor recording.
+				watchRecording(recordingID, vlcutil.RecordingLimits{
+					MaxDuration: time.Duration(maxDurationInput.GetValue()) * time.Minute,
+					MaxSize:     int64(maxSizeInput.GetValue()) * 1024 * 1024,
+				})
+			},
+			"onClickPause": func() {
+				if !rec.IsRecording() {
+					return
 				}
 
-				// Start screen recording.
-				if err := player.Play(); err != nil {
-					log.Fatalf("Cannot play media: %s\n", err)
+				if rec.IsPaused() {
+					if err := rec.Resume(); err != nil {
+						log.Printf("Cannot resume recording: %s\n", err)
+						return
+					}
+					pauseButton.SetLabel("gtk-media-pause")
+				} else {
+					if err := rec.Pause(); err != nil {
+						log.Printf("Cannot pause recording: %s\n", err)
+						return
+					}
+					pauseButton.SetLabel("gtk-media-play")
 				}
 
-				recordButton.SetLabel("gtk-media-stop")
-				captureAreaFrame.SetSensitive(false)
-				recOptionsFrame.SetSensitive(false)
-				destFileFrame.SetSensitive(false)
+				statusLabel.SetText(statusText(currentStatus()))
 			},
 			"onClickClose": func() {
 				app.Quit()
@@ -193,12 +564,12 @@ func main() {
 	})
 
 	// Cleanup on exit.
-	app.Connect("shutdown", func() {
-		playerReleaseMedia(player)
-		player.Release()
-		vlc.Release()
+	app.OnShutdown(func() {
+		rec.Stop()
+		rec.StopPreview()
+		gtkvlc.ReleasePlayer(player)
 	})
 
 	// Launch the application.
-	os.Exit(app.Run(os.Args))
+	os.Exit(app.Run())
 }
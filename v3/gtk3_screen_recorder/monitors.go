@@ -0,0 +1,67 @@
+//go:build gtk3
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/adrg/libvlc-go-examples/shared/screencap"
+	"github.com/gotk3/gotk3/gdk"
+)
+
+// listMonitors returns the monitors of the specified display. The geometry
+// of the monitors is expressed in application pixels, like all the other
+// coordinates used by the user interface. Use deviceScale in order to
+// convert it to the device pixels expected by the libVLC screen module.
+func listMonitors(display *gdk.Display) ([]*screencap.Monitor, error) {
+	count := display.GetNMonitors()
+	monitors := make([]*screencap.Monitor, 0, count)
+	for i := 0; i < count; i++ {
+		gdkMonitor, err := display.GetMonitor(i)
+		if err != nil {
+			return nil, err
+		}
+
+		name := strings.TrimSpace(gdkMonitor.GetManufacturer() + " " + gdkMonitor.GetModel())
+		if name == "" {
+			name = fmt.Sprintf("Monitor %d", i+1)
+		}
+
+		x, y, w, h := gdkMonitor.GetGeometry().GetRectangleInt()
+		monitors = append(monitors, &screencap.Monitor{
+			Index:   i,
+			Name:    name,
+			Primary: gdkMonitor.IsPrimary(),
+			X:       x,
+			Y:       y,
+			Width:   w,
+			Height:  h,
+		})
+	}
+
+	return monitors, nil
+}
+
+// deviceScale returns the factor used to convert application pixels to
+// the device pixels expected by the libVLC screen module and reported by
+// X11 tools like xwininfo. X11 applies the same scale factor to all the
+// monitors, so the one of the primary monitor is used.
+func deviceScale() int {
+	display, err := gdk.DisplayGetDefault()
+	if err != nil {
+		return 1
+	}
+
+	monitor, err := display.GetPrimaryMonitor()
+	if err != nil || monitor == nil {
+		if monitor, err = display.GetMonitor(0); err != nil || monitor == nil {
+			return 1
+		}
+	}
+	if scale := monitor.GetScaleFactor(); scale > 1 {
+		return scale
+	}
+
+	return 1
+}
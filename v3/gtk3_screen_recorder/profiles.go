@@ -0,0 +1,64 @@
+//go:build gtk3
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/adrg/libvlc-go-examples/v3/vlcutil"
+)
+
+func outputProfilesPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "libvlc-go-examples", "recorder-profiles.json"), nil
+}
+
+// loadOutputProfiles returns the built-in output profiles, along with the
+// user defined profiles read from the configuration directory. User
+// defined profiles replace the built-in profiles having the same name.
+func loadOutputProfiles() ([]*vlcutil.OutputProfile, error) {
+	profiles := make([]*vlcutil.OutputProfile, len(vlcutil.OutputProfiles))
+	copy(profiles, vlcutil.OutputProfiles)
+
+	profilesPath, err := outputProfilesPath()
+	if err != nil {
+		return profiles, err
+	}
+
+	data, err := os.ReadFile(profilesPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return profiles, nil
+		}
+		return profiles, err
+	}
+
+	var userProfiles []*vlcutil.OutputProfile
+	if err := json.Unmarshal(data, &userProfiles); err != nil {
+		return profiles, err
+	}
+
+UserProfiles:
+	for _, userProfile := range userProfiles {
+		if userProfile.Name == "" || userProfile.VideoCodec == "" || userProfile.Mux == "" {
+			continue
+		}
+
+		for i, profile := range profiles {
+			if profile.Name == userProfile.Name {
+				profiles[i] = userProfile
+				continue UserProfiles
+			}
+		}
+		profiles = append(profiles, userProfile)
+	}
+
+	return profiles, nil
+}
@@ -0,0 +1,378 @@
+//go:build gtk3
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+
+	"github.com/adrg/libvlc-go-examples/shared/screencap"
+	"github.com/adrg/libvlc-go-examples/v3/vlcutil"
+)
+
+// recordingOptions contains the settings of a recording session.
+type recordingOptions struct {
+	// Screen capture options.
+	Screen vlc.MediaScreenOptions
+
+	// Audio capture device. Default: nil (no audio).
+	AudioSource *screencap.AudioSource
+
+	// Audio codec used to encode captured audio.
+	AudioCodec *screencap.AudioCodec
+
+	// Output profile used to encode and store the recording.
+	Profile *vlcutil.OutputProfile
+
+	// Output file path.
+	Path string
+
+	// Length of the output segments. If specified, the output is split into
+	// numbered MPEG-TS files (e.g. rec-001.ts, rec-002.ts), each one
+	// containing the specified amount of recording time, and a playlist
+	// listing them (e.g. rec.m3u8). Default: 0 (single file).
+	SegmentLength time.Duration
+
+	// Display the captured screen in the player window while recording.
+	Preview bool
+
+	// Serve the recording as a live stream on the local network.
+	// Default: nil (no streaming).
+	Stream *vlcutil.StreamOptions
+}
+
+// recorder manages screen recording sessions. Sessions can be paused and
+// resumed while writing to the same output file and can be split into
+// segments of fixed length.
+//
+// Segmented recordings use a single stream output chain, whose `livehttp`
+// access module starts a new output file each time the configured segment
+// length is reached. The names of the files are generated from a template
+// containing the segment number.
+//
+// Pausing a recording stops the screen capture input of the player but
+// keeps its stream output open (`sout-keep`). Resuming the recording plays
+// a new screen capture input using the same stream output chain, which
+// libVLC reuses. The `gather` module of the chain merges the elementary
+// streams of the inputs so that the output file contains a single
+// continuous stream. Stopping the player closes the stream output and
+// finalizes the output file.
+type recorder struct {
+	player *vlc.Player
+	media  *vlc.Media
+	opts   recordingOptions
+
+	recording  bool
+	paused     bool
+	previewing bool
+
+	// Recording time accounting.
+	startedAt   time.Time
+	pausedAt    time.Time
+	pausedTotal time.Duration
+
+	// HTTP server used to serve HLS streams, along with the temporary
+	// directory containing the HLS segments.
+	hlsServer *http.Server
+	hlsDir    string
+}
+
+func newRecorder(player *vlc.Player) *recorder {
+	return &recorder{player: player}
+}
+
+// Start starts a new recording session using the provided options.
+func (r *recorder) Start(opts recordingOptions) error {
+	if r.recording {
+		return errors.New("recording already in progress")
+	}
+	if opts.Profile == nil || opts.Path == "" {
+		return errors.New("output profile and path must be specified")
+	}
+
+	if opts.Stream != nil {
+		stream := *opts.Stream
+		opts.Stream = &stream
+	}
+
+	r.opts = opts
+	r.previewing = false
+	if err := r.startStreamServer(); err != nil {
+		return err
+	}
+	if err := r.setMedia(); err != nil {
+		r.stopStreamServer()
+		return err
+	}
+	if err := r.player.Play(); err != nil {
+		r.stopStreamServer()
+		return err
+	}
+
+	r.recording, r.paused = true, false
+	r.startedAt, r.pausedTotal = time.Now(), 0
+	return nil
+}
+
+// Pause pauses the current recording session.
+func (r *recorder) Pause() error {
+	if !r.recording || r.paused {
+		return nil
+	}
+
+	// Stop the current input by replacing it with a new one. The stream
+	// output is kept alive and the new input is started when resuming.
+	if err := r.setMedia(); err != nil {
+		return err
+	}
+
+	r.paused, r.pausedAt = true, time.Now()
+	return nil
+}
+
+// Resume resumes the current recording session.
+func (r *recorder) Resume() error {
+	if !r.recording || !r.paused {
+		return nil
+	}
+	if err := r.player.Play(); err != nil {
+		return err
+	}
+
+	r.paused = false
+	r.pausedTotal += time.Since(r.pausedAt)
+	return nil
+}
+
+// Stop stops the current recording session and finalizes the output file.
+func (r *recorder) Stop() error {
+	if !r.recording {
+		return nil
+	}
+	r.recording, r.paused = false, false
+
+	err := r.player.Stop()
+	r.stopStreamServer()
+	return err
+}
+
+// StartPreview displays the screen area described by the specified options
+// in the player window, without recording it.
+func (r *recorder) StartPreview(screen vlc.MediaScreenOptions) error {
+	if r.recording {
+		return errors.New("cannot preview while recording")
+	}
+
+	media, err := vlc.NewMediaFromScreen(&screen)
+	if err != nil {
+		return fmt.Errorf("cannot load screen media: %w", err)
+	}
+	if err := r.replaceMedia(media); err != nil {
+		return err
+	}
+	if err := r.player.Play(); err != nil {
+		return err
+	}
+
+	r.previewing = true
+	return nil
+}
+
+// StopPreview stops the preview started using StartPreview.
+func (r *recorder) StopPreview() error {
+	if !r.previewing {
+		return nil
+	}
+	r.previewing = false
+
+	return r.player.Stop()
+}
+
+// IsRecording returns true if a recording session is in progress,
+// including when it is paused.
+func (r *recorder) IsRecording() bool {
+	return r.recording
+}
+
+// IsPreviewing returns true if the screen is displayed in the player
+// window, without being recorded.
+func (r *recorder) IsPreviewing() bool {
+	return r.previewing
+}
+
+// IsPaused returns true if the current recording session is paused.
+func (r *recorder) IsPaused() bool {
+	return r.paused
+}
+
+// Elapsed returns the recording time of the current session, excluding
+// the time spent paused.
+func (r *recorder) Elapsed() time.Duration {
+	if r.startedAt.IsZero() {
+		return 0
+	}
+
+	elapsed := time.Since(r.startedAt) - r.pausedTotal
+	if r.paused {
+		elapsed -= time.Since(r.pausedAt)
+	}
+
+	return elapsed
+}
+
+// StreamURLs returns the URLs of the live stream of the current session.
+func (r *recorder) StreamURLs() []string {
+	if r.opts.Stream == nil {
+		return nil
+	}
+
+	return r.opts.Stream.URLs()
+}
+
+// Segments returns the output files of the current session. For
+// segmented recordings, only the segments written so far are returned.
+func (r *recorder) Segments() []string {
+	if r.opts.Path == "" {
+		return nil
+	}
+	if r.opts.SegmentLength <= 0 {
+		return []string{r.opts.Path}
+	}
+
+	var segments []string
+	for number := 1; ; number++ {
+		path := segmentPath(r.opts.Path, number)
+		if _, err := os.Stat(path); err != nil {
+			return segments
+		}
+		segments = append(segments, path)
+	}
+}
+
+// setMedia creates a new screen capture media which outputs to the
+// configured path and sets it as the media of the player.
+func (r *recorder) setMedia() error {
+	screenOpts := r.opts.Screen
+	media, err := vlc.NewMediaFromScreen(&screenOpts)
+	if err != nil {
+		return fmt.Errorf("cannot load screen media: %w", err)
+	}
+
+	// Add the audio source as a slave input of the screen media.
+	var audio *screencap.AudioCodec
+	if r.opts.AudioSource != nil && r.opts.AudioCodec != nil {
+		if err := media.AddOptions(":input-slave=" + r.opts.AudioSource.MRL); err != nil {
+			media.Release()
+			return fmt.Errorf("cannot add media options: %w", err)
+		}
+		audio = r.opts.AudioCodec
+	}
+
+	// Configure media to save the recording to the configured path, using
+	// the settings of the output profile. If preview is enabled, the input
+	// is also displayed in the player window. The stream output is kept
+	// alive between inputs in order to allow pausing the recording.
+	chain := r.opts.Profile.Chain(r.opts.Path, audio)
+	if r.opts.SegmentLength > 0 {
+		var err error
+		if chain, err = r.opts.Profile.SegmentedChain(r.opts.Path, r.opts.SegmentLength, audio); err != nil {
+			media.Release()
+			return err
+		}
+	}
+
+	var dsts []interface{}
+	if r.opts.Preview {
+		dsts = append(dsts, vlcutil.SoutRaw("display"))
+	}
+	if r.opts.Stream != nil {
+		streamChain, err := r.opts.Stream.Chain(audio != nil)
+		if err != nil {
+			media.Release()
+			return err
+		}
+		dsts = append(dsts, streamChain)
+	}
+	if len(dsts) > 0 {
+		duplicate := vlcutil.NewSoutModule("duplicate").Set("dst", chain)
+		for _, dst := range dsts {
+			duplicate.Set("dst", dst)
+		}
+		chain = vlcutil.SoutChain{duplicate}
+	}
+	chain = append(vlcutil.SoutChain{vlcutil.NewSoutModule("gather")}, chain...)
+
+	if err := media.AddOptions(chain.MediaOption(), ":sout-keep"); err != nil {
+		media.Release()
+		return fmt.Errorf("cannot add media options: %w", err)
+	}
+
+	return r.replaceMedia(media)
+}
+
+// replaceMedia sets the specified media as the media of the player and
+// releases the previously set media.
+func (r *recorder) replaceMedia(media *vlc.Media) error {
+	if err := r.player.SetMedia(media); err != nil {
+		media.Release()
+		return fmt.Errorf("cannot set player media: %w", err)
+	}
+
+	// Release the previous media, now replaced by the new one.
+	if r.media != nil {
+		r.media.Release()
+	}
+	r.media = media
+
+	return nil
+}
+
+// startStreamServer starts the HTTP server used to serve HLS streams, if
+// needed. The HLS segments are written to a temporary directory.
+func (r *recorder) startStreamServer() error {
+	stream := r.opts.Stream
+	if stream == nil || stream.Protocol != vlcutil.StreamHLS {
+		return nil
+	}
+
+	if stream.HLSDir == "" {
+		dir, err := os.MkdirTemp("", "libvlc-go-hls-")
+		if err != nil {
+			return fmt.Errorf("cannot create HLS directory: %w", err)
+		}
+		r.hlsDir, stream.HLSDir = dir, dir
+	}
+
+	server, err := stream.ServeHLS()
+	if err != nil {
+		r.stopStreamServer()
+		return fmt.Errorf("cannot start HLS server: %w", err)
+	}
+	r.hlsServer = server
+
+	return nil
+}
+
+func (r *recorder) stopStreamServer() {
+	if r.hlsServer != nil {
+		r.hlsServer.Close()
+		r.hlsServer = nil
+	}
+	if r.hlsDir != "" {
+		os.RemoveAll(r.hlsDir)
+		r.hlsDir = ""
+	}
+}
+
+// segmentPath returns the path of the output segment with the specified
+// number (e.g. rec.mp4 becomes rec-001.ts for the first segment).
+func segmentPath(path string, number int) string {
+	return fmt.Sprintf("%s-%03d.ts", strings.TrimSuffix(path, filepath.Ext(path)), number)
+}
@@ -0,0 +1,241 @@
+//go:build gtk3
+
+package eqprofile
+
+import (
+	"path/filepath"
+	"strings"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+	"github.com/gotk3/gotk3/gtk"
+
+	"github.com/adrg/libvlc-go-examples/v3/vlcutil"
+)
+
+func ruleKindLabel(kind Kind) string {
+	if kind == KindMedia {
+		return "Media file"
+	}
+
+	return "Genre"
+}
+
+// newPresetComboBox creates a combo box containing the available equalizer
+// presets. If custom is true, a "Custom" entry is added after "None".
+func newPresetComboBox(profile Profile, custom bool) (*gtk.ComboBoxText, error) {
+	comboBox, err := gtk.ComboBoxTextNew()
+	if err != nil {
+		return nil, err
+	}
+
+	comboBox.AppendText("None")
+	if custom {
+		comboBox.AppendText("Custom")
+	}
+	for _, presetName := range vlc.EqualizerPresetNames() {
+		comboBox.AppendText(presetName)
+	}
+
+	active := 0
+	if custom {
+		active = 1
+	} else if idx, ok := vlcutil.PresetIndex(profile.Preset); ok {
+		active = int(idx) + 1
+	}
+	comboBox.SetActive(active)
+
+	return comboBox, nil
+}
+
+// RunRulesDialog displays a dialog which allows editing the provided
+// equalizer rules. The rules are saved when the dialog is closed.
+func RunRulesDialog(parent gtk.IWindow, rules []*Rule) ([]*Rule, error) {
+	dialog, err := gtk.DialogNew()
+	if err != nil {
+		return rules, err
+	}
+	defer dialog.Destroy()
+
+	dialog.SetTitle("Equalizer profiles")
+	dialog.SetTransientFor(parent)
+	dialog.SetModal(true)
+	dialog.SetDefaultSize(640, 360)
+	if _, err = dialog.AddButton("Close", gtk.RESPONSE_CLOSE); err != nil {
+		return rules, err
+	}
+
+	contentArea, err := dialog.GetContentArea()
+	if err != nil {
+		return rules, err
+	}
+	contentArea.SetSpacing(10)
+	contentArea.SetMarginStart(10)
+	contentArea.SetMarginEnd(10)
+	contentArea.SetMarginTop(10)
+
+	// Create rules list.
+	rulesListBox, err := gtk.ListBoxNew()
+	if err != nil {
+		return rules, err
+	}
+	rulesListBox.SetSelectionMode(gtk.SELECTION_NONE)
+
+	scrolledWin, err := gtk.ScrolledWindowNew(nil, nil)
+	if err != nil {
+		return rules, err
+	}
+	scrolledWin.SetPolicy(gtk.POLICY_NEVER, gtk.POLICY_AUTOMATIC)
+	scrolledWin.SetVExpand(true)
+	scrolledWin.Add(rulesListBox)
+	contentArea.PackStart(scrolledWin, true, true, 0)
+
+	var fillRulesListBox func() error
+	fillRulesListBox = func() error {
+		rulesListBox.GetChildren().Foreach(func(row interface{}) {
+			if widget, ok := row.(*gtk.Widget); ok {
+				rulesListBox.Remove(widget)
+			}
+		})
+
+		for _, rule := range rules {
+			rule := rule
+
+			kindLabel, err := gtk.LabelNew(ruleKindLabel(rule.Kind))
+			if err != nil {
+				return err
+			}
+			kindLabel.SetWidthChars(10)
+			kindLabel.SetXAlign(0)
+
+			valueLabel, err := gtk.LabelNew(rule.Value)
+			if err != nil {
+				return err
+			}
+			valueLabel.SetXAlign(0)
+			valueLabel.SetHExpand(true)
+			if rule.Kind == KindMedia {
+				valueLabel.SetText(filepath.Base(rule.Value))
+				valueLabel.SetTooltipText(rule.Value)
+			}
+
+			presetComboBox, err := newPresetComboBox(rule.Profile, len(rule.Profile.Bands) > 0)
+			if err != nil {
+				return err
+			}
+			presetComboBox.Connect("changed", func() {
+				text := presetComboBox.GetActiveText()
+				switch text {
+				case "Custom":
+					return
+				case "None":
+					text = ""
+				}
+
+				rule.Profile = Profile{Preset: text}
+			})
+
+			removeButton, err := gtk.ButtonNewWithLabel("Remove")
+			if err != nil {
+				return err
+			}
+			removeButton.Connect("clicked", func() {
+				for i, r := range rules {
+					if r == rule {
+						rules = append(rules[:i], rules[i+1:]...)
+						break
+					}
+				}
+				fillRulesListBox()
+			})
+
+			rowBox, err := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 10)
+			if err != nil {
+				return err
+			}
+			rowBox.SetMarginTop(5)
+			rowBox.SetMarginBottom(5)
+			rowBox.SetMarginStart(5)
+			rowBox.SetMarginEnd(5)
+			rowBox.PackStart(kindLabel, false, false, 0)
+			rowBox.PackStart(valueLabel, true, true, 0)
+			rowBox.PackStart(presetComboBox, false, false, 0)
+			rowBox.PackStart(removeButton, false, false, 0)
+
+			row, err := gtk.ListBoxRowNew()
+			if err != nil {
+				return err
+			}
+			row.Add(rowBox)
+			rulesListBox.Add(row)
+		}
+
+		rulesListBox.ShowAll()
+		return nil
+	}
+	if err := fillRulesListBox(); err != nil {
+		return rules, err
+	}
+
+	// Create new rule controls.
+	kindComboBox, err := gtk.ComboBoxTextNew()
+	if err != nil {
+		return rules, err
+	}
+	kindComboBox.AppendText(ruleKindLabel(KindGenre))
+	kindComboBox.AppendText(ruleKindLabel(KindMedia))
+	kindComboBox.SetActive(0)
+
+	valueEntry, err := gtk.EntryNew()
+	if err != nil {
+		return rules, err
+	}
+	valueEntry.SetPlaceholderText("Genre or media file path")
+	valueEntry.SetHExpand(true)
+
+	presetComboBox, err := newPresetComboBox(Profile{}, false)
+	if err != nil {
+		return rules, err
+	}
+
+	addButton, err := gtk.ButtonNewWithLabel("Add")
+	if err != nil {
+		return rules, err
+	}
+	addButton.Connect("clicked", func() {
+		value, _ := valueEntry.GetText()
+		if value = strings.TrimSpace(value); value == "" {
+			valueEntry.GrabFocus()
+			return
+		}
+
+		kind := KindGenre
+		if kindComboBox.GetActive() == 1 {
+			kind = KindMedia
+		}
+
+		var profile Profile
+		if presetComboBox.GetActive() > 0 {
+			profile.Preset = presetComboBox.GetActiveText()
+		}
+
+		rules = SetRule(rules, kind, value, profile)
+		valueEntry.SetText("")
+		fillRulesListBox()
+	})
+
+	addBox, err := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 10)
+	if err != nil {
+		return rules, err
+	}
+	addBox.SetMarginBottom(10)
+	addBox.PackStart(kindComboBox, false, false, 0)
+	addBox.PackStart(valueEntry, true, true, 0)
+	addBox.PackStart(presetComboBox, false, false, 0)
+	addBox.PackStart(addButton, false, false, 0)
+	contentArea.PackStart(addBox, false, false, 0)
+
+	dialog.ShowAll()
+	dialog.Run()
+
+	return rules, SaveRules(rules)
+}
@@ -0,0 +1,53 @@
+//go:build gtk3
+
+package eqprofile
+
+import (
+	vlc "github.com/adrg/libvlc-go/v3"
+	"github.com/gotk3/gotk3/glib"
+)
+
+// ParseMediaGenre parses the specified media asynchronously and calls the
+// provided callback function with the genre of the media. The callback
+// function is called on the main GTK loop. The returned cancel function
+// must be called before the media is released.
+func ParseMediaGenre(media *vlc.Media, callback func(genre string)) (func(), error) {
+	manager, err := media.EventManager()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		eventID  vlc.EventID
+		detached bool
+	)
+	cancel := func() {
+		if !detached {
+			manager.Detach(eventID)
+			detached = true
+		}
+	}
+
+	eventCallback := func(event vlc.Event, userData interface{}) {
+		// NOTE: the event cannot be detached from the callback function.
+		glib.IdleAdd(func() {
+			if detached {
+				return
+			}
+			cancel()
+
+			genre, _ := media.Meta(vlc.MediaGenre)
+			callback(genre)
+		})
+	}
+
+	if eventID, err = manager.Attach(vlc.MediaParsedChanged, eventCallback, nil); err != nil {
+		return nil, err
+	}
+	if err = media.ParseWithOptions(0, vlc.MediaParseLocal); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return cancel, nil
+}
@@ -0,0 +1,166 @@
+// Package eqprofile implements equalizer profiles which are applied
+// automatically, based on rules matching the path or the genre of the
+// loaded media.
+package eqprofile
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+
+	"github.com/adrg/libvlc-go-examples/v3/vlcutil"
+)
+
+// Kind specifies what an equalizer rule is matched against.
+type Kind string
+
+const (
+	// KindMedia rules match the path of the loaded media file.
+	KindMedia Kind = "media"
+
+	// KindGenre rules match the genre of the loaded media (case insensitive).
+	KindGenre Kind = "genre"
+)
+
+// Profile contains the settings of an equalizer profile. If Bands is
+// empty, the equalizer is created from the preset named Preset. A profile
+// without a preset and without bands disables the equalizer.
+type Profile struct {
+	Preset string    `json:"preset,omitempty"`
+	Preamp float64   `json:"preamp,omitempty"`
+	Bands  []float64 `json:"bands,omitempty"`
+}
+
+// Rule associates an equalizer profile with a media file or genre.
+type Rule struct {
+	Kind    Kind    `json:"kind"`
+	Value   string  `json:"value"`
+	Profile Profile `json:"profile"`
+}
+
+func rulesPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "libvlc-go-examples", "equalizer-rules.json"), nil
+}
+
+// LoadRules loads the equalizer rules stored in the user configuration
+// directory. No error is returned if the rules file does not exist.
+func LoadRules() ([]*Rule, error) {
+	rulesPath, err := rulesPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(rulesPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var rules []*Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// SaveRules stores the specified equalizer rules in the user configuration
+// directory.
+func SaveRules(rules []*Rule) error {
+	rulesPath, err := rulesPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(rulesPath), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(rules, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(rulesPath, data, 0o644)
+}
+
+// SetRule adds a rule for the specified kind and value, replacing the
+// existing one, if any.
+func SetRule(rules []*Rule, kind Kind, value string, profile Profile) []*Rule {
+	for _, rule := range rules {
+		if rule.Kind == kind && rule.Value == value {
+			rule.Profile = profile
+			return rules
+		}
+	}
+
+	return append(rules, &Rule{Kind: kind, Value: value, Profile: profile})
+}
+
+// MatchRule returns the rule matching the specified media path or genre.
+// Media file rules take precedence over genre rules.
+func MatchRule(rules []*Rule, path, genre string) *Rule {
+	var genreRule *Rule
+	for _, rule := range rules {
+		switch rule.Kind {
+		case KindMedia:
+			if path != "" && rule.Value == path {
+				return rule
+			}
+		case KindGenre:
+			if genreRule == nil && genre != "" && strings.EqualFold(rule.Value, strings.TrimSpace(genre)) {
+				genreRule = rule
+			}
+		}
+	}
+
+	return genreRule
+}
+
+// NewEqualizer creates a new equalizer using the settings of the specified
+// profile. Returns a nil equalizer if the profile disables the equalizer.
+func NewEqualizer(profile Profile) (*vlc.Equalizer, error) {
+	var (
+		equalizer *vlc.Equalizer
+		err       error
+	)
+
+	if idx, ok := vlcutil.PresetIndex(profile.Preset); ok {
+		equalizer, err = vlc.NewEqualizerFromPreset(idx)
+	} else if len(profile.Bands) > 0 {
+		equalizer, err = vlc.NewEqualizer()
+	}
+	if err != nil || equalizer == nil {
+		return nil, err
+	}
+	if len(profile.Bands) == 0 {
+		return equalizer, nil
+	}
+
+	// Set custom amplification values.
+	if err := equalizer.SetPreampValue(profile.Preamp); err != nil {
+		equalizer.Release()
+		return nil, err
+	}
+	for i, amp := range profile.Bands {
+		if uint(i) >= vlc.EqualizerBandCount() {
+			break
+		}
+		if err := equalizer.SetAmpValueAtIndex(amp, uint(i)); err != nil {
+			equalizer.Release()
+			return nil, err
+		}
+	}
+
+	return equalizer, nil
+}
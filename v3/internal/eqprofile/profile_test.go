@@ -0,0 +1,32 @@
+package eqprofile
+
+import "testing"
+
+func TestMatchRule(t *testing.T) {
+	var rules []*Rule
+	rules = SetRule(rules, KindGenre, "Rock", Profile{Preset: "Rock"})
+	rules = SetRule(rules, KindMedia, "/music/talk.mp3", Profile{Preset: "Headphones"})
+	rules = SetRule(rules, KindGenre, "Rock", Profile{Preset: "Live"})
+	if len(rules) != 2 {
+		t.Fatalf("got %d rules, want 2", len(rules))
+	}
+
+	tests := []struct {
+		path, genre string
+		want        string
+	}{
+		{"/music/talk.mp3", "Rock", "Headphones"},
+		{"/music/song.mp3", " rock ", "Live"},
+		{"/music/song.mp3", "Jazz", ""},
+		{"", "", ""},
+	}
+	for _, test := range tests {
+		var got string
+		if rule := MatchRule(rules, test.path, test.genre); rule != nil {
+			got = rule.Profile.Preset
+		}
+		if got != test.want {
+			t.Errorf("MatchRule(%q, %q): got preset %q, want %q", test.path, test.genre, got, test.want)
+		}
+	}
+}
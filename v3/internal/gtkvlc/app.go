@@ -0,0 +1,32 @@
+//go:build gtk3
+
+// Package gtkvlc contains the libVLC specific helpers used by the GTK 3
+// examples: a standard application bootstrap, which initializes libVLC and
+// releases it on shutdown, and player lifecycle helpers. The helpers which
+// do not depend on libVLC are provided by the shared gtkutil package.
+package gtkvlc
+
+import (
+	vlc "github.com/adrg/libvlc-go/v3"
+
+	"github.com/adrg/libvlc-go-examples/shared/gtkutil"
+)
+
+// DefaultVLCArgs contains the libVLC initialization arguments used when
+// none are provided to NewApp.
+var DefaultVLCArgs = []string{"--quiet", "--no-xlib"}
+
+// NewApp initializes libVLC using the specified arguments and creates a new
+// GTK application with the specified ID. If no arguments are provided,
+// DefaultVLCArgs is used. libVLC is released when the application shuts
+// down, after the functions registered using OnShutdown are called.
+func NewApp(id string, vlcArgs ...string) (*gtkutil.App, error) {
+	if len(vlcArgs) == 0 {
+		vlcArgs = DefaultVLCArgs
+	}
+
+	return gtkutil.NewApp(id,
+		func() error { return vlc.Init(vlcArgs...) },
+		func() { vlc.Release() },
+	)
+}
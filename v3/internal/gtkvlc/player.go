@@ -0,0 +1,50 @@
+//go:build gtk3
+
+package gtkvlc
+
+import (
+	"runtime"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+	"github.com/gotk3/gotk3/gdk"
+
+	"github.com/adrg/libvlc-go-examples/shared/gtkutil"
+)
+
+// ReleaseMedia stops the specified player and releases its media, if any.
+func ReleaseMedia(player *vlc.Player) {
+	if player == nil {
+		return
+	}
+
+	player.Stop()
+	if media, _ := player.Media(); media != nil {
+		media.Release()
+	}
+}
+
+// ReleasePlayer releases the media of the specified player, if any, and then
+// the player itself.
+func ReleasePlayer(player *vlc.Player) {
+	if player == nil {
+		return
+	}
+
+	ReleaseMedia(player)
+	player.Release()
+}
+
+// SetPlayerWindow sets the specified GDK window as the video output of
+// the player.
+func SetPlayerWindow(player *vlc.Player, window *gdk.Window) error {
+	handle := gtkutil.WindowHandle(window)
+
+	switch runtime.GOOS {
+	case "darwin":
+		return player.SetNSObject(handle)
+	case "windows":
+		return player.SetHWND(handle)
+	default:
+		return player.SetXWindow(uint32(handle))
+	}
+}
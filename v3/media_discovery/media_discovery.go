@@ -0,0 +1,92 @@
+package main
+
+/*
+ * Media discovery tool.
+ *
+ * Usage:
+ *   media_discovery -list [-category lan]
+ *   media_discovery -service upnp [-wait 3s] [-depth 2] [-timeout 30s]
+ *                   [-parse-timeout 5s] [-json]
+ *
+ * The -service mode starts the specified media discovery service, collects
+ * the items it finds within the -wait window and recursively expands their
+ * sub-items (e.g. UPnP and SMB folders), up to the specified depth. The
+ * resulting tree is printed as text or JSON.
+ */
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+
+	"github.com/adrg/libvlc-go-examples/v3/mediatree"
+)
+
+func main() {
+	var (
+		list         = flag.Bool("list", false, "list media discovery services and exit")
+		category     = flag.String("category", "", "list the services of the specified category only: "+mediatree.CategoryNames())
+		serviceName  = flag.String("service", "", "name of the media discovery service to start")
+		wait         = flag.Duration("wait", 3*time.Second, "duration for which top level items are collected")
+		depth        = flag.Int("depth", 2, "maximum depth of the tree (-1 for unlimited)")
+		timeout      = flag.Duration("timeout", 30*time.Second, "maximum duration of the tree expansion")
+		parseTimeout = flag.Duration("parse-timeout", 5*time.Second, "maximum duration for parsing an item")
+		jsonOutput   = flag.Bool("json", false, "print the tree in JSON format")
+	)
+	flag.Parse()
+
+	if !*list && *serviceName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Initialize libVLC. Additional command line arguments can be passed in
+	// to libVLC by specifying them in the Init function.
+	if err := vlc.Init("--quiet"); err != nil {
+		log.Fatal(err)
+	}
+	defer vlc.Release()
+
+	// List media discovery services.
+	if *list {
+		if err := mediatree.ListServices(os.Stdout, *category); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Discover media until the wait duration elapses or the process is
+	// interrupted.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	ctx, cancel := context.WithTimeout(ctx, *wait)
+	items, release, err := mediatree.Discover(ctx, *serviceName)
+	cancel()
+	stop()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer release()
+
+	nodes := mediatree.Build(items, mediatree.Options{
+		MaxDepth:     *depth,
+		ParseTimeout: *parseTimeout,
+		Deadline:     time.Now().Add(*timeout),
+	})
+
+	if *jsonOutput {
+		if err := mediatree.WriteJSON(os.Stdout, nodes); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if len(nodes) == 0 {
+		log.Printf("No media found by %s within %s.\n", *serviceName, *wait)
+		return
+	}
+	mediatree.Print(os.Stdout, nodes)
+}
@@ -0,0 +1,224 @@
+package main
+
+/*
+ * Local media library tool.
+ *
+ * Usage:
+ *   media_library scan [-db path] [-parse-timeout 5s] [-v] [dir ...]
+ *   media_library search [-db path] [-artist name] [-album name]
+ *                        [-genre name] [-json] [text]
+ *   media_library list [-db path] artists|albums|genres
+ *
+ * If no directories are specified, the scan command indexes the local media
+ * directories (e.g. ~/Music, ~/Videos) reported by the local directory
+ * media discovery services of libVLC.
+ */
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+
+	"github.com/adrg/libvlc-go-examples/v3/medialib"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage:
+  %[1]s scan [-db path] [-parse-timeout 5s] [-v] [dir ...]
+  %[1]s search [-db path] [-artist name] [-album name] [-genre name] [-json] [text]
+  %[1]s list [-db path] artists|albums|genres
+`, filepath.Base(os.Args[0]))
+}
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "scan":
+		err = scanCmd(args)
+	case "search":
+		err = searchCmd(args)
+	case "list":
+		err = listCmd(args)
+	case "-h", "-help", "--help", "help":
+		usage()
+	default:
+		log.Printf("Unknown command %q\n", cmd)
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// dbFlag adds the database path flag to the specified flag set.
+func dbFlag(flags *flag.FlagSet) *string {
+	defaultPath, _ := medialib.DefaultPath()
+	return flags.String("db", defaultPath, "path of the library database")
+}
+
+func scanCmd(args []string) error {
+	flags := flag.NewFlagSet("scan", flag.ExitOnError)
+	dbPath := dbFlag(flags)
+	parseTimeout := flags.Duration("parse-timeout", 5*time.Second, "maximum duration for parsing a file")
+	verbose := flags.Bool("v", false, "print the indexed files")
+	flags.Parse(args)
+
+	// Initialize libVLC.
+	if err := vlc.Init("--quiet", "--no-video", "--no-audio"); err != nil {
+		return err
+	}
+	defer vlc.Release()
+
+	lib, err := medialib.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer lib.Close()
+
+	// Get directories to scan.
+	dirs := flags.Args()
+	if len(dirs) == 0 {
+		if dirs, err = medialib.LocalDirs(2 * time.Second); err != nil {
+			return err
+		}
+	}
+
+	// Stop scanning on SIGINT. The files indexed so far are kept.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	scanner := &medialib.Scanner{
+		Library:      lib,
+		ParseTimeout: *parseTimeout,
+		Progress: func(path string, err error) {
+			if err != nil {
+				log.Printf("Cannot index %s: %s\n", path, err)
+			} else if *verbose {
+				log.Printf("Indexed %s\n", path)
+			}
+		},
+	}
+
+	start := time.Now()
+	for _, dir := range dirs {
+		log.Printf("Scanning %s...\n", dir)
+	}
+
+	stats, err := scanner.Scan(ctx, dirs...)
+	if err != nil {
+		return err
+	}
+
+	count, err := lib.Count()
+	if err != nil {
+		return err
+	}
+	log.Printf("Added %d, updated %d, unchanged %d, removed %d, failed %d (%s). %d files indexed.\n",
+		stats.Added, stats.Updated, stats.Unchanged, stats.Removed, stats.Failed,
+		time.Since(start).Truncate(time.Millisecond), count)
+
+	return nil
+}
+
+func searchCmd(args []string) error {
+	flags := flag.NewFlagSet("search", flag.ExitOnError)
+	dbPath := dbFlag(flags)
+	artist := flags.String("artist", "", "artist or album artist (case insensitive)")
+	album := flags.String("album", "", "album (case insensitive)")
+	genre := flags.String("genre", "", "genre (case insensitive)")
+	jsonOutput := flags.Bool("json", false, "print the results in JSON format")
+	flags.Parse(args)
+
+	lib, err := medialib.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer lib.Close()
+
+	entries, err := lib.Search(medialib.Query{
+		Artist: *artist,
+		Album:  *album,
+		Genre:  *genre,
+		Text:   strings.Join(flags.Args(), " "),
+	})
+	if err != nil {
+		return err
+	}
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TITLE\tARTIST\tALBUM\tGENRE\tDURATION\tPATH")
+	for _, entry := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", entry.DisplayTitle(), orDash(entry.Artist),
+			orDash(entry.Album), orDash(entry.Genre), entry.Duration.Round(time.Second), entry.Path)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	log.Printf("%d results.\n", len(entries))
+
+	return nil
+}
+
+func listCmd(args []string) error {
+	flags := flag.NewFlagSet("list", flag.ExitOnError)
+	dbPath := dbFlag(flags)
+	flags.Parse(args)
+
+	fields := map[string]string{
+		"artists": medialib.FieldArtist,
+		"albums":  medialib.FieldAlbum,
+		"genres":  medialib.FieldGenre,
+	}
+
+	field, ok := fields[flags.Arg(0)]
+	if flags.NArg() != 1 || !ok {
+		usage()
+		os.Exit(2)
+	}
+
+	lib, err := medialib.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer lib.Close()
+
+	values, err := lib.Values(field)
+	if err != nil {
+		return err
+	}
+	for _, value := range values {
+		fmt.Println(value)
+	}
+
+	return nil
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
@@ -0,0 +1,410 @@
+// Package medialib implements a persistent index of local media files.
+// Files are parsed using libVLC and their metadata, duration and track
+// information are stored in a BoltDB database, which can be queried by
+// artist, album, genre or text.
+package medialib
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	entriesBucket = []byte("entries")
+	indexBucket   = []byte("index")
+)
+
+// Indexed entry fields, whose values can be retrieved using Library.Values.
+const (
+	FieldArtist = "artist"
+	FieldAlbum  = "album"
+	FieldGenre  = "genre"
+)
+
+// Track contains information about a track of an indexed media file.
+type Track struct {
+	ID          int     `json:"id"`
+	Type        string  `json:"type"`
+	Codec       string  `json:"codec,omitempty"`
+	Language    string  `json:"language,omitempty"`
+	Description string  `json:"description,omitempty"`
+	BitRate     uint    `json:"bit_rate,omitempty"`
+	Channels    uint    `json:"channels,omitempty"`
+	Rate        uint    `json:"rate,omitempty"`
+	Width       uint    `json:"width,omitempty"`
+	Height      uint    `json:"height,omitempty"`
+	FrameRate   float64 `json:"frame_rate,omitempty"`
+}
+
+// Entry contains the information stored for an indexed media file.
+type Entry struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+
+	Title       string        `json:"title,omitempty"`
+	Artist      string        `json:"artist,omitempty"`
+	AlbumArtist string        `json:"album_artist,omitempty"`
+	Album       string        `json:"album,omitempty"`
+	Genre       string        `json:"genre,omitempty"`
+	Date        string        `json:"date,omitempty"`
+	TrackNumber string        `json:"track_number,omitempty"`
+	Duration    time.Duration `json:"duration,omitempty"`
+	Tracks      []Track       `json:"tracks,omitempty"`
+
+	IndexedAt time.Time `json:"indexed_at"`
+}
+
+// DisplayTitle returns the title of the entry, falling back to the name of
+// the file.
+func (e *Entry) DisplayTitle() string {
+	if e.Title != "" {
+		return e.Title
+	}
+
+	return filepath.Base(e.Path)
+}
+
+// Query contains the conditions used to search the library. Empty fields
+// match all entries. Artist, Album and Genre must match exactly (case
+// insensitive). Text must be contained by the title, artist, album artist,
+// album, genre or path of the entries (case insensitive).
+type Query struct {
+	Artist string
+	Album  string
+	Genre  string
+	Text   string
+}
+
+func (q Query) match(e *Entry) bool {
+	if q.Artist != "" && !strings.EqualFold(q.Artist, e.Artist) && !strings.EqualFold(q.Artist, e.AlbumArtist) {
+		return false
+	}
+	if q.Album != "" && !strings.EqualFold(q.Album, e.Album) {
+		return false
+	}
+	if q.Genre != "" && !strings.EqualFold(q.Genre, e.Genre) {
+		return false
+	}
+	if q.Text != "" {
+		text := strings.ToLower(q.Text)
+		for _, value := range []string{e.Title, e.Artist, e.AlbumArtist, e.Album, e.Genre, e.Path} {
+			if strings.Contains(strings.ToLower(value), text) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+// Library is a persistent index of media files.
+type Library struct {
+	db *bolt.DB
+}
+
+// DefaultPath returns the default location of the library database.
+func DefaultPath() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(cacheDir, "libvlc-go-examples", "library.db"), nil
+}
+
+// Open opens the library database at the specified path, creating it if
+// it does not exist. The database can only be opened by one process at a
+// time, so Open fails if it is not released within a second.
+func Open(path string) (*Library, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(entriesBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(indexBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Library{db: db}, nil
+}
+
+// Close closes the library database.
+func (l *Library) Close() error {
+	return l.db.Close()
+}
+
+// Get returns the entry of the file at the specified path. Returns nil if
+// the file is not indexed.
+func (l *Library) Get(path string) (*Entry, error) {
+	var entry *Entry
+	err := l.db.View(func(tx *bolt.Tx) error {
+		var err error
+		entry, err = getEntry(tx, path)
+		return err
+	})
+
+	return entry, err
+}
+
+// Put adds the specified entry to the library, replacing the existing
+// entry of the same file, if any.
+func (l *Library) Put(entry *Entry) error {
+	if entry.Path == "" {
+		return errors.New("entry path must be specified")
+	}
+
+	return l.db.Update(func(tx *bolt.Tx) error {
+		if err := deleteEntry(tx, entry.Path); err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		if err := tx.Bucket(entriesBucket).Put([]byte(entry.Path), data); err != nil {
+			return err
+		}
+
+		index := tx.Bucket(indexBucket)
+		for _, key := range indexKeys(entry) {
+			if err := index.Put(key, nil); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Delete removes the entry of the file at the specified path.
+func (l *Library) Delete(path string) error {
+	return l.db.Update(func(tx *bolt.Tx) error {
+		return deleteEntry(tx, path)
+	})
+}
+
+// Count returns the number of indexed files.
+func (l *Library) Count() (int, error) {
+	var count int
+	err := l.db.View(func(tx *bolt.Tx) error {
+		count = tx.Bucket(entriesBucket).Stats().KeyN
+		return nil
+	})
+
+	return count, err
+}
+
+// Paths returns the paths of the indexed files located in the specified
+// directory or in its subdirectories. Relative directories are resolved
+// against the working directory, as files are indexed by absolute path.
+func (l *Library) Paths(dir string) ([]string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// The root directory already ends with a separator.
+	if !strings.HasSuffix(dir, string(filepath.Separator)) {
+		dir += string(filepath.Separator)
+	}
+	prefix := []byte(dir)
+
+	var paths []string
+	err = l.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(entriesBucket).Cursor()
+		for k, _ := c.Seek(prefix); k != nil && hasPrefix(k, prefix); k, _ = c.Next() {
+			paths = append(paths, string(k))
+		}
+		return nil
+	})
+
+	return paths, err
+}
+
+// Search returns the entries matching the specified query, sorted by
+// artist, album, track number and title.
+func (l *Library) Search(q Query) ([]*Entry, error) {
+	var entries []*Entry
+	err := l.db.View(func(tx *bolt.Tx) error {
+		// Use an index in order to find candidate entries, if possible.
+		var field, value string
+		switch {
+		case q.Artist != "":
+			field, value = FieldArtist, q.Artist
+		case q.Album != "":
+			field, value = FieldAlbum, q.Album
+		case q.Genre != "":
+			field, value = FieldGenre, q.Genre
+		}
+
+		if field == "" {
+			return tx.Bucket(entriesBucket).ForEach(func(k, v []byte) error {
+				entry, err := decodeEntry(v)
+				if err != nil {
+					return err
+				}
+				if q.match(entry) {
+					entries = append(entries, entry)
+				}
+				return nil
+			})
+		}
+
+		prefix := indexPrefix(field, value)
+		c := tx.Bucket(indexBucket).Cursor()
+		for k, _ := c.Seek(prefix); k != nil && hasPrefix(k, prefix); k, _ = c.Next() {
+			entry, err := getEntry(tx, string(k[len(prefix):]))
+			if err != nil {
+				return err
+			}
+			if entry != nil && q.match(entry) {
+				entries = append(entries, entry)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortEntries(entries)
+	return entries, nil
+}
+
+// Values returns the distinct values of the specified field (artist, album
+// or genre), sorted alphabetically. The values are lowercase.
+func (l *Library) Values(field string) ([]string, error) {
+	switch field {
+	case FieldArtist, FieldAlbum, FieldGenre:
+	default:
+		return nil, errors.New("unknown field " + strconv.Quote(field))
+	}
+
+	var values []string
+	err := l.db.View(func(tx *bolt.Tx) error {
+		prefix := []byte(field + "\x00")
+		c := tx.Bucket(indexBucket).Cursor()
+		for k, _ := c.Seek(prefix); k != nil && hasPrefix(k, prefix); k, _ = c.Next() {
+			value := strings.SplitN(string(k[len(prefix):]), "\x00", 2)[0]
+			if len(values) == 0 || values[len(values)-1] != value {
+				values = append(values, value)
+			}
+		}
+		return nil
+	})
+
+	return values, err
+}
+
+func getEntry(tx *bolt.Tx, path string) (*Entry, error) {
+	data := tx.Bucket(entriesBucket).Get([]byte(path))
+	if data == nil {
+		return nil, nil
+	}
+
+	return decodeEntry(data)
+}
+
+func deleteEntry(tx *bolt.Tx, path string) error {
+	entry, err := getEntry(tx, path)
+	if err != nil || entry == nil {
+		return err
+	}
+
+	index := tx.Bucket(indexBucket)
+	for _, key := range indexKeys(entry) {
+		if err := index.Delete(key); err != nil {
+			return err
+		}
+	}
+
+	return tx.Bucket(entriesBucket).Delete([]byte(path))
+}
+
+func decodeEntry(data []byte) (*Entry, error) {
+	entry := &Entry{}
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// indexKeys returns the index keys of the specified entry. The keys have
+// the following format: field \x00 lowercase value \x00 path.
+func indexKeys(entry *Entry) [][]byte {
+	values := map[string][]string{
+		FieldArtist: {entry.Artist, entry.AlbumArtist},
+		FieldAlbum:  {entry.Album},
+		FieldGenre:  {entry.Genre},
+	}
+
+	var keys [][]byte
+	for field, fieldValues := range values {
+		seen := map[string]bool{}
+		for _, value := range fieldValues {
+			if value = strings.ToLower(value); value == "" || seen[value] {
+				continue
+			}
+			seen[value] = true
+
+			keys = append(keys, append(indexPrefix(field, value), entry.Path...))
+		}
+	}
+
+	return keys
+}
+
+func indexPrefix(field, value string) []byte {
+	return []byte(field + "\x00" + strings.ToLower(value) + "\x00")
+}
+
+func hasPrefix(key, prefix []byte) bool {
+	return len(key) >= len(prefix) && string(key[:len(prefix)]) == string(prefix)
+}
+
+func sortEntries(entries []*Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if !strings.EqualFold(a.Artist, b.Artist) {
+			return strings.ToLower(a.Artist) < strings.ToLower(b.Artist)
+		}
+		if !strings.EqualFold(a.Album, b.Album) {
+			return strings.ToLower(a.Album) < strings.ToLower(b.Album)
+		}
+
+		an, _ := strconv.Atoi(a.TrackNumber)
+		bn, _ := strconv.Atoi(b.TrackNumber)
+		if an != bn {
+			return an < bn
+		}
+		if a.DisplayTitle() != b.DisplayTitle() {
+			return a.DisplayTitle() < b.DisplayTitle()
+		}
+
+		return a.Path < b.Path
+	})
+}
@@ -0,0 +1,126 @@
+package medialib
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func openTestLibrary(t *testing.T) *Library {
+	t.Helper()
+
+	lib, err := Open(filepath.Join(t.TempDir(), "library.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { lib.Close() })
+
+	return lib
+}
+
+func putEntries(t *testing.T, lib *Library, entries ...*Entry) {
+	t.Helper()
+
+	for _, entry := range entries {
+		if err := lib.Put(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func entryPaths(entries []*Entry) string {
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		paths = append(paths, entry.Path)
+	}
+
+	return strings.Join(paths, ",")
+}
+
+func TestLibrarySearch(t *testing.T) {
+	lib := openTestLibrary(t)
+	putEntries(t, lib,
+		&Entry{Path: "/music/b2.mp3", Title: "Second", Artist: "Band", Album: "Debut", Genre: "Rock", TrackNumber: "2"},
+		&Entry{Path: "/music/b1.mp3", Title: "First", Artist: "Band", Album: "Debut", Genre: "Rock", TrackNumber: "1"},
+		&Entry{Path: "/music/s1.mp3", Title: "Solo", Artist: "Singer", AlbumArtist: "Band", Album: "Live", Genre: "Jazz"},
+		&Entry{Path: "/videos/clip.mp4"},
+	)
+
+	tests := []struct {
+		query    Query
+		expected string
+	}{
+		{Query{}, "/videos/clip.mp4,/music/b1.mp3,/music/b2.mp3,/music/s1.mp3"},
+		{Query{Artist: "band"}, "/music/b1.mp3,/music/b2.mp3,/music/s1.mp3"},
+		{Query{Artist: "Band", Album: "live"}, "/music/s1.mp3"},
+		{Query{Genre: "ROCK"}, "/music/b1.mp3,/music/b2.mp3"},
+		{Query{Text: "sec"}, "/music/b2.mp3"},
+		{Query{Text: "videos"}, "/videos/clip.mp4"},
+		{Query{Genre: "Pop"}, ""},
+	}
+	for _, test := range tests {
+		entries, err := lib.Search(test.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if paths := entryPaths(entries); paths != test.expected {
+			t.Errorf("query %+v: expected %q, got %q", test.query, test.expected, paths)
+		}
+	}
+}
+
+func TestLibraryUpdate(t *testing.T) {
+	lib := openTestLibrary(t)
+	putEntries(t, lib, &Entry{Path: "/music/a.mp3", Artist: "Old", Genre: "Rock"})
+
+	// Replacing an entry must update the indexes.
+	putEntries(t, lib, &Entry{Path: "/music/a.mp3", Artist: "New", Genre: "Rock"})
+
+	if entries, _ := lib.Search(Query{Artist: "Old"}); len(entries) != 0 {
+		t.Errorf("expected no entries for the old artist, got %q", entryPaths(entries))
+	}
+	if entries, _ := lib.Search(Query{Artist: "New"}); len(entries) != 1 {
+		t.Errorf("expected 1 entry for the new artist, got %d", len(entries))
+	}
+
+	artists, err := lib.Values(FieldArtist)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(artists, ",") != "new" {
+		t.Errorf("expected artists [new], got %q", artists)
+	}
+
+	// Deleting an entry must remove it from the indexes.
+	if err := lib.Delete("/music/a.mp3"); err != nil {
+		t.Fatal(err)
+	}
+	if entry, _ := lib.Get("/music/a.mp3"); entry != nil {
+		t.Error("expected deleted entry not to be found")
+	}
+	if genres, _ := lib.Values(FieldGenre); len(genres) != 0 {
+		t.Errorf("expected no genres, got %q", genres)
+	}
+	if count, _ := lib.Count(); count != 0 {
+		t.Errorf("expected empty library, got %d entries", count)
+	}
+}
+
+func TestLibraryPaths(t *testing.T) {
+	lib := openTestLibrary(t)
+	putEntries(t, lib,
+		&Entry{Path: filepath.FromSlash("/music/a.mp3")},
+		&Entry{Path: filepath.FromSlash("/music/sub/b.mp3")},
+		&Entry{Path: filepath.FromSlash("/musicals/c.mp3")},
+	)
+
+	paths, err := lib.Paths(filepath.FromSlash("/music"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := filepath.FromSlash("/music/a.mp3") + "," + filepath.FromSlash("/music/sub/b.mp3")
+	if strings.Join(paths, ",") != expected {
+		t.Errorf("expected %q, got %q", expected, paths)
+	}
+}
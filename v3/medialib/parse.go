@@ -0,0 +1,89 @@
+package medialib
+
+import (
+	"fmt"
+	"time"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+
+	"github.com/adrg/libvlc-go-examples/v3/vlcutil"
+)
+
+// trackTypeNames contains the names of the media track types.
+var trackTypeNames = map[vlc.MediaTrackType]string{
+	vlc.MediaTrackUnknown: "unknown",
+	vlc.MediaTrackAudio:   "audio",
+	vlc.MediaTrackVideo:   "video",
+	vlc.MediaTrackText:    "subtitle",
+}
+
+// ParseFile parses the media file at the specified path using libVLC and
+// returns its library entry. Parsing fails if it does not finish within the
+// specified timeout. libVLC must be initialized before calling ParseFile.
+// The size and modification time of the returned entry are not set.
+func ParseFile(path string, timeout time.Duration) (*Entry, error) {
+	media, err := vlc.NewMediaFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+	defer media.Release()
+
+	// Parse media and wait for the parsing to finish.
+	if err := vlcutil.ParseMedia(media, timeout, vlc.MediaParseLocal, vlc.MediaFetchLocal); err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", path, err)
+	}
+
+	// Retrieve metadata.
+	entry := &Entry{Path: path}
+	for key, value := range map[vlc.MediaMetaKey]*string{
+		vlc.MediaTitle:       &entry.Title,
+		vlc.MediaArtist:      &entry.Artist,
+		vlc.MediaAlbumArtist: &entry.AlbumArtist,
+		vlc.MediaAlbum:       &entry.Album,
+		vlc.MediaGenre:       &entry.Genre,
+		vlc.MediaDate:        &entry.Date,
+		vlc.MediaTrackNumber: &entry.TrackNumber,
+	} {
+		*value, _ = media.Meta(key)
+	}
+	if duration, err := media.Duration(); err == nil && duration > 0 {
+		entry.Duration = duration
+	}
+
+	// Retrieve track information.
+	tracks, err := media.Tracks()
+	if err != nil {
+		return nil, err
+	}
+	for _, track := range tracks {
+		entry.Tracks = append(entry.Tracks, newTrack(track))
+	}
+
+	return entry, nil
+}
+
+func newTrack(track *vlc.MediaTrack) Track {
+	t := Track{
+		ID:          track.ID,
+		Type:        trackTypeNames[track.Type],
+		Language:    track.Language,
+		Description: track.Description,
+		BitRate:     track.BitRate,
+	}
+	if t.Type == "" {
+		t.Type = trackTypeNames[vlc.MediaTrackUnknown]
+	}
+	t.Codec, _ = track.CodecDescription()
+
+	switch {
+	case track.Audio != nil:
+		t.Channels, t.Rate = track.Audio.Channels, track.Audio.Rate
+	case track.Video != nil:
+		t.Width, t.Height = track.Video.Width, track.Video.Height
+		if track.Video.FrameRateDen > 0 {
+			t.FrameRate = float64(track.Video.FrameRateNum) / float64(track.Video.FrameRateDen)
+		}
+	}
+
+	return t
+}
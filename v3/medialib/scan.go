@@ -0,0 +1,237 @@
+package medialib
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+)
+
+// mediaExtensions contains the extensions of the indexed files.
+var mediaExtensions = map[string]bool{
+	".aac": true, ".flac": true, ".m4a": true, ".mka": true, ".mp3": true,
+	".oga": true, ".ogg": true, ".opus": true, ".wav": true, ".wma": true,
+	".avi": true, ".m4v": true, ".mkv": true, ".mov": true, ".mp4": true,
+	".mpeg": true, ".mpg": true, ".ogv": true, ".ts": true, ".webm": true,
+	".wmv": true,
+}
+
+// ScanStats contains the results of a library scan.
+type ScanStats struct {
+	Added     int
+	Updated   int
+	Unchanged int
+	Removed   int
+	Failed    int
+}
+
+// Scanner indexes the media files of a set of directories. Only new files
+// and files whose size or modification time changed since they were last
+// indexed are parsed.
+type Scanner struct {
+	// Library in which the files are indexed.
+	Library *Library
+
+	// Maximum duration allowed for parsing a file. Default: 5 seconds.
+	ParseTimeout time.Duration
+
+	// Function used to parse media files. Default: ParseFile.
+	Parse func(path string, timeout time.Duration) (*Entry, error)
+
+	// Optional function called after each file is processed. The error is
+	// not nil if the file could not be indexed.
+	Progress func(path string, err error)
+}
+
+// Scan indexes the media files located in the specified directories and
+// in their subdirectories. The entries of files which no longer exist are
+// removed from the library. Scanning stops when the context is canceled.
+func (s *Scanner) Scan(ctx context.Context, dirs ...string) (*ScanStats, error) {
+	parse, timeout := s.Parse, s.ParseTimeout
+	if parse == nil {
+		parse = ParseFile
+	}
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	stats := &ScanStats{}
+	for _, dir := range dirs {
+		// Index files by absolute path, so that entries do not depend on
+		// the working directory of the scan.
+		dir, err := filepath.Abs(dir)
+		if err != nil {
+			return stats, err
+		}
+		seen := map[string]bool{}
+
+		err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err != nil {
+				// Skip unreadable directories.
+				if d != nil && d.IsDir() && path != dir {
+					return fs.SkipDir
+				}
+				return err
+			}
+			if d.IsDir() || !mediaExtensions[strings.ToLower(filepath.Ext(path))] {
+				return nil
+			}
+			seen[path] = true
+
+			info, err := d.Info()
+			if err != nil {
+				s.progress(path, err)
+				stats.Failed++
+				return nil
+			}
+
+			// Skip unchanged files.
+			current, err := s.Library.Get(path)
+			if err != nil {
+				return err
+			}
+			if current != nil && current.Size == info.Size() && current.ModTime.Equal(info.ModTime()) {
+				stats.Unchanged++
+				return nil
+			}
+
+			// Parse and index file.
+			entry, err := parse(path, timeout)
+			if err != nil {
+				s.progress(path, err)
+				stats.Failed++
+				return nil
+			}
+			entry.Path = path
+			entry.Size = info.Size()
+			entry.ModTime = info.ModTime()
+			entry.IndexedAt = time.Now()
+
+			if err := s.Library.Put(entry); err != nil {
+				return err
+			}
+			if current == nil {
+				stats.Added++
+			} else {
+				stats.Updated++
+			}
+
+			s.progress(path, nil)
+			return nil
+		})
+		if err != nil {
+			return stats, err
+		}
+
+		// Remove the entries of deleted files.
+		paths, err := s.Library.Paths(dir)
+		if err != nil {
+			return stats, err
+		}
+		for _, path := range paths {
+			if seen[path] {
+				continue
+			}
+			if err := s.Library.Delete(path); err != nil {
+				return stats, err
+			}
+			stats.Removed++
+		}
+	}
+
+	return stats, nil
+}
+
+func (s *Scanner) progress(path string, err error) {
+	if s.Progress != nil {
+		s.Progress(path, err)
+	}
+}
+
+// LocalDirs returns the local media directories (e.g. ~/Music, ~/Videos),
+// as reported by the local directory media discovery services of libVLC.
+// The services are given the specified duration to report the directories.
+// libVLC must be initialized before calling LocalDirs.
+func LocalDirs(wait time.Duration) ([]string, error) {
+	descriptors, err := vlc.ListMediaDiscoverers(vlc.MediaDiscoveryLocal)
+	if err != nil {
+		return nil, err
+	}
+	if len(descriptors) == 0 {
+		return nil, errors.New("could not find any local directory discovery service")
+	}
+
+	var (
+		mu   sync.Mutex
+		dirs []string
+		seen = map[string]bool{}
+	)
+
+	var services []*vlc.MediaDiscoverer
+	defer func() {
+		for _, service := range services {
+			service.Release()
+		}
+	}()
+
+	for _, descriptor := range descriptors {
+		service, err := vlc.NewMediaDiscoverer(descriptor.Name)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, service)
+
+		err = service.Start(func(event vlc.Event, media *vlc.Media, index int) {
+			if event != vlc.MediaListItemAdded {
+				return
+			}
+
+			location, err := media.Location()
+			if err != nil {
+				return
+			}
+			path, ok := fileURIPath(location)
+			if !ok {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if !seen[path] {
+				seen[path] = true
+				dirs = append(dirs, path)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	time.Sleep(wait)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(dirs) == 0 {
+		return nil, errors.New("no local media directories found")
+	}
+
+	return append([]string(nil), dirs...), nil
+}
+
+// fileURIPath returns the local path of the specified file URI.
+func fileURIPath(location string) (string, bool) {
+	u, err := url.Parse(location)
+	if err != nil || u.Scheme != "file" || u.Path == "" {
+		return "", false
+	}
+
+	return filepath.FromSlash(u.Path), true
+}
@@ -0,0 +1,160 @@
+package medialib
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScannerIncremental(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "a.mp3"), "a")
+	writeTestFile(t, filepath.Join(dir, "albums", "b.flac"), "b")
+	writeTestFile(t, filepath.Join(dir, "notes.txt"), "not media")
+
+	var parsed []string
+	scanner := &Scanner{
+		Library: openTestLibrary(t),
+		Parse: func(path string, timeout time.Duration) (*Entry, error) {
+			parsed = append(parsed, filepath.Base(path))
+			return &Entry{Title: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))}, nil
+		},
+	}
+
+	scan := func() *ScanStats {
+		t.Helper()
+
+		parsed = nil
+		stats, err := scanner.Scan(context.Background(), dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return stats
+	}
+
+	// Initial scan.
+	if stats := scan(); stats.Added != 2 || len(parsed) != 2 {
+		t.Fatalf("expected 2 added files, got %+v (parsed %q)", stats, parsed)
+	}
+	if entry, _ := scanner.Library.Get(filepath.Join(dir, "a.mp3")); entry == nil || entry.Title != "a" || entry.Size != 1 {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+
+	// Unchanged files are not parsed again.
+	if stats := scan(); stats.Unchanged != 2 || len(parsed) != 0 {
+		t.Fatalf("expected 2 unchanged files, got %+v (parsed %q)", stats, parsed)
+	}
+
+	// Modified files are parsed again and deleted files are removed.
+	modTime := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "a.mp3"), modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "albums", "b.flac")); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(dir, "c.mkv"), "c")
+
+	stats := scan()
+	if stats.Added != 1 || stats.Updated != 1 || stats.Removed != 1 || stats.Unchanged != 0 {
+		t.Fatalf("unexpected scan stats: %+v", stats)
+	}
+	if strings.Join(parsed, ",") != "a.mp3,c.mkv" {
+		t.Errorf("expected a.mp3 and c.mkv to be parsed, got %q", parsed)
+	}
+	if count, _ := scanner.Library.Count(); count != 2 {
+		t.Errorf("expected 2 indexed files, got %d", count)
+	}
+}
+
+func TestScannerRelativeDir(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "a.mp3"), "a")
+	writeTestFile(t, filepath.Join(dir, "b.mp3"), "b")
+
+	// Scan the directory using a relative path.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	scanner := &Scanner{
+		Library: openTestLibrary(t),
+		Parse: func(path string, timeout time.Duration) (*Entry, error) {
+			return &Entry{}, nil
+		},
+	}
+	if _, err := scanner.Scan(context.Background(), "."); err != nil {
+		t.Fatal(err)
+	}
+
+	// Files are indexed by absolute path.
+	absDir, err := filepath.Abs(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry, _ := scanner.Library.Get(filepath.Join(absDir, "a.mp3")); entry == nil {
+		t.Fatal("expected a.mp3 to be indexed by absolute path")
+	}
+
+	// Deleted files are removed when scanning the relative directory.
+	if err := os.Remove("b.mp3"); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := scanner.Scan(context.Background(), ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Removed != 1 || stats.Unchanged != 1 {
+		t.Fatalf("unexpected scan stats: %+v", stats)
+	}
+	if paths, _ := scanner.Library.Paths("."); len(paths) != 1 || filepath.Base(paths[0]) != "a.mp3" {
+		t.Errorf("expected only a.mp3 to be indexed, got %q", paths)
+	}
+}
+
+func TestScannerCanceled(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "a.mp3"), "a")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	scanner := &Scanner{
+		Library: openTestLibrary(t),
+		Parse: func(path string, timeout time.Duration) (*Entry, error) {
+			t.Errorf("unexpected parse of %s", path)
+			return &Entry{}, nil
+		},
+	}
+	if _, err := scanner.Scan(ctx, dir); err == nil {
+		t.Fatal("expected scan to fail when the context is canceled")
+	}
+}
+
+func TestFileURIPath(t *testing.T) {
+	if path, ok := fileURIPath("file:///home/user/Music%20Files"); !ok || path != filepath.FromSlash("/home/user/Music Files") {
+		t.Errorf("unexpected path %q", path)
+	}
+	if _, ok := fileURIPath("upnp://server/music"); ok {
+		t.Error("expected non-file URIs to be rejected")
+	}
+}
@@ -0,0 +1,146 @@
+package mediatree
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+)
+
+// Categories contains the media discovery categories, along with the names
+// used to select them.
+var Categories = []struct {
+	Category vlc.MediaDiscoveryCategory
+	Name     string
+}{
+	{vlc.MediaDiscoveryDevices, "devices"},
+	{vlc.MediaDiscoveryLAN, "lan"},
+	{vlc.MediaDiscoveryInternet, "internet"},
+	{vlc.MediaDiscoveryLocal, "local"},
+}
+
+// CategoryNames returns the names of the media discovery categories,
+// separated by commas.
+func CategoryNames() string {
+	names := make([]string, 0, len(Categories))
+	for _, c := range Categories {
+		names = append(names, c.Name)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// ListServices writes the media discovery services of each category to w.
+// If a category name is specified, only the services of that category are
+// written.
+func ListServices(w io.Writer, categoryName string) error {
+	found := categoryName == ""
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, c := range Categories {
+		if categoryName != "" && !strings.EqualFold(categoryName, c.Name) {
+			continue
+		}
+		found = true
+
+		descriptors, err := vlc.ListMediaDiscoverers(c.Category)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(tw, "%s:\n", c.Name)
+		for _, descriptor := range descriptors {
+			fmt.Fprintf(tw, "  %s\t%s\n", descriptor.Name, descriptor.LongName)
+		}
+		if len(descriptors) == 0 {
+			fmt.Fprintln(tw, "  -")
+		}
+	}
+	if !found {
+		return fmt.Errorf("unknown category %q. Available categories: %s", categoryName, CategoryNames())
+	}
+
+	return tw.Flush()
+}
+
+// Discover starts the specified media discovery service and returns the
+// top level items found until the context is done. The returned release
+// function must be called after the items are no longer used.
+func Discover(ctx context.Context, serviceName string) ([]Item, func(), error) {
+	service, err := vlc.NewMediaDiscoverer(serviceName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot create %s discovery service: %w", serviceName, err)
+	}
+
+	// Collect the discovered items. The media instances reported by the
+	// service are released by libVLC when they are deleted, so copies of
+	// them are stored instead. The copies are kept at the same indices as
+	// the media they were created from, in order to handle deletions.
+	var (
+		mu     sync.Mutex
+		medias []*vlc.Media
+		done   bool
+	)
+	onEvent := func(event vlc.Event, media *vlc.Media, index int) {
+		mu.Lock()
+		defer mu.Unlock()
+		if done || index < 0 {
+			return
+		}
+
+		switch event {
+		case vlc.MediaListItemAdded:
+			if index > len(medias) {
+				index = len(medias)
+			}
+
+			// Keep a nil placeholder if the media cannot be copied, so
+			// that the indices of the following items remain valid.
+			dup, err := media.Duplicate()
+			if err != nil {
+				dup = nil
+			}
+			medias = append(medias[:index], append([]*vlc.Media{dup}, medias[index:]...)...)
+		case vlc.MediaListItemDeleted:
+			if index < len(medias) {
+				if medias[index] != nil {
+					medias[index].Release()
+				}
+				medias = append(medias[:index], medias[index+1:]...)
+			}
+		}
+	}
+
+	// Start media discovery service and wait for the context to be done.
+	if err := service.Start(onEvent); err != nil {
+		service.Release()
+		return nil, nil, err
+	}
+	<-ctx.Done()
+
+	// Stop collecting items. The event callback does not access the
+	// collected media afterwards, so the lock is no longer needed.
+	mu.Lock()
+	done = true
+	mu.Unlock()
+
+	items := make([]Item, 0, len(medias))
+	for _, media := range medias {
+		if media != nil {
+			items = append(items, &vlcItem{media: media})
+		}
+	}
+
+	return items, func() {
+		service.Release()
+		for _, media := range medias {
+			if media != nil {
+				media.Release()
+			}
+		}
+	}, nil
+}
@@ -0,0 +1,169 @@
+//go:build integration
+
+package mediatree
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+
+	"github.com/adrg/libvlc-go-examples/shared/fixtures"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Main(m))
+}
+
+// fixtureDir returns a directory containing copies of the audio and video
+// fixtures.
+func fixtureDir(t *testing.T) (string, []string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	var names []string
+	for _, path := range []string{fixtures.SineWAV(t), fixtures.ColorBars(t)} {
+		name := filepath.Base(path)
+		if err := copyFile(filepath.Join(dir, name), path); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+	}
+
+	return dir, names
+}
+
+func copyFile(dst, src string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+// findLocations checks that the specified trees contain nodes having
+// locations ending with each of the specified file names.
+func findLocations(t *testing.T, nodes []*Node, names []string) {
+	t.Helper()
+
+	var locations []string
+	var walk func(nodes []*Node)
+	walk = func(nodes []*Node) {
+		for _, node := range nodes {
+			locations = append(locations, node.Location)
+			walk(node.Children)
+		}
+	}
+	walk(nodes)
+
+	for _, name := range names {
+		found := false
+		for _, location := range locations {
+			if strings.HasSuffix(location, "/"+name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("%s not found in tree locations %q", name, locations)
+		}
+	}
+}
+
+func TestBuildDirectory(t *testing.T) {
+	dir, names := fixtureDir(t)
+
+	media, err := vlc.NewMediaFromPath(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer media.Release()
+
+	nodes := Build([]Item{&vlcItem{media: media}}, Options{
+		MaxDepth:     1,
+		ParseTimeout: 10 * time.Second,
+	})
+	if len(nodes) != 1 {
+		t.Fatalf("got %d nodes, want 1", len(nodes))
+	}
+	if nodes[0].Error != "" {
+		t.Fatalf("cannot expand directory: %s", nodes[0].Error)
+	}
+	if len(nodes[0].Children) != len(names) {
+		t.Errorf("got %d children, want %d", len(nodes[0].Children), len(names))
+	}
+	findLocations(t, nodes, names)
+}
+
+func TestDiscoverLocal(t *testing.T) {
+	dir, names := fixtureDir(t)
+
+	// Point the videos directory used by the local discovery service to
+	// the fixture directory.
+	configDir := t.TempDir()
+	userDirs := fmt.Sprintf("XDG_VIDEOS_DIR=%q\n", dir)
+	if err := os.WriteFile(filepath.Join(configDir, "user-dirs.dirs"), []byte(userDirs), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+
+	descriptors, err := vlc.ListMediaDiscoverers(vlc.MediaDiscoveryLocal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, descriptor := range descriptors {
+		found = found || descriptor.Name == "video_dir"
+	}
+	if !found {
+		t.Skip("video_dir discovery service not available")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	items, release, err := Discover(ctx, "video_dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	nodes := Build(items, Options{
+		MaxDepth:     2,
+		ParseTimeout: 10 * time.Second,
+	})
+	findLocations(t, nodes, names)
+}
+
+func TestDiscoverCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	_, release, err := Discover(ctx, "video_dir")
+	if err != nil {
+		t.Skipf("video_dir discovery service not available: %s", err)
+	}
+	defer release()
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Discover returned after %s, want immediate return", elapsed)
+	}
+}
@@ -0,0 +1,130 @@
+package mediatree
+
+import (
+	"errors"
+	"time"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+
+	"github.com/adrg/libvlc-go-examples/v3/vlcutil"
+)
+
+// mediaTypeNames contains the names of the media types.
+var mediaTypeNames = map[vlc.MediaType]string{
+	vlc.MediaTypeUnknown:   "unknown",
+	vlc.MediaTypeFile:      "file",
+	vlc.MediaTypeDirectory: "directory",
+	vlc.MediaTypeDisc:      "disc",
+	vlc.MediaTypeStream:    "stream",
+	vlc.MediaTypePlaylist:  "playlist",
+}
+
+// metaKeys contains the media metadata included in the output.
+var metaKeys = []struct {
+	Key  vlc.MediaMetaKey
+	Name string
+}{
+	{vlc.MediaArtist, "artist"},
+	{vlc.MediaAlbum, "album"},
+	{vlc.MediaGenre, "genre"},
+	{vlc.MediaDate, "date"},
+	{vlc.MediaDescription, "description"},
+	{vlc.MediaArtworkURL, "artwork_url"},
+}
+
+// vlcItem is a media item backed by a libVLC media instance.
+type vlcItem struct {
+	media *vlc.Media
+}
+
+func (i *vlcItem) Info() (Info, error) {
+	location, err := i.media.Location()
+	if err != nil {
+		return Info{}, err
+	}
+	mediaType, err := i.media.Type()
+	if err != nil {
+		return Info{}, err
+	}
+
+	info := Info{
+		Location: location,
+		Type:     mediaTypeNames[mediaType],
+		// Directories and playlists contain sub-items. Items of unknown
+		// type (e.g. UPnP and SMB folders) must be parsed in order to
+		// find out if they contain sub-items.
+		Expandable: mediaType == vlc.MediaTypeDirectory ||
+			mediaType == vlc.MediaTypePlaylist ||
+			mediaType == vlc.MediaTypeUnknown,
+	}
+	if info.Type == "" {
+		info.Type = mediaTypeNames[vlc.MediaTypeUnknown]
+	}
+
+	info.Title, _ = i.media.Meta(vlc.MediaTitle)
+	if duration, err := i.media.Duration(); err == nil && duration > 0 {
+		info.Duration = duration
+	}
+
+	for _, metaKey := range metaKeys {
+		if value, _ := i.media.Meta(metaKey.Key); value != "" {
+			if info.Meta == nil {
+				info.Meta = map[string]string{}
+			}
+			info.Meta[metaKey.Name] = value
+		}
+	}
+
+	return info, nil
+}
+
+func (i *vlcItem) SubItems(timeout time.Duration) ([]Item, error) {
+	if err := parseMedia(i.media, timeout); err != nil {
+		return nil, err
+	}
+
+	subItems, err := i.media.SubItems()
+	if err != nil {
+		return nil, err
+	}
+	if subItems == nil {
+		return nil, nil
+	}
+
+	// The sub-items list is also referenced by the media, which keeps the
+	// listed items alive, so the reference obtained here can be released.
+	defer subItems.Release()
+
+	return ListItems(subItems)
+}
+
+// parseMedia parses the specified media, including network resources, and
+// waits for the parsing to finish. Parsing is abandoned after the specified
+// timeout.
+func parseMedia(media *vlc.Media, timeout time.Duration) error {
+	err := vlcutil.ParseMedia(media, timeout, vlc.MediaParseLocal, vlc.MediaParseNetwork)
+	if errors.Is(err, vlcutil.ErrParseTimeout) {
+		return errors.New("not expanded: parse timeout exceeded")
+	}
+
+	return err
+}
+
+// ListItems returns the items of the specified media list.
+func ListItems(list *vlc.MediaList) ([]Item, error) {
+	count, err := list.Count()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Item, 0, count)
+	for i := 0; i < count; i++ {
+		media, err := list.MediaAtIndex(uint(i))
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, &vlcItem{media: media})
+	}
+
+	return items, nil
+}
@@ -0,0 +1,167 @@
+// Package mediatree builds and prints trees of the media found by the
+// libVLC media discovery services (e.g. UPnP and SMB shares), expanding the
+// sub-items of folders and playlists recursively.
+package mediatree
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Info contains the information displayed for a media item.
+type Info struct {
+	Title    string            `json:"title,omitempty"`
+	Location string            `json:"location"`
+	Type     string            `json:"type"`
+	Duration time.Duration     `json:"-"`
+	Meta     map[string]string `json:"meta,omitempty"`
+
+	// Expandable reports whether the item can contain sub-items
+	// (e.g. directories, playlists, UPnP and SMB folders).
+	Expandable bool `json:"-"`
+}
+
+// Item represents an item of a media list, which can contain
+// sub-items. It allows traversing media trees without depending on libVLC.
+type Item interface {
+	// Info returns the information of the item.
+	Info() (Info, error)
+
+	// SubItems returns the sub-items of the item. The item is parsed in
+	// order to retrieve its sub-items, if needed. Parsing is abandoned
+	// after the specified timeout.
+	SubItems(timeout time.Duration) ([]Item, error)
+}
+
+// Node is a node of a media tree.
+type Node struct {
+	Info
+	DurationMS int64   `json:"duration_ms,omitempty"`
+	Error      string  `json:"error,omitempty"`
+	Children   []*Node `json:"children,omitempty"`
+}
+
+// Options contains the settings used to build media trees.
+type Options struct {
+	// Maximum depth of the tree. Items found at this depth are not
+	// expanded. A negative value means unlimited depth.
+	MaxDepth int
+
+	// Maximum duration allowed for parsing an item.
+	ParseTimeout time.Duration
+
+	// Time after which no more items are expanded. A zero value means
+	// no deadline.
+	Deadline time.Time
+}
+
+// Build returns the media trees rooted in the specified items.
+func Build(items []Item, opts Options) []*Node {
+	return buildNodes(items, 0, opts)
+}
+
+func buildNodes(items []Item, depth int, opts Options) []*Node {
+	nodes := make([]*Node, 0, len(items))
+	for _, item := range items {
+		nodes = append(nodes, buildNode(item, depth, opts))
+	}
+
+	return nodes
+}
+
+func buildNode(item Item, depth int, opts Options) *Node {
+	info, err := item.Info()
+	node := &Node{
+		Info:       info,
+		DurationMS: info.Duration.Milliseconds(),
+	}
+	if err != nil {
+		node.Error = err.Error()
+		return node
+	}
+
+	// Check expansion limits.
+	if !info.Expandable || (opts.MaxDepth >= 0 && depth >= opts.MaxDepth) {
+		return node
+	}
+
+	timeout := opts.ParseTimeout
+	if !opts.Deadline.IsZero() {
+		remaining := time.Until(opts.Deadline)
+		if remaining <= 0 {
+			node.Error = "not expanded: timeout exceeded"
+			return node
+		}
+		if timeout <= 0 || remaining < timeout {
+			timeout = remaining
+		}
+	}
+
+	// Expand item.
+	subItems, err := item.SubItems(timeout)
+	if err != nil {
+		node.Error = err.Error()
+		return node
+	}
+	node.Children = buildNodes(subItems, depth+1, opts)
+
+	return node
+}
+
+// Print writes a textual representation of the specified media trees
+// to w.
+func Print(w io.Writer, nodes []*Node) {
+	for _, node := range nodes {
+		fmt.Fprintln(w, node.label())
+		printChildren(w, node.Children, "")
+	}
+}
+
+func printChildren(w io.Writer, nodes []*Node, prefix string) {
+	for i, node := range nodes {
+		branch, indent := "├── ", "│   "
+		if i == len(nodes)-1 {
+			branch, indent = "└── ", "    "
+		}
+
+		fmt.Fprintf(w, "%s%s%s\n", prefix, branch, node.label())
+		printChildren(w, node.Children, prefix+indent)
+	}
+}
+
+// label returns the text describing the node in a media tree
+// (e.g. Title [file, 3m20s] <file:///music/title.mp3>).
+func (n *Node) label() string {
+	var sb strings.Builder
+
+	title := n.Title
+	if title == "" {
+		title = n.Location
+	}
+	sb.WriteString(title)
+
+	details := []string{n.Type}
+	if n.Duration > 0 {
+		details = append(details, n.Duration.Round(time.Second).String())
+	}
+	fmt.Fprintf(&sb, " [%s]", strings.Join(details, ", "))
+
+	if n.Title != "" && n.Location != "" {
+		fmt.Fprintf(&sb, " <%s>", n.Location)
+	}
+	if n.Error != "" {
+		fmt.Fprintf(&sb, " (%s)", n.Error)
+	}
+
+	return sb.String()
+}
+
+// WriteJSON writes the specified media trees to w, in JSON format.
+func WriteJSON(w io.Writer, nodes []*Node) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(nodes)
+}
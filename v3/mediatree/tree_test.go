@@ -0,0 +1,168 @@
+package mediatree
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeItem is a media item which does not depend on libVLC.
+type fakeItem struct {
+	info     Info
+	children []*fakeItem
+	err      error
+	expanded bool
+}
+
+func (i *fakeItem) Info() (Info, error) {
+	return i.info, nil
+}
+
+func (i *fakeItem) SubItems(timeout time.Duration) ([]Item, error) {
+	i.expanded = true
+	if i.err != nil {
+		return nil, i.err
+	}
+
+	items := make([]Item, 0, len(i.children))
+	for _, child := range i.children {
+		items = append(items, child)
+	}
+	return items, nil
+}
+
+func newFakeFolder(title string, children ...*fakeItem) *fakeItem {
+	return &fakeItem{
+		info: Info{
+			Title:      title,
+			Location:   "upnp://server/" + title,
+			Type:       "directory",
+			Expandable: true,
+		},
+		children: children,
+	}
+}
+
+func newFakeFile(title string, duration time.Duration) *fakeItem {
+	return &fakeItem{
+		info: Info{
+			Title:    title,
+			Location: "upnp://server/" + title + ".mp3",
+			Type:     "file",
+			Duration: duration,
+			Meta:     map[string]string{"artist": "Artist"},
+		},
+	}
+}
+
+func newFakeLibrary() (*fakeItem, *fakeItem) {
+	deep := newFakeFolder("Deep", newFakeFile("Hidden", time.Minute))
+	root := newFakeFolder("Music",
+		newFakeFolder("Albums", newFakeFile("Song", 200*time.Second), deep),
+		newFakeFile("Single", 90*time.Second),
+	)
+
+	return root, deep
+}
+
+func TestBuildTreeDepth(t *testing.T) {
+	root, deep := newFakeLibrary()
+
+	nodes := Build([]Item{root}, Options{MaxDepth: 2})
+	if len(nodes) != 1 || len(nodes[0].Children) != 2 {
+		t.Fatalf("unexpected tree: %+v", nodes)
+	}
+
+	albums := nodes[0].Children[0]
+	if len(albums.Children) != 2 {
+		t.Fatalf("expected 2 children for Albums, got %d", len(albums.Children))
+	}
+	if deep.expanded || len(albums.Children[1].Children) != 0 {
+		t.Error("expected items at the maximum depth not to be expanded")
+	}
+
+	// Unlimited depth.
+	root, deep = newFakeLibrary()
+	Build([]Item{root}, Options{MaxDepth: -1})
+	if !deep.expanded {
+		t.Error("expected all items to be expanded when depth is unlimited")
+	}
+}
+
+func TestBuildTreeErrors(t *testing.T) {
+	broken := newFakeFolder("Broken")
+	broken.err = errors.New("parsing failed")
+
+	nodes := Build([]Item{broken}, Options{MaxDepth: -1})
+	if nodes[0].Error != "parsing failed" {
+		t.Errorf("expected expansion error to be recorded, got %q", nodes[0].Error)
+	}
+
+	// Items are not expanded after the deadline.
+	root, _ := newFakeLibrary()
+	nodes = Build([]Item{root}, Options{
+		MaxDepth: -1,
+		Deadline: time.Now().Add(-time.Second),
+	})
+	if root.expanded || nodes[0].Error == "" {
+		t.Error("expected items not to be expanded after the deadline")
+	}
+}
+
+func TestPrintTree(t *testing.T) {
+	root, _ := newFakeLibrary()
+	nodes := Build([]Item{root}, Options{MaxDepth: 2})
+
+	var buf bytes.Buffer
+	Print(&buf, nodes)
+
+	expected := strings.Join([]string{
+		"Music [directory] <upnp://server/Music>",
+		"├── Albums [directory] <upnp://server/Albums>",
+		"│   ├── Song [file, 3m20s] <upnp://server/Song.mp3>",
+		"│   └── Deep [directory] <upnp://server/Deep>",
+		"└── Single [file, 1m30s] <upnp://server/Single.mp3>",
+		"",
+	}, "\n")
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	root, _ := newFakeLibrary()
+	nodes := Build([]Item{root}, Options{MaxDepth: 1})
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, nodes); err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded []struct {
+		Title    string `json:"title"`
+		Location string `json:"location"`
+		Children []struct {
+			Title      string            `json:"title"`
+			DurationMS int64             `json:"duration_ms"`
+			Meta       map[string]string `json:"meta"`
+			Children   []interface{}     `json:"children"`
+		} `json:"children"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded) != 1 || decoded[0].Location != "upnp://server/Music" || len(decoded[0].Children) != 2 {
+		t.Fatalf("unexpected JSON output:\n%s", buf.String())
+	}
+	single := decoded[0].Children[1]
+	if single.Title != "Single" || single.DurationMS != 90000 || single.Meta["artist"] != "Artist" {
+		t.Errorf("unexpected item: %+v", single)
+	}
+	if len(decoded[0].Children[0].Children) != 0 {
+		t.Error("expected items at the maximum depth not to have children")
+	}
+}
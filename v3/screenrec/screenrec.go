@@ -0,0 +1,147 @@
+package main
+
+/*
+ * Headless screen recorder.
+ * libVLC screen module must be installed.
+ * See https://github.com/adrg/libvlc-go/wiki for installation instructions.
+ * See https://wiki.videolan.org/Documentation:Modules/screen.
+ *
+ * Usage:
+ *   screenrec -o out.mp4 [-region 640x480+10+20 | -monitor <index|name|all>]
+ *             [-fps 30] [-follow-mouse] [-profile h264] [-duration 1m30s]
+ *   screenrec -list-monitors
+ *
+ * The recording is stopped when the specified duration elapses or when the
+ * process receives SIGINT (Ctrl+C) or SIGTERM.
+ */
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+
+	"github.com/adrg/libvlc-go-examples/shared/screencap"
+	"github.com/adrg/libvlc-go-examples/v3/vlcutil"
+)
+
+func profileNames() string {
+	return strings.Join(vlcutil.RecordProfileNames(), ", ")
+}
+
+func main() {
+	var (
+		output      = flag.String("o", "", "output file path (required)")
+		region      = flag.String("region", "", "captured region as WIDTHxHEIGHT+X+Y (default: entire screen)")
+		monitorName = flag.String("monitor", "", "capture the monitor with the specified index or name, or all monitors (all)")
+		listMons    = flag.Bool("list-monitors", false, "list monitors and exit")
+		fps         = flag.Float64("fps", 30, "capture frame rate")
+		followMouse = flag.Bool("follow-mouse", false, "captured region follows the mouse cursor")
+		profileName = flag.String("profile", vlcutil.DefaultRecordProfile, "output profile: "+profileNames())
+		duration    = flag.Duration("duration", 0, "recording duration (default: until interrupted)")
+		verbose     = flag.Bool("v", false, "enable libVLC logging")
+	)
+	flag.Parse()
+
+	// List monitors.
+	if *listMons {
+		monitors, err := screencap.ListMonitors()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, m := range monitors {
+			fmt.Println(m)
+		}
+		return
+	}
+
+	if *output == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	prof, ok := vlcutil.RecordProfiles[*profileName]
+	if !ok {
+		log.Fatalf("Unknown profile %q. Available profiles: %s\n", *profileName, profileNames())
+	}
+	if filepath.Ext(*output) == "" {
+		*output += "." + prof.Extension
+	}
+
+	// Create screen media options.
+	screenOpts := vlc.MediaScreenOptions{
+		FPS:         *fps,
+		FollowMouse: *followMouse,
+	}
+	switch {
+	case *region != "" && *monitorName != "":
+		log.Fatal("The -region and -monitor flags cannot be used together")
+	case *region != "":
+		area, err := screencap.ParseRegion(*region)
+		if err != nil {
+			log.Fatal(err)
+		}
+		screenOpts.X, screenOpts.Y = area.X, area.Y
+		screenOpts.Width, screenOpts.Height = area.Width, area.Height
+	case *monitorName != "":
+		monitors, err := screencap.ListMonitors()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		m, err := screencap.FindMonitor(monitors, *monitorName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Capturing monitor %s\n", m)
+
+		screenOpts.X, screenOpts.Y = m.X, m.Y
+		screenOpts.Width, screenOpts.Height = m.Width, m.Height
+	}
+
+	// Initialize libVLC.
+	var args []string
+	if !*verbose {
+		args = append(args, "--quiet")
+	}
+	if err := vlc.Init(args...); err != nil {
+		log.Fatal(err)
+	}
+	defer vlc.Release()
+
+	// Stop the recording on SIGINT and SIGTERM, or when the specified
+	// duration elapses.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if *duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *duration)
+		defer cancel()
+	}
+
+	// Start recording.
+	start := time.Now()
+	log.Printf("Recording to %s. Press Ctrl+C to stop.\n", *output)
+
+	err := vlcutil.RecordScreen(ctx, vlcutil.RecordOptions{
+		Output:  *output,
+		Screen:  screenOpts,
+		Profile: *profileName,
+	})
+	if err != nil {
+		log.Fatalf("Recording failed: %s\n", err)
+	}
+
+	info, err := os.Stat(*output)
+	if err != nil {
+		log.Fatalf("Recording failed: %s\n", err)
+	}
+	log.Printf("Saved %s (%d bytes, %s).\n", *output, info.Size(), time.Since(start).Truncate(time.Second))
+}
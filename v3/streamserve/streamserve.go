@@ -0,0 +1,223 @@
+package main
+
+/*
+ * Serve media files or the computer screen as live streams on the local
+ * network, using HTTP (MPEG-TS or WebM), HLS or RTSP.
+ * libVLC screen module must be installed in order to stream the screen.
+ * See https://github.com/adrg/libvlc-go/wiki for installation instructions.
+ * See https://wiki.videolan.org/Documentation:Streaming_HowTo_New.
+ *
+ * Usage:
+ *   streamserve [-protocol http-ts] [-port 8080] [-loop] <media path>
+ *   streamserve -screen [-region 640x480+10+20] [-fps 30] [-protocol hls]
+ */
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+
+	"github.com/adrg/libvlc-go-examples/shared/screencap"
+	"github.com/adrg/libvlc-go-examples/v3/vlcutil"
+)
+
+func protocolNames() string {
+	names := make([]string, 0, len(vlcutil.StreamProtocols))
+	for _, protocol := range vlcutil.StreamProtocols {
+		names = append(names, string(protocol.Protocol))
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func main() {
+	var (
+		protocol = flag.String("protocol", string(vlcutil.StreamHTTPTS), "streaming protocol: "+protocolNames())
+		port     = flag.Int("port", 8080, "port on which the stream is served")
+		screen   = flag.Bool("screen", false, "stream the screen instead of a media file")
+		region   = flag.String("region", "", "captured screen region as WIDTHxHEIGHT+X+Y (default: entire screen)")
+		fps      = flag.Float64("fps", 30, "screen capture frame rate")
+		loop     = flag.Bool("loop", false, "loop the media file")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <media path>\n       %s -screen [flags]\n\nFlags:\n", os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *screen == (flag.NArg() == 0) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	opts := &serveOptions{
+		stream:    &vlcutil.StreamOptions{Protocol: vlcutil.StreamProtocol(*protocol), Port: *port},
+		mediaPath: flag.Arg(0),
+		screen:    *screen,
+		region:    *region,
+		fps:       *fps,
+		loop:      *loop,
+	}
+	if err := run(opts); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// serveOptions contains the settings provided through command line flags.
+type serveOptions struct {
+	stream    *vlcutil.StreamOptions
+	mediaPath string
+	screen    bool
+	region    string
+	fps       float64
+	loop      bool
+}
+
+// run streams the configured media until it ends or the process is
+// interrupted. The returned error is reported by the caller, after all the
+// allocated resources have been released.
+func run(opts *serveOptions) error {
+	stream := opts.stream
+
+	// Initialize libVLC. Additional command line arguments can be passed in
+	// to libVLC by specifying them in the Init function.
+	if err := vlc.Init("--quiet"); err != nil {
+		return err
+	}
+	defer vlc.Release()
+
+	// Create a new player.
+	player, err := vlc.NewPlayer()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		player.Stop()
+		player.Release()
+	}()
+
+	// Create media from the screen or from the specified path.
+	var media *vlc.Media
+	if opts.screen {
+		screenOpts := &vlc.MediaScreenOptions{FPS: opts.fps}
+		if opts.region != "" {
+			area, err := screencap.ParseRegion(opts.region)
+			if err != nil {
+				return err
+			}
+			screenOpts.X, screenOpts.Y = area.X, area.Y
+			screenOpts.Width, screenOpts.Height = area.Width, area.Height
+		}
+
+		media, err = vlc.NewMediaFromScreen(screenOpts)
+	} else {
+		media, err = vlc.NewMediaFromPath(opts.mediaPath)
+	}
+	if err != nil {
+		return err
+	}
+	defer media.Release()
+
+	if opts.loop && !opts.screen {
+		if err := media.AddOptions(":input-repeat=65535"); err != nil {
+			return err
+		}
+	}
+
+	// Serve HLS segments over HTTP, as libVLC only writes them to disk.
+	if stream.Protocol == vlcutil.StreamHLS {
+		if stream.HLSDir, err = os.MkdirTemp("", "libvlc-go-hls-"); err != nil {
+			return err
+		}
+		defer os.RemoveAll(stream.HLSDir)
+
+		server, err := stream.ServeHLS()
+		if err != nil {
+			return err
+		}
+		defer server.Close()
+	}
+
+	// Configure media to be served using the selected protocol. Screen
+	// captures contain no audio.
+	chain, err := stream.Chain(!opts.screen)
+	if err != nil {
+		return err
+	}
+	if err := media.AddOptions(chain.MediaOption()); err != nil {
+		return err
+	}
+
+	fmt.Println("Streaming at:")
+	for _, url := range stream.URLs() {
+		fmt.Println("  " + url)
+	}
+	fmt.Println("Press Ctrl+C to stop.")
+
+	// Stream until the media ends or the process is interrupted.
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	return streamMedia(player, media, sigCh)
+}
+
+// streamMedia plays the specified media, which is expected to contain a
+// stream output chain option, using the provided player. Returns when the
+// media ends, when streaming fails or when a signal is received on the
+// stop channel.
+func streamMedia(player *vlc.Player, media *vlc.Media, stop <-chan os.Signal) error {
+	// Set player media.
+	if err := player.SetMedia(media); err != nil {
+		return err
+	}
+
+	// Retrieve player event manager.
+	manager, err := player.EventManager()
+	if err != nil {
+		return err
+	}
+
+	// Register the media end reached and error events with the event manager.
+	errCh := make(chan error, 1)
+	eventCallback := func(event vlc.Event, userData interface{}) {
+		var err error
+		if event == vlc.MediaPlayerEncounteredError {
+			err = errors.New("streaming failed")
+		}
+
+		// Do not block the libVLC event thread.
+		select {
+		case errCh <- err:
+		default:
+		}
+	}
+
+	var eventIDs []vlc.EventID
+	for _, event := range []vlc.Event{vlc.MediaPlayerEndReached, vlc.MediaPlayerEncounteredError} {
+		eventID, err := manager.Attach(event, eventCallback, nil)
+		if err != nil {
+			return err
+		}
+		eventIDs = append(eventIDs, eventID)
+	}
+	defer manager.Detach(eventIDs...)
+
+	// Start streaming.
+	if err = player.Play(); err != nil {
+		return err
+	}
+
+	select {
+	case <-stop:
+		return nil
+	case err := <-errCh:
+		return err
+	}
+}
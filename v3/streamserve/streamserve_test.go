@@ -0,0 +1,258 @@
+//go:build integration
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+
+	"github.com/adrg/libvlc-go-examples/shared/fixtures"
+	"github.com/adrg/libvlc-go-examples/v3/vlcutil"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Main(m))
+}
+
+func TestStreamMediaHLS(t *testing.T) {
+	stream := &vlcutil.StreamOptions{Protocol: vlcutil.StreamHLS, Port: freePort(t), HLSDir: t.TempDir()}
+	chain, err := stream.Chain(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	media, err := vlc.NewMediaFromPath(fixtures.MultiTrack(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer media.Release()
+
+	if err := media.AddOptions(chain.MediaOption()); err != nil {
+		t.Fatal(err)
+	}
+
+	player, err := vlc.NewPlayer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		player.Stop()
+		player.Release()
+	}()
+
+	err = fixtures.RunWithTimeout(t, fixtures.PlaybackTimeout, func() error {
+		return streamMedia(player, media, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the index and the segments written to the HLS directory.
+	index, err := os.Open(filepath.Join(stream.HLSDir, vlcutil.HLSIndexName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer index.Close()
+
+	var header string
+	var segments []string
+	scanner := bufio.NewScanner(index)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case header == "":
+			header = line
+		case line != "" && !strings.HasPrefix(line, "#"):
+			segments = append(segments, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if header != "#EXTM3U" {
+		t.Errorf("got index header %q, want #EXTM3U", header)
+	}
+	if len(segments) == 0 {
+		t.Fatal("index contains no segments")
+	}
+	for _, segment := range segments {
+		info, err := os.Stat(filepath.Join(stream.HLSDir, segment))
+		if err != nil {
+			t.Errorf("segment %s: %v", segment, err)
+			continue
+		}
+		if info.Size() == 0 {
+			t.Errorf("segment %s is empty", segment)
+		}
+	}
+}
+
+func TestStreamMediaPlayback(t *testing.T) {
+	for _, protocol := range []vlcutil.StreamProtocol{vlcutil.StreamHTTPTS, vlcutil.StreamHLS} {
+		t.Run(string(protocol), func(t *testing.T) {
+			stream := &vlcutil.StreamOptions{Protocol: protocol, Port: freePort(t)}
+			if protocol == vlcutil.StreamHLS {
+				stream.HLSDir = t.TempDir()
+
+				server, err := stream.ServeHLS()
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer server.Close()
+			}
+
+			chain, err := stream.Chain(true)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			media, err := vlc.NewMediaFromPath(fixtures.MultiTrack(t))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer media.Release()
+
+			// Loop the media, so that the stream outlasts the playback.
+			if err := media.AddOptions(chain.MediaOption(), ":input-repeat=65535"); err != nil {
+				t.Fatal(err)
+			}
+
+			player, err := vlc.NewPlayer()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				player.Stop()
+				player.Release()
+			}()
+
+			// Start streaming.
+			var streamErr error
+			stop, streamDone := make(chan os.Signal, 1), make(chan struct{})
+			go func() {
+				streamErr = streamMedia(player, media, stop)
+				close(streamDone)
+			}()
+			defer func() {
+				stop <- os.Interrupt
+				<-streamDone
+				if streamErr != nil {
+					t.Error(streamErr)
+				}
+			}()
+
+			// Play the stream back using a second player. The stream may not
+			// be available right away (e.g. HLS segments are written only
+			// after the first segment is complete), so playback is retried.
+			url := fmt.Sprintf("http://127.0.0.1:%d/%s", stream.Port, vlcutil.StreamName+".ts")
+			if protocol == vlcutil.StreamHLS {
+				url = fmt.Sprintf("http://127.0.0.1:%d/%s", stream.Port, vlcutil.HLSIndexName)
+			}
+
+			err = fixtures.RunWithTimeout(t, fixtures.PlaybackTimeout, func() error {
+				for {
+					err := playStream(url)
+					if err == nil {
+						return nil
+					}
+
+					select {
+					case <-streamDone:
+						return fmt.Errorf("streaming stopped: %v", streamErr)
+					case <-time.After(500 * time.Millisecond):
+					}
+				}
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// playStream plays the stream at the specified URL using a new player.
+// Returns when the playback time of the stream advances or when playback
+// fails.
+func playStream(url string) error {
+	player, err := vlc.NewPlayer()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		player.Stop()
+		player.Release()
+	}()
+
+	media, err := player.LoadMediaFromURL(url)
+	if err != nil {
+		return err
+	}
+	defer media.Release()
+
+	manager, err := player.EventManager()
+	if err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	eventCallback := func(event vlc.Event, userData interface{}) {
+		var err error
+		switch event {
+		case vlc.MediaPlayerEncounteredError:
+			err = errors.New("cannot play stream")
+		case vlc.MediaPlayerEndReached:
+			err = errors.New("stream ended before playback started")
+		}
+
+		// Do not block the libVLC event thread.
+		select {
+		case done <- err:
+		default:
+		}
+	}
+
+	events := []vlc.Event{
+		vlc.MediaPlayerTimeChanged,
+		vlc.MediaPlayerEndReached,
+		vlc.MediaPlayerEncounteredError,
+	}
+
+	var eventIDs []vlc.EventID
+	for _, event := range events {
+		eventID, err := manager.Attach(event, eventCallback, nil)
+		if err != nil {
+			return err
+		}
+		eventIDs = append(eventIDs, eventID)
+	}
+	defer manager.Detach(eventIDs...)
+
+	if err := player.Play(); err != nil {
+		return err
+	}
+
+	return <-done
+}
+
+// freePort returns a TCP port which is not in use.
+func freePort(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
@@ -0,0 +1,52 @@
+package vlcutil
+
+import (
+	"fmt"
+	"strings"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+)
+
+// PresetIndex returns the index of the equalizer preset with the specified
+// name (e.g. Full bass). The name is matched case-insensitively.
+func PresetIndex(name string) (uint, bool) {
+	for i, presetName := range vlc.EqualizerPresetNames() {
+		if strings.EqualFold(presetName, name) {
+			return uint(i), true
+		}
+	}
+
+	return 0, false
+}
+
+// NewPresetEqualizer creates a new equalizer from the preset with the
+// specified name. The returned equalizer must be released when it is no
+// longer needed.
+func NewPresetEqualizer(name string) (*vlc.Equalizer, error) {
+	idx, ok := PresetIndex(name)
+	if !ok {
+		return nil, fmt.Errorf("equalizer preset %q not found. Available presets: %s",
+			name, strings.Join(vlc.EqualizerPresetNames(), ", "))
+	}
+
+	return vlc.NewEqualizerFromPreset(idx)
+}
+
+// ApplyPreset sets the equalizer of the player to the preset with the
+// specified name. An empty name disables the equalizer of the player.
+// The preset can be applied both before and during playback.
+func ApplyPreset(player *vlc.Player, name string) error {
+	if name == "" {
+		return player.SetEqualizer(nil)
+	}
+
+	equalizer, err := NewPresetEqualizer(name)
+	if err != nil {
+		return err
+	}
+
+	// The player copies the equalizer settings, so the equalizer can be
+	// released as soon as it is set.
+	defer equalizer.Release()
+	return player.SetEqualizer(equalizer)
+}
@@ -0,0 +1,29 @@
+//go:build integration
+
+package vlcutil
+
+import (
+	"testing"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+)
+
+func TestApplyPreset(t *testing.T) {
+	player, err := vlc.NewPlayer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer player.Release()
+
+	for _, name := range vlc.EqualizerPresetNames() {
+		if err := ApplyPreset(player, name); err != nil {
+			t.Errorf("cannot apply preset %q: %v", name, err)
+		}
+	}
+	if err := ApplyPreset(player, ""); err != nil {
+		t.Errorf("cannot disable equalizer: %v", err)
+	}
+	if err := ApplyPreset(player, "Unknown"); err == nil {
+		t.Error("expected error for unknown preset")
+	}
+}
@@ -0,0 +1,85 @@
+// Package vlcutil contains reusable building blocks extracted from the
+// libvlc-go v3 examples: media parsing, renderer discovery, equalizer presets
+// and screen recording. libVLC must be initialized before calling any of the
+// functions of the package.
+package vlcutil
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+)
+
+// ErrParseTimeout is returned by ParseMedia if parsing does not finish
+// within the specified timeout.
+var ErrParseTimeout = errors.New("parse timeout exceeded")
+
+// ParseMedia parses the specified media using the provided parse options and
+// waits for the parsing to finish. If no options are specified, only local
+// resources are parsed. If timeout is not positive, ParseMedia waits until
+// parsing finishes, however long it takes. Media which has already been
+// parsed is not parsed again.
+func ParseMedia(media *vlc.Media, timeout time.Duration, opts ...vlc.MediaParseOption) error {
+	if parsed, _ := media.IsParsed(); parsed {
+		return nil
+	}
+	if len(opts) == 0 {
+		opts = []vlc.MediaParseOption{vlc.MediaParseLocal}
+	}
+
+	// Retrieve media event manager.
+	manager, err := media.EventManager()
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{}, 1)
+	eventCallback := func(event vlc.Event, userData interface{}) {
+		// Do not block the libVLC event thread.
+		select {
+		case done <- struct{}{}:
+		default:
+		}
+	}
+
+	eventID, err := manager.Attach(vlc.MediaParsedChanged, eventCallback, nil)
+	if err != nil {
+		return err
+	}
+	defer manager.Detach(eventID)
+
+	// Parse media asynchronously. libVLC reports a parse timeout status once
+	// the timeout elapses, so the parsing is abandoned only if no status is
+	// reported shortly after that.
+	var expired <-chan time.Time
+	if timeout > 0 {
+		expired = time.After(timeout + time.Second)
+	}
+	if err := media.ParseWithOptions(int(timeout.Milliseconds()), opts...); err != nil {
+		return err
+	}
+
+	select {
+	case <-done:
+	case <-expired:
+		media.StopParse()
+		return ErrParseTimeout
+	}
+
+	status, err := media.ParseStatus()
+	if err != nil {
+		return err
+	}
+	switch status {
+	case vlc.MediaParseDone:
+		return nil
+	case vlc.MediaParseTimeout:
+		return ErrParseTimeout
+	case vlc.MediaParseFailed:
+		return errors.New("media parsing failed")
+	default:
+		return fmt.Errorf("media parsing failed with status %d", status)
+	}
+}
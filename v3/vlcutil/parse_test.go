@@ -0,0 +1,64 @@
+//go:build integration
+
+package vlcutil
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+
+	"github.com/adrg/libvlc-go-examples/shared/fixtures"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(fixtures.Main(m))
+}
+
+func TestParseMedia(t *testing.T) {
+	media, err := vlc.NewMediaFromPath(fixtures.MultiTrack(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer media.Release()
+
+	if err := ParseMedia(media, 10*time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := media.ParseStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != vlc.MediaParseDone {
+		t.Errorf("got parse status %d, want %d", status, vlc.MediaParseDone)
+	}
+
+	tracks, err := media.Tracks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tracks) == 0 {
+		t.Error("parsed media has no tracks")
+	}
+
+	// Parsed media is not parsed again.
+	if err := ParseMedia(media, time.Nanosecond); err != nil {
+		t.Errorf("got error %v for parsed media", err)
+	}
+}
+
+func TestParseMediaMissingFile(t *testing.T) {
+	media, err := vlc.NewMediaFromPath(t.TempDir() + "/missing.mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer media.Release()
+
+	err = ParseMedia(media, 10*time.Second)
+	if err == nil || errors.Is(err, ErrParseTimeout) {
+		t.Errorf("got error %v, want parse failure", err)
+	}
+}
@@ -0,0 +1,120 @@
+package vlcutil
+
+import (
+	"context"
+	"errors"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+)
+
+// ErrPlayback is returned by PlayMedia if the player encounters an error
+// during playback.
+var ErrPlayback = errors.New("playback failed")
+
+// PlayMedia plays the media of the specified player and returns when the
+// playback ends or when the context is done. Playback is not stopped when
+// the context is done, so the caller can decide what to do with the player.
+// The context being done is not considered an error.
+func PlayMedia(ctx context.Context, player *vlc.Player) error {
+	// Retrieve player event manager.
+	manager, err := player.EventManager()
+	if err != nil {
+		return err
+	}
+
+	return play(ctx, manager, player.Play, vlc.MediaPlayerEndReached, vlc.MediaPlayerEncounteredError)
+}
+
+// PlayMediaList plays the media list of the specified list player and
+// returns when all the media files in the list have been played or when
+// the context is done. The context being done is not considered an error.
+func PlayMediaList(ctx context.Context, player *vlc.ListPlayer) error {
+	// Retrieve player event manager.
+	manager, err := player.EventManager()
+	if err != nil {
+		return err
+	}
+
+	return play(ctx, manager, player.Play, vlc.MediaListPlayerPlayed)
+}
+
+// play starts the playback and waits for the end event, the optional error
+// event or for the context to be done.
+func play(ctx context.Context, manager *vlc.EventManager, start func() error,
+	endEvent vlc.Event, errEvent ...vlc.Event) error {
+	done := make(chan error, 1)
+	eventCallback := func(event vlc.Event, userData interface{}) {
+		var err error
+		if event != endEvent {
+			err = ErrPlayback
+		}
+
+		// Do not block the libVLC event thread.
+		select {
+		case done <- err:
+		default:
+		}
+	}
+
+	var eventIDs []vlc.EventID
+	for _, event := range append([]vlc.Event{endEvent}, errEvent...) {
+		eventID, err := manager.Attach(event, eventCallback, nil)
+		if err != nil {
+			manager.Detach(eventIDs...)
+			return err
+		}
+		eventIDs = append(eventIDs, eventID)
+	}
+	defer manager.Detach(eventIDs...)
+
+	// Start playing the media.
+	if err := start(); err != nil {
+		return err
+	}
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return nil
+	}
+}
+
+// ClearRenderer switches the output of the specified player back to local
+// playback. libVLC players cannot be detached from a renderer, as
+// vlc.Player.SetRenderer does not accept nil, so a new player is created
+// instead. The media of the old player, if any, is set on the new player,
+// and the optional setup function is called in order to restore the rest of
+// its configuration (e.g. video window, equalizer, event handlers). The old
+// player is stopped and released only if the new player is set up
+// successfully, so it can still be used if an error is returned.
+func ClearRenderer(player *vlc.Player, setup func(*vlc.Player) error) (*vlc.Player, error) {
+	media, err := player.Media()
+	if err != nil {
+		return nil, err
+	}
+
+	newPlayer, err := vlc.NewPlayer()
+	if err != nil {
+		return nil, err
+	}
+	if media != nil {
+		if err := newPlayer.SetMedia(media); err != nil {
+			newPlayer.Release()
+			return nil, err
+		}
+	}
+	if setup != nil {
+		if err := setup(newPlayer); err != nil {
+			newPlayer.Release()
+			return nil, err
+		}
+	}
+
+	// The media is retained by the new player, so releasing the old one
+	// does not release it.
+	player.Stop()
+	player.Release()
+
+	return newPlayer, nil
+}
@@ -0,0 +1,189 @@
+//go:build integration
+
+package vlcutil
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+
+	"github.com/adrg/libvlc-go-examples/shared/fixtures"
+)
+
+func TestPlayMedia(t *testing.T) {
+	for _, fixture := range []struct {
+		name string
+		path func(testing.TB) string
+	}{
+		{"audio", fixtures.SineWAV},
+		{"video", fixtures.ColorBars},
+		{"multitrack", fixtures.MultiTrack},
+	} {
+		t.Run(fixture.name, func(t *testing.T) {
+			player, err := vlc.NewPlayer()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				player.Stop()
+				player.Release()
+			}()
+
+			media, err := player.LoadMediaFromPath(fixture.path(t))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer media.Release()
+
+			err = fixtures.RunWithTimeout(t, fixtures.PlaybackTimeout, func() error {
+				return PlayMedia(context.Background(), player)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			state, err := player.MediaState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if state != vlc.MediaEnded {
+				t.Errorf("got media state %v, want %v", state, vlc.MediaEnded)
+			}
+		})
+	}
+}
+
+func TestPlayMediaList(t *testing.T) {
+	player, err := vlc.NewListPlayer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		player.Stop()
+		player.Release()
+	}()
+
+	list, err := vlc.NewMediaList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer list.Release()
+
+	paths := []string{fixtures.SineWAV(t), fixtures.ColorBars(t)}
+	if err := list.AddMediaFromPath(paths[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := player.SetMediaList(list); err != nil {
+		t.Fatal(err)
+	}
+
+	// Media added after the list is set must be played as well.
+	if err := list.AddMediaFromPath(paths[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	// Record the played media.
+	manager, err := player.EventManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu      sync.Mutex
+		nextSet int
+	)
+	eventID, err := manager.Attach(vlc.MediaListPlayerNextItemSet, func(vlc.Event, interface{}) {
+		mu.Lock()
+		nextSet++
+		mu.Unlock()
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Detach(eventID)
+
+	err = fixtures.RunWithTimeout(t, 2*fixtures.PlaybackTimeout, func() error {
+		return PlayMediaList(context.Background(), player)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if nextSet != len(paths) {
+		t.Errorf("got %d next item set events, want %d", nextSet, len(paths))
+	}
+}
+
+func TestClearRenderer(t *testing.T) {
+	player, err := vlc.NewPlayer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		player.Stop()
+		player.Release()
+	}()
+
+	path := fixtures.SineWAV(t)
+	media, err := player.LoadMediaFromPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer media.Release()
+
+	// Switch to a renderer, if one is available on the local network.
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if renderer, err := DiscoverRenderer(ctx, RendererFilter{}); err == nil {
+		defer renderer.Release()
+
+		if err := player.SetRenderer(renderer.Renderer); err != nil {
+			t.Fatal(err)
+		}
+	} else {
+		t.Logf("Switching from local playback: %s", err)
+	}
+
+	// Switch back to local playback.
+	var setupPlayer *vlc.Player
+	newPlayer, err := ClearRenderer(player, func(p *vlc.Player) error {
+		setupPlayer = p
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	player = newPlayer
+
+	if setupPlayer != newPlayer {
+		t.Error("setup function not called with the new player")
+	}
+
+	playerMedia, err := player.Media()
+	if err != nil || playerMedia == nil {
+		t.Fatalf("got media %v (%v), want loaded media", playerMedia, err)
+	}
+	location, err := playerMedia.Location()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(location, filepath.Base(path)) {
+		t.Errorf("got media location %q, want %q", location, path)
+	}
+
+	// The media must be played locally by the new player.
+	err = fixtures.RunWithTimeout(t, fixtures.PlaybackTimeout, func() error {
+		return PlayMedia(context.Background(), player)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,222 @@
+package vlcutil
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adrg/libvlc-go-examples/shared/screencap"
+)
+
+// OutputProfile contains the settings used to encode and store recordings.
+type OutputProfile struct {
+	// Name of the profile, displayed to users.
+	Name string `json:"name"`
+
+	// Video codec (e.g. h264, VP80, MJPG).
+	VideoCodec string `json:"vcodec"`
+
+	// Video encoder module and options (e.g. `x264{qp=0}`). Optional.
+	VideoEncoder string `json:"venc,omitempty"`
+
+	// Video bitrate in kb/s. Default: 0 (encoder default).
+	Bitrate int `json:"bitrate,omitempty"`
+
+	// Video scale factor. Default: 0 (no scaling).
+	Scale float64 `json:"scale,omitempty"`
+
+	// Output frame rate. Default: 0 (capture frame rate).
+	FPS float64 `json:"fps,omitempty"`
+
+	// Default audio codec used when capturing audio (e.g. mp4a, vorb).
+	AudioCodec string `json:"acodec,omitempty"`
+
+	// Container format (e.g. mp4, webm, avi, mkv).
+	Mux string `json:"mux"`
+
+	// Output file extension, without the leading dot.
+	Extension string `json:"extension"`
+}
+
+// RecordProfiles contains the predefined output profiles, by short name.
+var RecordProfiles = map[string]*OutputProfile{
+	"h264": {
+		Name:       "H.264 / MP4",
+		VideoCodec: "h264",
+		AudioCodec: "mp4a",
+		Mux:        "mp4",
+		Extension:  "mp4",
+	},
+	"vp8": {
+		Name:       "VP8 / WebM",
+		VideoCodec: "VP80",
+		Bitrate:    2000,
+		AudioCodec: "vorb",
+		Mux:        "webm",
+		Extension:  "webm",
+	},
+	"mjpeg": {
+		Name:       "MJPEG / AVI",
+		VideoCodec: "MJPG",
+		Bitrate:    8000,
+		AudioCodec: "mpga",
+		Mux:        "avi",
+		Extension:  "avi",
+	},
+	"lossless": {
+		Name:         "Lossless H.264 / MKV",
+		VideoCodec:   "h264",
+		VideoEncoder: "x264{qp=0,preset=ultrafast}",
+		AudioCodec:   "flac",
+		Mux:          "mkv",
+		Extension:    "mkv",
+	},
+	"gif": {
+		Name:       "GIF-friendly (10 FPS, half size)",
+		VideoCodec: "h264",
+		Scale:      0.5,
+		FPS:        10,
+		AudioCodec: "mp4a",
+		Mux:        "mp4",
+		Extension:  "mp4",
+	},
+}
+
+// OutputProfiles contains the predefined output profiles, in the order in
+// which they are presented to users.
+var OutputProfiles = []*OutputProfile{
+	RecordProfiles["h264"],
+	RecordProfiles["vp8"],
+	RecordProfiles["mjpeg"],
+	RecordProfiles["lossless"],
+	RecordProfiles["gif"],
+}
+
+// Video and audio codecs which can be stored in MPEG-TS segments.
+var (
+	segmentVideoCodecs = []string{"h264", "hevc", "mp4v", "mpgv", "mp2v"}
+	segmentAudioCodecs = []string{"mp4a", "mpga", "a52", "opus"}
+)
+
+// DefaultRecordProfile is the name of the profile used by RecordScreen if
+// none is specified.
+const DefaultRecordProfile = "h264"
+
+// RecordProfileNames returns the sorted short names of the predefined
+// output profiles.
+func RecordProfileNames() []string {
+	names := make([]string, 0, len(RecordProfiles))
+	for name := range RecordProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Transcode returns the transcode module configured using the settings
+// of the profile. If an audio codec is specified, the audio streams of the
+// input are also encoded.
+func (p *OutputProfile) Transcode(audio *screencap.AudioCodec) *SoutModule {
+	transcode := NewSoutModule("transcode").Set("vcodec", p.VideoCodec)
+	if p.VideoEncoder != "" {
+		transcode.Set("venc", SoutRaw(p.VideoEncoder))
+	}
+	transcode.Set("vb", p.Bitrate)
+
+	scale := p.Scale
+	if scale <= 0 {
+		scale = 1
+	}
+	transcode.Set("scale", scale)
+
+	if p.FPS > 0 {
+		transcode.Set("fps", p.FPS)
+	}
+
+	if audio != nil {
+		transcode.
+			Set("acodec", audio.Codec).
+			Set("ab", audio.Bitrate).
+			Set("channels", 2).
+			Set("samplerate", 44100)
+	}
+
+	return transcode
+}
+
+// Chain returns a stream output chain which encodes the input using the
+// settings of the profile and saves it to the specified path.
+func (p *OutputProfile) Chain(path string, audio *screencap.AudioCodec) SoutChain {
+	return SoutChain{
+		p.Transcode(audio),
+		NewSoutModule("std").
+			Set("access", "file").
+			Set("mux", p.Mux).
+			Set("dst", path),
+	}
+}
+
+// FilePath returns the specified path, having its extension replaced with
+// the output file extension of the profile.
+func (p *OutputProfile) FilePath(path string) string {
+	if path == "" || p.Extension == "" {
+		return path
+	}
+
+	return strings.TrimSuffix(path, filepath.Ext(path)) + "." + p.Extension
+}
+
+// SegmentedChain returns a stream output chain which encodes the input
+// using the settings of the profile and splits it into numbered MPEG-TS
+// segments of the specified length. The segment file names are generated
+// from the specified path (e.g. rec.mp4 becomes rec-001.ts, rec-002.ts and
+// so on) and are listed in a playlist (e.g. rec.m3u8), which can be used
+// to play back the whole recording. The segments are written using the
+// livehttp access module, which only supports MPEG-TS, so an error is
+// returned if the codecs of the profile cannot be stored in it.
+func (p *OutputProfile) SegmentedChain(path string, length time.Duration, audio *screencap.AudioCodec) (SoutChain, error) {
+	if !containsFold(segmentVideoCodecs, p.VideoCodec) {
+		return nil, fmt.Errorf("%s recordings cannot be split: the %s video codec is not supported by MPEG-TS segments",
+			p.Name, p.VideoCodec)
+	}
+	if audio != nil && !containsFold(segmentAudioCodecs, audio.Codec) {
+		return nil, fmt.Errorf("%s recordings cannot be split: the %s audio codec is not supported by MPEG-TS segments",
+			p.Name, audio.Codec)
+	}
+
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	segmentName := filepath.Base(base) + "-###.ts"
+
+	seglen := int(length / time.Second)
+	if seglen < 1 {
+		seglen = 1
+	}
+
+	livehttp := NewSoutModule("livehttp").
+		Set("seglen", seglen).
+		Set("numsegs", 0).
+		Set("delsegs", false).
+		Set("index", base+".m3u8").
+		Set("index-url", segmentName)
+
+	return SoutChain{
+		p.Transcode(audio),
+		NewSoutModule("std").
+			Set("access", livehttp).
+			Set("mux", "ts").
+			Set("dst", filepath.Join(filepath.Dir(path), segmentName)),
+	}, nil
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
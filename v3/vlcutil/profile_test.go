@@ -0,0 +1,29 @@
+package vlcutil
+
+import (
+	"testing"
+
+	"github.com/adrg/libvlc-go-examples/shared/screencap"
+)
+
+func TestOutputProfile(t *testing.T) {
+	if len(OutputProfiles) != len(RecordProfiles) {
+		t.Fatalf("got %d ordered profiles, want %d", len(OutputProfiles), len(RecordProfiles))
+	}
+	for _, profile := range OutputProfiles {
+		if profile == nil {
+			t.Fatal("ordered profile missing from RecordProfiles")
+		}
+	}
+
+	profile := RecordProfiles["gif"]
+	audio := &screencap.AudioCodec{Codec: "mp4a", Bitrate: 128}
+	want := `#transcode{vcodec=h264,vb=0,scale=0.5,fps=10,acodec=mp4a,ab=128,channels=2,samplerate=44100}:std{access=file,mux=mp4,dst=out.mp4}`
+	if got := profile.Chain("out.mp4", audio).String(); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	if got := RecordProfiles["lossless"].FilePath("/tmp/rec.mp4"); got != "/tmp/rec.mkv" {
+		t.Errorf("got %q, want %q", got, "/tmp/rec.mkv")
+	}
+}
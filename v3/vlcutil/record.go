@@ -0,0 +1,143 @@
+package vlcutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+)
+
+// RecordOptions contains the options used by RecordScreen.
+type RecordOptions struct {
+	// Output is the path of the recording file.
+	Output string
+
+	// Screen contains the captured region and the capture frame rate.
+	// The zero value captures the entire screen.
+	Screen vlc.MediaScreenOptions
+
+	// Profile is the name of a predefined profile from RecordProfiles.
+	// DefaultRecordProfile is used if empty. Ignored if Custom is set.
+	Profile string
+
+	// Custom is an output profile used instead of the predefined ones.
+	Custom *OutputProfile
+}
+
+func (o RecordOptions) profile() (*OutputProfile, error) {
+	if o.Custom != nil {
+		return o.Custom, nil
+	}
+
+	name := o.Profile
+	if name == "" {
+		name = DefaultRecordProfile
+	}
+	profile, ok := RecordProfiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile %q. Available profiles: %s",
+			name, strings.Join(RecordProfileNames(), ", "))
+	}
+
+	return profile, nil
+}
+
+// soutOption returns the media option which saves the recording to the
+// output file.
+func (o RecordOptions) soutOption() (string, error) {
+	if o.Output == "" {
+		return "", errors.New("no output file specified")
+	}
+
+	profile, err := o.profile()
+	if err != nil {
+		return "", err
+	}
+
+	return profile.Chain(o.Output, nil).MediaOption(), nil
+}
+
+// RecordScreen records the screen to the output file until the context is
+// done. The context being done is the regular way of stopping the recording,
+// so it is not considered an error. An error is returned if the capture
+// fails or ends unexpectedly. The libVLC screen module must be installed.
+func RecordScreen(ctx context.Context, opts RecordOptions) error {
+	saveOpt, err := opts.soutOption()
+	if err != nil {
+		return err
+	}
+
+	// Create a new player.
+	player, err := vlc.NewPlayer()
+	if err != nil {
+		return err
+	}
+	defer player.Release()
+
+	// Create new media instance from screen.
+	screenOpts := opts.Screen
+	media, err := vlc.NewMediaFromScreen(&screenOpts)
+	if err != nil {
+		return err
+	}
+	defer media.Release()
+
+	// Configure media to save the recording to the output file.
+	if err := media.AddOptions(saveOpt); err != nil {
+		return err
+	}
+	if err := player.SetMedia(media); err != nil {
+		return err
+	}
+
+	// Retrieve player event manager.
+	manager, err := player.EventManager()
+	if err != nil {
+		return err
+	}
+
+	// Register the player error and end reached events with the
+	// event manager.
+	errCh := make(chan error, 1)
+	eventCallback := func(event vlc.Event, userData interface{}) {
+		err := errors.New("screen capture ended unexpectedly")
+		if event == vlc.MediaPlayerEncounteredError {
+			err = errors.New("screen capture failed")
+		}
+
+		// Do not block the libVLC event thread.
+		select {
+		case errCh <- err:
+		default:
+		}
+	}
+
+	eventIDs := make([]vlc.EventID, 0, 2)
+	for _, event := range []vlc.Event{vlc.MediaPlayerEncounteredError, vlc.MediaPlayerEndReached} {
+		eventID, err := manager.Attach(event, eventCallback, nil)
+		if err != nil {
+			return err
+		}
+		eventIDs = append(eventIDs, eventID)
+	}
+	defer manager.Detach(eventIDs...)
+
+	// Start recording.
+	if err := player.Play(); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+	case err = <-errCh:
+	}
+
+	// Stopping the player finalizes the output file.
+	if stopErr := player.Stop(); stopErr != nil && err == nil {
+		err = stopErr
+	}
+
+	return err
+}
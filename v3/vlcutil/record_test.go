@@ -0,0 +1,34 @@
+package vlcutil
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRecordOptions(t *testing.T) {
+	opt, err := RecordOptions{Output: `/tmp/my "rec".mp4`}.soutOption()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `:sout=#transcode{vcodec=h264,vb=0,scale=1}:std{access=file,mux=mp4,dst="/tmp/my \"rec\".mp4"}`
+	if opt != want {
+		t.Errorf("got %s, want %s", opt, want)
+	}
+
+	custom := &OutputProfile{VideoCodec: "theo", Bitrate: 1000, Mux: "ogg", Extension: "ogv"}
+	opt, err = RecordOptions{Output: "out.ogv", Profile: "vp8", Custom: custom}.soutOption()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `:sout=#transcode{vcodec=theo,vb=1000,scale=1}:std{access=file,mux=ogg,dst=out.ogv}`; opt != want {
+		t.Errorf("got %s, want %s", opt, want)
+	}
+
+	if _, err := (RecordOptions{}).soutOption(); err == nil {
+		t.Error("expected error for missing output file")
+	}
+	_, err = RecordOptions{Output: "out.mp4", Profile: "unknown"}.soutOption()
+	if err == nil || !strings.Contains(err.Error(), strings.Join(RecordProfileNames(), ", ")) {
+		t.Errorf("expected error listing the available profiles, got %v", err)
+	}
+}
@@ -0,0 +1,273 @@
+package vlcutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+)
+
+// Renderer describes a renderer (e.g. Chromecast) discovered on the local
+// network.
+type Renderer struct {
+	Name    string
+	Type    string
+	IconURI string
+	Audio   bool
+	Video   bool
+
+	// Renderer is the libVLC renderer, which can be passed in to
+	// vlc.Player.SetRenderer. It is valid until the discoverer which
+	// found it is released.
+	Renderer *vlc.Renderer
+
+	// release releases the discoverer owned by the renderer, if any.
+	release func() error
+}
+
+// Capabilities returns a textual representation of the renderer flags
+// (e.g. audio+video).
+func (r *Renderer) Capabilities() string {
+	var caps []string
+	if r.Audio {
+		caps = append(caps, "audio")
+	}
+	if r.Video {
+		caps = append(caps, "video")
+	}
+	if len(caps) == 0 {
+		return "-"
+	}
+
+	return strings.Join(caps, "+")
+}
+
+// Release releases the discovery services which found the renderer, if they
+// are owned by the renderer, which is the case for renderers returned by
+// DiscoverRenderer. The renderers returned by RendererDiscoverer.Discover
+// are owned by the discoverer, so releasing them is a no-op.
+func (r *Renderer) Release() error {
+	if r.release == nil {
+		return nil
+	}
+
+	release := r.release
+	r.release, r.Renderer = nil, nil
+	return release()
+}
+
+func newRenderer(r *vlc.Renderer) (*Renderer, error) {
+	name, err := r.Name()
+	if err != nil {
+		return nil, err
+	}
+	rendererType, err := r.Type()
+	if err != nil {
+		return nil, err
+	}
+	iconURI, err := r.IconURI()
+	if err != nil {
+		return nil, err
+	}
+	flags, err := r.Flags()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Renderer{
+		Name:     name,
+		Type:     string(rendererType),
+		IconURI:  iconURI,
+		Audio:    flags != nil && flags.AudioEnabled,
+		Video:    flags != nil && flags.VideoEnabled,
+		Renderer: r,
+	}, nil
+}
+
+// RendererFilter matches renderers by name and type, case-insensitively.
+// Empty fields match any renderer.
+type RendererFilter struct {
+	Name string
+	Type string
+}
+
+// Match returns true if the specified renderer matches the filter.
+func (f RendererFilter) Match(r *Renderer) bool {
+	if f.Name != "" && !strings.EqualFold(f.Name, r.Name) {
+		return false
+	}
+	if f.Type != "" && !strings.EqualFold(f.Type, r.Type) {
+		return false
+	}
+
+	return true
+}
+
+// String returns a textual representation of the filter
+// (e.g. name="Living Room", type=chromecast).
+func (f RendererFilter) String() string {
+	var conds []string
+	if f.Name != "" {
+		conds = append(conds, fmt.Sprintf("name=%q", f.Name))
+	}
+	if f.Type != "" {
+		conds = append(conds, "type="+f.Type)
+	}
+	if len(conds) == 0 {
+		return "any"
+	}
+
+	return strings.Join(conds, ", ")
+}
+
+// RendererDiscoverer discovers renderers using multiple libVLC renderer
+// discovery services (e.g. microdns_renderer).
+type RendererDiscoverer struct {
+	discoverers []*vlc.RendererDiscoverer
+}
+
+// NewRendererDiscoverer returns a discoverer which uses the renderer
+// discovery services with the specified names. If no names are specified,
+// all the discovery services provided by libVLC are used.
+func NewRendererDiscoverer(services ...string) (*RendererDiscoverer, error) {
+	if len(services) == 0 {
+		descriptors, err := vlc.ListRendererDiscoverers()
+		if err != nil {
+			return nil, err
+		}
+		for _, descriptor := range descriptors {
+			services = append(services, descriptor.Name)
+		}
+	}
+
+	d := &RendererDiscoverer{}
+	for _, service := range services {
+		discoverer, err := vlc.NewRendererDiscoverer(service)
+		if err != nil {
+			d.Release()
+			return nil, fmt.Errorf("cannot create %s discovery service: %w", service, err)
+		}
+		d.discoverers = append(d.discoverers, discoverer)
+	}
+	if len(d.discoverers) == 0 {
+		return nil, errors.New("could not find any renderer discovery service")
+	}
+
+	return d, nil
+}
+
+// Discover searches for renderers until the context is done or until the
+// match function returns true for a discovered renderer. The match function
+// can be nil. Returns all the renderers which are still available when the
+// discovery stops. The context being done is not considered an error.
+func (d *RendererDiscoverer) Discover(ctx context.Context, match func(*Renderer) bool) ([]*Renderer, error) {
+	var (
+		mu        sync.Mutex
+		renderers []*Renderer
+		matched   = make(chan struct{})
+		once      sync.Once
+	)
+
+	callback := func(event vlc.Event, r *vlc.Renderer) {
+		// NOTE: the discovery service cannot be stopped or released from
+		// the callback function. Doing so will result in undefined behavior.
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch event {
+		case vlc.RendererDiscovererItemAdded:
+			renderer, err := newRenderer(r)
+			if err != nil {
+				return
+			}
+			renderers = append(renderers, renderer)
+
+			if match != nil && match(renderer) {
+				once.Do(func() { close(matched) })
+			}
+		case vlc.RendererDiscovererItemDeleted:
+			deleted, err := newRenderer(r)
+			if err != nil {
+				return
+			}
+
+			for i, renderer := range renderers {
+				if renderer.Name == deleted.Name && renderer.Type == deleted.Type {
+					renderers = append(renderers[:i], renderers[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+
+	// Start discovery services.
+	for _, discoverer := range d.discoverers {
+		if err := discoverer.Start(callback); err != nil {
+			d.stop()
+			return nil, err
+		}
+	}
+
+	select {
+	case <-matched:
+	case <-ctx.Done():
+	}
+	d.stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	return renderers, nil
+}
+
+// Release releases the discovery services. The renderers returned by
+// Discover must not be used after the discoverer is released.
+func (d *RendererDiscoverer) Release() error {
+	var err error
+	for _, discoverer := range d.discoverers {
+		if releaseErr := discoverer.Release(); releaseErr != nil && err == nil {
+			err = releaseErr
+		}
+	}
+	d.discoverers = nil
+
+	return err
+}
+
+func (d *RendererDiscoverer) stop() {
+	for _, discoverer := range d.discoverers {
+		discoverer.Stop()
+	}
+}
+
+// DiscoverRenderer searches for the first renderer matching the specified
+// filter, using all the discovery services provided by libVLC, until the
+// context is done. The returned renderer owns the discovery services, so
+// it must be released when it is no longer needed.
+func DiscoverRenderer(ctx context.Context, filter RendererFilter) (*Renderer, error) {
+	discoverer, err := NewRendererDiscoverer()
+	if err != nil {
+		return nil, err
+	}
+
+	renderers, err := discoverer.Discover(ctx, filter.Match)
+	if err != nil {
+		discoverer.Release()
+		return nil, err
+	}
+
+	for _, renderer := range renderers {
+		if filter.Match(renderer) {
+			renderer.release = discoverer.Release
+			return renderer, nil
+		}
+	}
+	discoverer.Release()
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("no renderer matching filter (%s) found: %w", filter, err)
+	}
+	return nil, fmt.Errorf("no renderer matching filter (%s) found", filter)
+}
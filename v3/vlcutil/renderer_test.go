@@ -0,0 +1,44 @@
+package vlcutil
+
+import "testing"
+
+func TestRendererFilter(t *testing.T) {
+	renderer := &Renderer{Name: "Living Room TV", Type: "chromecast"}
+
+	tests := []struct {
+		filter RendererFilter
+		match  bool
+		str    string
+	}{
+		{RendererFilter{}, true, "any"},
+		{RendererFilter{Name: "living room tv"}, true, `name="living room tv"`},
+		{RendererFilter{Type: "Chromecast"}, true, "type=Chromecast"},
+		{RendererFilter{Name: "Living Room TV", Type: "upnp"}, false, `name="Living Room TV", type=upnp`},
+		{RendererFilter{Name: "Kitchen"}, false, `name="Kitchen"`},
+	}
+	for _, test := range tests {
+		if got := test.filter.Match(renderer); got != test.match {
+			t.Errorf("%s: got match %t, want %t", test.filter, got, test.match)
+		}
+		if got := test.filter.String(); got != test.str {
+			t.Errorf("got filter string %s, want %s", got, test.str)
+		}
+	}
+}
+
+func TestRendererCapabilities(t *testing.T) {
+	tests := []struct {
+		renderer Renderer
+		caps     string
+	}{
+		{Renderer{Audio: true, Video: true}, "audio+video"},
+		{Renderer{Audio: true}, "audio"},
+		{Renderer{Video: true}, "video"},
+		{Renderer{}, "-"},
+	}
+	for _, test := range tests {
+		if got := test.renderer.Capabilities(); got != test.caps {
+			t.Errorf("got capabilities %s, want %s", got, test.caps)
+		}
+	}
+}
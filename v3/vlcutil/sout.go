@@ -0,0 +1,114 @@
+package vlcutil
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SoutRaw represents a stream output option value which is used as is,
+// without being quoted (e.g. encoder module definitions).
+type SoutRaw string
+
+// SoutModule represents a stream output module (e.g. transcode, std) along
+// with its options.
+// See https://wiki.videolan.org/Documentation:Streaming_HowTo/Advanced_Streaming_Using_the_Command_Line.
+type SoutModule struct {
+	name string
+	opts []soutOption
+}
+
+type soutOption struct {
+	key   string
+	value string
+}
+
+// NewSoutModule returns a new stream output module having the specified
+// name and no options.
+func NewSoutModule(name string) *SoutModule {
+	return &SoutModule{name: name}
+}
+
+// Set adds an option to the module. Supported values are strings, integers,
+// floats, booleans, raw values, modules and module chains. Boolean options
+// are added as flags (e.g. `key` if true and `no-key` if false).
+func (m *SoutModule) Set(key string, value interface{}) *SoutModule {
+	var val string
+	switch v := value.(type) {
+	case string:
+		val = soutQuote(v)
+	case SoutRaw:
+		val = string(v)
+	case int:
+		val = strconv.Itoa(v)
+	case float64:
+		val = strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		if !v {
+			key = "no-" + key
+		}
+	case *SoutModule:
+		val = v.String()
+	case SoutChain:
+		val = v.join()
+	default:
+		panic("unsupported sout option value")
+	}
+
+	m.opts = append(m.opts, soutOption{key: key, value: val})
+	return m
+}
+
+// String returns the textual representation of the module
+// (e.g. `std{access=file,mux=mp4,dst=out.mp4}`).
+func (m *SoutModule) String() string {
+	if len(m.opts) == 0 {
+		return m.name
+	}
+
+	opts := make([]string, 0, len(m.opts))
+	for _, opt := range m.opts {
+		if opt.value == "" {
+			opts = append(opts, opt.key)
+			continue
+		}
+		opts = append(opts, opt.key+"="+opt.value)
+	}
+
+	return m.name + "{" + strings.Join(opts, ",") + "}"
+}
+
+// SoutChain represents a chain of stream output modules.
+type SoutChain []*SoutModule
+
+func (c SoutChain) join() string {
+	modules := make([]string, 0, len(c))
+	for _, module := range c {
+		modules = append(modules, module.String())
+	}
+
+	return strings.Join(modules, ":")
+}
+
+// String returns the textual representation of the chain
+// (e.g. `#transcode{vcodec=h264}:std{access=file,dst=out.mp4}`).
+func (c SoutChain) String() string {
+	return "#" + c.join()
+}
+
+// MediaOption returns the chain as a media option which can be passed in
+// to vlc.Media.AddOptions.
+func (c SoutChain) MediaOption() string {
+	return ":sout=" + c.String()
+}
+
+// soutQuote quotes the provided value, if it contains characters which
+// have a special meaning in stream output chains.
+func soutQuote(value string) string {
+	if !strings.ContainsAny(value, " ,{}=:'\"\\") {
+		return value
+	}
+
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + value + `"`
+}
@@ -0,0 +1,54 @@
+package vlcutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adrg/libvlc-go-examples/shared/screencap"
+)
+
+func TestSoutChain(t *testing.T) {
+	chain := SoutChain{
+		NewSoutModule("transcode").
+			Set("vcodec", "h264").
+			Set("venc", SoutRaw("x264{qp=0}")).
+			Set("vb", 0).
+			Set("scale", 0.5),
+		NewSoutModule("std").
+			Set("access", "file").
+			Set("mux", "mp4").
+			Set("dst", `/tmp/my "rec".mp4`),
+	}
+	duplicate := NewSoutModule("duplicate").
+		Set("dst", chain).
+		Set("dst", SoutRaw("display")).
+		Set("audio", false)
+
+	want := `:sout=#duplicate{dst=transcode{vcodec=h264,venc=x264{qp=0},vb=0,scale=0.5}:std{access=file,mux=mp4,dst="/tmp/my \"rec\".mp4"},dst=display,no-audio}`
+	if got := (SoutChain{duplicate}).MediaOption(); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	// Segmented recordings are stored using MPEG-TS, regardless of the
+	// container of the profile.
+	profile := &OutputProfile{Name: "MPEG-4 / AVI", VideoCodec: "mp4v", Bitrate: 2000, Mux: "avi", Extension: "avi"}
+	segmented, err := profile.SegmentedChain("/tmp/rec.avi", 90*time.Second, &screencap.AudioCodec{Codec: "mpga", Bitrate: 128})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `#transcode{vcodec=mp4v,vb=2000,scale=1,acodec=mpga,ab=128,channels=2,samplerate=44100}:` +
+		`std{access=livehttp{seglen=90,numsegs=0,no-delsegs,index=/tmp/rec.m3u8,index-url=rec-###.ts},mux=ts,dst=/tmp/rec-###.ts}`
+	if got := segmented.String(); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	// Profiles having codecs which cannot be stored in MPEG-TS cannot be
+	// used to record segments.
+	if _, err := RecordProfiles["vp8"].SegmentedChain("/tmp/rec.webm", time.Minute, nil); err == nil {
+		t.Error("expected error for VP8 segments")
+	}
+	flac := &screencap.AudioCodec{Codec: "flac"}
+	if _, err := RecordProfiles["lossless"].SegmentedChain("/tmp/rec.mkv", time.Minute, flac); err == nil {
+		t.Error("expected error for FLAC segments")
+	}
+}
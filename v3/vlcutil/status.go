@@ -0,0 +1,106 @@
+package vlcutil
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+)
+
+// RecordingLimits contains the limits after which recording is stopped.
+type RecordingLimits struct {
+	// Maximum recording duration. Default: 0 (unlimited).
+	MaxDuration time.Duration
+
+	// Maximum output size in bytes, including all segments of the
+	// recording. Default: 0 (unlimited).
+	MaxSize int64
+}
+
+// RecordingStatus contains information about a recording in progress.
+type RecordingStatus struct {
+	Elapsed       time.Duration
+	FileSize      int64
+	DroppedFrames int
+	Segment       int
+	Paused        bool
+}
+
+// String returns a textual representation of the status, suitable for
+// being displayed in the status line of a recorder.
+func (s RecordingStatus) String() string {
+	state := "Recording"
+	if s.Paused {
+		state = "Paused"
+	}
+
+	status := fmt.Sprintf("%s: %s  |  File size: %s  |  Dropped frames: %d",
+		state, FormatElapsed(s.Elapsed), FormatFileSize(s.FileSize), s.DroppedFrames)
+	if s.Segment > 1 {
+		status += fmt.Sprintf("  |  Segment: %d", s.Segment)
+	}
+
+	return status
+}
+
+// Exceeds returns true if the status Exceeds any of the provided limits.
+func (s RecordingStatus) Exceeds(limits RecordingLimits) bool {
+	if limits.MaxDuration > 0 && s.Elapsed >= limits.MaxDuration {
+		return true
+	}
+	if limits.MaxSize > 0 && s.FileSize >= limits.MaxSize {
+		return true
+	}
+
+	return false
+}
+
+// NewRecordingStatus returns the status of the recording played by the
+// specified player, having the specified duration and being saved at the
+// specified paths. Dropped frames are retrieved from the media statistics.
+func NewRecordingStatus(player *vlc.Player, elapsed time.Duration, paths ...string) RecordingStatus {
+	status := RecordingStatus{
+		Elapsed: elapsed,
+		Segment: len(paths),
+	}
+
+	// Get output file size.
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			status.FileSize += info.Size()
+		}
+	}
+
+	// Get dropped frames from the media statistics.
+	if media, _ := player.Media(); media != nil {
+		if stats, err := media.Stats(); err == nil && stats != nil {
+			status.DroppedFrames = stats.LostPictures
+		}
+	}
+
+	return status
+}
+
+// FormatElapsed formats the specified duration as HH:MM:SS.
+func FormatElapsed(d time.Duration) string {
+	d = d.Truncate(time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
+
+// FormatFileSize formats the specified size in bytes using binary units
+// (e.g. 1.5 MiB).
+func FormatFileSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
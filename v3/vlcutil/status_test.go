@@ -0,0 +1,39 @@
+package vlcutil
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordingStatus(t *testing.T) {
+	status := RecordingStatus{
+		Elapsed:       90*time.Minute + 5*time.Second + 300*time.Millisecond,
+		FileSize:      3 * 1024 * 1024 / 2,
+		DroppedFrames: 2,
+		Segment:       3,
+	}
+	want := "Recording: 01:30:05  |  File size: 1.5 MiB  |  Dropped frames: 2  |  Segment: 3"
+	if got := status.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	tests := []struct {
+		limits RecordingLimits
+		want   bool
+	}{
+		{RecordingLimits{}, false},
+		{RecordingLimits{MaxDuration: time.Hour}, true},
+		{RecordingLimits{MaxDuration: 2 * time.Hour}, false},
+		{RecordingLimits{MaxSize: 1024 * 1024}, true},
+		{RecordingLimits{MaxSize: 2 * 1024 * 1024}, false},
+	}
+	for _, test := range tests {
+		if got := status.Exceeds(test.limits); got != test.want {
+			t.Errorf("Exceeds(%+v): got %t, want %t", test.limits, got, test.want)
+		}
+	}
+
+	if got := FormatFileSize(512); got != "512 B" {
+		t.Errorf("got %q, want %q", got, "512 B")
+	}
+}
@@ -0,0 +1,187 @@
+package vlcutil
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strconv"
+)
+
+// StreamProtocol represents a protocol used to serve live streams.
+type StreamProtocol string
+
+// Supported streaming protocols.
+const (
+	StreamHTTPTS   StreamProtocol = "http-ts"
+	StreamHTTPWebM StreamProtocol = "http-webm"
+	StreamHLS      StreamProtocol = "hls"
+	StreamRTSP     StreamProtocol = "rtsp"
+)
+
+// StreamProtocols contains the supported streaming protocols, along with
+// their descriptions.
+var StreamProtocols = []struct {
+	Protocol    StreamProtocol
+	Description string
+}{
+	{StreamHTTPTS, "HTTP (MPEG-TS)"},
+	{StreamHTTPWebM, "HTTP (WebM)"},
+	{StreamHLS, "HLS"},
+	{StreamRTSP, "RTSP"},
+}
+
+// StreamOptions contains the settings used to serve live streams.
+type StreamOptions struct {
+	// Streaming protocol.
+	Protocol StreamProtocol
+
+	// Port on which the stream is served.
+	Port int
+
+	// Directory in which HLS segments are written. Required when using HLS.
+	HLSDir string
+}
+
+// Names of the served streams.
+const (
+	// StreamName is the name under which streams are served.
+	StreamName = "stream"
+
+	// HLSIndexName is the name of the HLS index file (playlist).
+	HLSIndexName = StreamName + ".m3u8"
+)
+
+// Chain returns a stream output chain which encodes the input and serves
+// it using the configured protocol. If audio is true, the audio streams of
+// the input are also encoded.
+func (o StreamOptions) Chain(audio bool) (SoutChain, error) {
+	if o.Port <= 0 || o.Port > 65535 {
+		return nil, fmt.Errorf("invalid stream port %d", o.Port)
+	}
+
+	// Configure encoding.
+	vcodec, acodec := "h264", "mp4a"
+	if o.Protocol == StreamHTTPWebM {
+		vcodec, acodec = "VP80", "vorb"
+	}
+
+	transcode := NewSoutModule("transcode").
+		Set("vcodec", vcodec).
+		Set("vb", 2000)
+	if audio {
+		transcode.
+			Set("acodec", acodec).
+			Set("ab", 128).
+			Set("channels", 2).
+			Set("samplerate", 44100)
+	}
+
+	// Configure output.
+	var output *SoutModule
+	switch o.Protocol {
+	case StreamHTTPTS, StreamHTTPWebM:
+		mux, ext := "ts", ".ts"
+		if o.Protocol == StreamHTTPWebM {
+			mux, ext = "webm", ".webm"
+		}
+
+		output = NewSoutModule("std").
+			Set("access", "http").
+			Set("mux", mux).
+			Set("dst", ":"+strconv.Itoa(o.Port)+"/"+StreamName+ext)
+	case StreamHLS:
+		if o.HLSDir == "" {
+			return nil, errors.New("HLS output directory must be specified")
+		}
+
+		segmentName := StreamName + "-########.ts"
+		livehttp := NewSoutModule("livehttp").
+			Set("seglen", 4).
+			Set("numsegs", 5).
+			Set("delsegs", true).
+			Set("index", filepath.Join(o.HLSDir, HLSIndexName)).
+			Set("index-url", segmentName)
+
+		output = NewSoutModule("std").
+			Set("access", livehttp).
+			Set("mux", SoutRaw("ts{use-key-frames}")).
+			Set("dst", filepath.Join(o.HLSDir, segmentName))
+	case StreamRTSP:
+		output = NewSoutModule("rtp").
+			Set("sdp", "rtsp://:"+strconv.Itoa(o.Port)+"/"+StreamName)
+	default:
+		return nil, fmt.Errorf("unsupported streaming protocol %q", o.Protocol)
+	}
+
+	return SoutChain{transcode, output}, nil
+}
+
+// URLs returns the URLs clients can use in order to open the stream, for
+// each of the local network addresses of the machine.
+func (o StreamOptions) URLs() []string {
+	var scheme, path string
+	switch o.Protocol {
+	case StreamHTTPTS:
+		scheme, path = "http", StreamName+".ts"
+	case StreamHTTPWebM:
+		scheme, path = "http", StreamName+".webm"
+	case StreamHLS:
+		scheme, path = "http", HLSIndexName
+	case StreamRTSP:
+		scheme, path = "rtsp", StreamName
+	}
+
+	hosts := localAddresses()
+	urls := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		hostPort := net.JoinHostPort(host, strconv.Itoa(o.Port))
+		urls = append(urls, fmt.Sprintf("%s://%s/%s", scheme, hostPort, path))
+	}
+
+	return urls
+}
+
+// ServeHLS serves the HLS segments written to the configured directory
+// over HTTP, as libVLC only writes the segments to disk. The returned
+// server must be closed when the stream ends.
+func (o StreamOptions) ServeHLS() (*http.Server, error) {
+	listener, err := net.Listen("tcp", ":"+strconv.Itoa(o.Port))
+	if err != nil {
+		return nil, err
+	}
+
+	server := &http.Server{Handler: http.FileServer(http.Dir(o.HLSDir))}
+	go server.Serve(listener)
+
+	return server, nil
+}
+
+// localAddresses returns the IPv4 addresses of the machine, starting with
+// the non-loopback ones.
+func localAddresses() []string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return []string{"localhost"}
+	}
+
+	var hosts, loopback []string
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.To4() == nil {
+			continue
+		}
+
+		if ipNet.IP.IsLoopback() {
+			loopback = append(loopback, ipNet.IP.String())
+			continue
+		}
+		hosts = append(hosts, ipNet.IP.String())
+	}
+	if len(hosts) == 0 && len(loopback) == 0 {
+		return []string{"localhost"}
+	}
+
+	return append(hosts, loopback...)
+}
@@ -0,0 +1,65 @@
+package vlcutil
+
+import (
+	"fmt"
+	"io"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+)
+
+// WriteTracks writes information about the tracks of the specified media
+// to w. The media must be parsed beforehand (e.g. using ParseMedia).
+func WriteTracks(w io.Writer, media *vlc.Media) error {
+	// Retrieve media tracks.
+	tracks, err := media.Tracks()
+	if err != nil {
+		return err
+	}
+
+	for i, track := range tracks {
+		// Retrieve codec description.
+		codecDesc, err := track.CodecDescription()
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(w, "Track #%d\n", i+1)
+		fmt.Fprintln(w, "ID:", track.ID)
+		fmt.Fprintln(w, "Bit rate:", track.BitRate)
+		fmt.Fprintln(w, "Codec:", track.Codec)
+		fmt.Fprintln(w, "Original codec:", track.OriginalCodec)
+		fmt.Fprintln(w, "Codec description:", codecDesc)
+		fmt.Fprintln(w, "Profile:", track.Profile)
+		fmt.Fprintln(w, "Level:", track.Level)
+		fmt.Fprintln(w, "Language:", track.Language)
+		fmt.Fprintln(w, "Description:", track.Description)
+
+		switch track.Type {
+		case vlc.MediaTrackAudio:
+			audio := track.Audio
+			fmt.Fprintln(w, "Type: audio track")
+			fmt.Fprintln(w, "Audio channels:", audio.Channels)
+			fmt.Fprintln(w, "Audio rate:", audio.Rate)
+		case vlc.MediaTrackVideo:
+			video := track.Video
+			fmt.Fprintln(w, "Type: video track")
+			fmt.Fprintln(w, "Video width:", video.Width)
+			fmt.Fprintln(w, "Video height:", video.Height)
+			fmt.Fprintf(w, "Aspect ratio: %d:%d\n", video.AspectRatioNum, video.AspectRatioDen)
+			fmt.Fprintf(w, "Frame rate: %d/%d\n", video.FrameRateNum, video.FrameRateDen)
+			fmt.Fprintln(w, "Video orientation", video.Orientation)
+			fmt.Fprintln(w, "Video projection", video.Projection)
+
+			pose := video.Pose
+			fmt.Fprintf(w, "Video viewopoint: %.2f yaw, %.2f pitch, %.2f roll, %.2f FOV\n",
+				pose.Yaw, pose.Pitch, pose.Roll, pose.FOV)
+		case vlc.MediaTrackText:
+			subtitle := track.Subtitle
+			fmt.Fprintln(w, "Type: subtitle track")
+			fmt.Fprintln(w, "Encoding:", subtitle.Encoding)
+		}
+		fmt.Fprintln(w, "---")
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+//go:build integration
+
+package vlcutil
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+
+	"github.com/adrg/libvlc-go-examples/shared/fixtures"
+)
+
+func loadTracks(t *testing.T, path string) (*vlc.Media, []*vlc.MediaTrack) {
+	t.Helper()
+
+	media, err := vlc.NewMediaFromPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { media.Release() })
+
+	err = ParseMedia(media, 10*time.Second, vlc.MediaParseLocal, vlc.MediaParseNetwork)
+	if err != nil {
+		t.Fatalf("cannot parse media: %v", err)
+	}
+
+	tracks, err := media.Tracks()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return media, tracks
+}
+
+func TestAudioTracks(t *testing.T) {
+	_, tracks := loadTracks(t, fixtures.SineWAV(t))
+	if len(tracks) != 1 {
+		t.Fatalf("got %d tracks, want 1", len(tracks))
+	}
+
+	track := tracks[0]
+	if track.Type != vlc.MediaTrackAudio {
+		t.Fatalf("got track type %v, want audio", track.Type)
+	}
+	if track.Audio.Channels != fixtures.AudioChannels {
+		t.Errorf("got %d audio channels, want %d", track.Audio.Channels, fixtures.AudioChannels)
+	}
+	if track.Audio.Rate != fixtures.SampleRate {
+		t.Errorf("got audio rate %d, want %d", track.Audio.Rate, fixtures.SampleRate)
+	}
+}
+
+func TestMultipleTracks(t *testing.T) {
+	media, tracks := loadTracks(t, fixtures.MultiTrack(t))
+
+	counts := map[vlc.MediaTrackType]int{}
+	for _, track := range tracks {
+		counts[track.Type]++
+
+		switch track.Type {
+		case vlc.MediaTrackVideo:
+			if track.Video.Width != fixtures.VideoWidth || track.Video.Height != fixtures.VideoHeight {
+				t.Errorf("got video size %dx%d, want %dx%d", track.Video.Width, track.Video.Height,
+					fixtures.VideoWidth, fixtures.VideoHeight)
+			}
+		case vlc.MediaTrackAudio:
+			if track.Audio.Channels != fixtures.AudioChannels {
+				t.Errorf("got %d audio channels, want %d", track.Audio.Channels, fixtures.AudioChannels)
+			}
+		}
+	}
+
+	want := map[vlc.MediaTrackType]int{
+		vlc.MediaTrackVideo: 1,
+		vlc.MediaTrackAudio: 2,
+		vlc.MediaTrackText:  1,
+	}
+	for trackType, count := range want {
+		if counts[trackType] != count {
+			t.Errorf("got %d tracks of type %v, want %d", counts[trackType], trackType, count)
+		}
+	}
+
+	// Check the printed track information.
+	var buf bytes.Buffer
+	if err := WriteTracks(&buf, media); err != nil {
+		t.Fatal(err)
+	}
+
+	output := buf.String()
+	if got := strings.Count(output, "---\n"); got != len(tracks) {
+		t.Errorf("got %d printed tracks, want %d", got, len(tracks))
+	}
+	for _, line := range []string{
+		"Type: video track",
+		"Type: audio track",
+		"Type: subtitle track",
+		fmt.Sprintf("Video width: %d", fixtures.VideoWidth),
+		fmt.Sprintf("Audio rate: %d", fixtures.SampleRate),
+	} {
+		if !strings.Contains(output, line+"\n") {
+			t.Errorf("output does not contain %q:\n%s", line, output)
+		}
+	}
+}
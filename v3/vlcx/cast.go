@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+
+	"github.com/adrg/libvlc-go-examples/v3/vlcutil"
+)
+
+var castCommand = &command{
+	name:     "cast",
+	args:     "[-list | <path or URL>]",
+	summary:  "cast media to a network renderer",
+	complete: completion{files: true},
+	setup: func(flags *flag.FlagSet, e *env) func(context.Context, []string) error {
+		var (
+			timeout      = flags.Duration("timeout", 10*time.Second, "maximum renderer discovery duration")
+			list         = flags.Bool("list", false, "list the renderers found within the timeout and exit")
+			name         = flags.String("name", "", "name of the renderer (case insensitive)")
+			rendererType = flags.String("type", "", "type of the renderer (e.g. chromecast)")
+		)
+
+		return func(ctx context.Context, args []string) error {
+			filter := vlcutil.RendererFilter{Name: *name, Type: *rendererType}
+			if *list {
+				return listRenderers(ctx, e, filter, *timeout)
+			}
+			if len(args) != 1 {
+				return errUsage
+			}
+
+			// Discover renderer.
+			e.verbose("Searching for renderer (%s)...\n", filter)
+			discoverCtx, cancel := context.WithTimeout(ctx, *timeout)
+			renderer, err := vlcutil.DiscoverRenderer(discoverCtx, filter)
+			cancel()
+			if err != nil {
+				return err
+			}
+			defer renderer.Release()
+
+			// Create a new player.
+			player, err := vlc.NewPlayer()
+			if err != nil {
+				return err
+			}
+			defer func() {
+				player.Stop()
+				player.Release()
+			}()
+
+			media, err := newMedia(args[0], false)
+			if err != nil {
+				return err
+			}
+			defer media.Release()
+
+			if err := player.SetMedia(media); err != nil {
+				return err
+			}
+			if err := player.SetRenderer(renderer.Renderer); err != nil {
+				return err
+			}
+
+			e.logger.Printf("Casting %s to %s (%s).\n", args[0], renderer.Name, renderer.Type)
+			return vlcutil.PlayMedia(ctx, player)
+		}
+	},
+}
+
+// listRenderers writes the renderers matching the filter, found within
+// the specified timeout, as a table.
+func listRenderers(ctx context.Context, e *env, filter vlcutil.RendererFilter, timeout time.Duration) error {
+	discoverer, err := vlcutil.NewRendererDiscoverer()
+	if err != nil {
+		return err
+	}
+	defer discoverer.Release()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	renderers, err := discoverer.Discover(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(e.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTYPE\tFLAGS")
+	for _, renderer := range renderers {
+		if filter.Match(renderer) {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", renderer.Name, renderer.Type, renderer.Capabilities())
+		}
+	}
+
+	return tw.Flush()
+}
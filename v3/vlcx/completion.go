@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// shells contains the shells for which completion scripts are generated.
+var shells = map[string]func(w io.Writer, specs []commandSpec, global commandSpec){
+	"bash": writeBashCompletion,
+	"zsh":  writeZshCompletion,
+	"fish": writeFishCompletion,
+}
+
+func shellNames() []string {
+	names := make([]string, 0, len(shells))
+	for name := range shells {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+var completionCommand = &command{
+	name:    "completion",
+	args:    "<" + strings.Join(shellNames(), "|") + ">",
+	summary: "print a shell completion script",
+	noVLC:   true,
+	complete: completion{
+		args: shellNames(),
+	},
+	setup: func(flags *flag.FlagSet, e *env) func(context.Context, []string) error {
+		return func(ctx context.Context, args []string) error {
+			if len(args) != 1 {
+				return errUsage
+			}
+
+			write, ok := shells[args[0]]
+			if !ok {
+				return fmt.Errorf("unsupported shell %q. Supported shells: %s",
+					args[0], strings.Join(shellNames(), ", "))
+			}
+
+			write(e.stdout, commandSpecs(), globalSpec())
+			return nil
+		}
+	},
+}
+
+// flagSpec describes a command line flag, for shell completion purposes.
+type flagSpec struct {
+	name   string
+	usage  string
+	isBool bool
+	values []string
+	files  bool
+}
+
+// commandSpec describes a command, for shell completion purposes.
+type commandSpec struct {
+	name    string
+	summary string
+	flags   []flagSpec
+	args    []string
+	files   bool
+}
+
+// valueFlags returns the names of the flags which take a value.
+func (c commandSpec) valueFlags() []string {
+	var names []string
+	for _, f := range c.flags {
+		if !f.isBool {
+			names = append(names, "-"+f.name)
+		}
+	}
+
+	return names
+}
+
+func (c commandSpec) flagNames() []string {
+	names := make([]string, 0, len(c.flags))
+	for _, f := range c.flags {
+		names = append(names, "-"+f.name)
+	}
+
+	return names
+}
+
+// flagSpecs returns the specs of the flags defined on the flag set.
+func flagSpecs(flags *flag.FlagSet, values map[string][]string) []flagSpec {
+	var specs []flagSpec
+	flags.VisitAll(func(f *flag.Flag) {
+		boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool })
+		specs = append(specs, flagSpec{
+			name:   f.Name,
+			usage:  f.Usage,
+			isBool: ok && boolFlag.IsBoolFlag(),
+			values: values[f.Name],
+		})
+	})
+
+	return specs
+}
+
+// commandSpecs returns the specs of the vlcx commands.
+func commandSpecs() []commandSpec {
+	specs := make([]commandSpec, 0, len(commands))
+	for _, cmd := range commands {
+		flags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+		cmd.setup(flags, &env{})
+
+		specs = append(specs, commandSpec{
+			name:    cmd.name,
+			summary: cmd.summary,
+			flags:   flagSpecs(flags, cmd.complete.values),
+			args:    cmd.complete.args,
+			files:   cmd.complete.files,
+		})
+	}
+
+	return specs
+}
+
+// globalSpec returns the spec of the global flags. Its arguments are the
+// command names.
+func globalSpec() commandSpec {
+	flags := flag.NewFlagSet("vlcx", flag.ContinueOnError)
+	globalFlags(flags, &env{})
+
+	spec := commandSpec{
+		flags: flagSpecs(flags, map[string][]string{"v": {"0", "1", "2"}}),
+	}
+	for i := range spec.flags {
+		spec.flags[i].files = spec.flags[i].name == "log"
+	}
+	for _, cmd := range commands {
+		spec.args = append(spec.args, cmd.name)
+	}
+
+	return spec
+}
+
+func writeBashCompletion(w io.Writer, specs []commandSpec, global commandSpec) {
+	fmt.Fprint(w, `# bash completion for vlcx. Load it using:
+#   source <(vlcx completion bash)
+_vlcx() {
+	local cur=${COMP_WORDS[COMP_CWORD]} prev=${COMP_WORDS[COMP_CWORD-1]}
+	local cmd= i
+
+	# Find the command, skipping the global flags and their values.
+	for ((i = 1; i < COMP_CWORD; i++)); do
+		case ${COMP_WORDS[i]} in
+`)
+	if valueFlags := global.valueFlags(); len(valueFlags) > 0 {
+		fmt.Fprintf(w, "\t\t%s) ((i++)) ;;\n", strings.Join(valueFlags, "|"))
+	}
+	fmt.Fprint(w, `		-*) ;;
+		*) cmd=${COMP_WORDS[i]}; break ;;
+		esac
+	done
+
+	local flags= values= files=
+	case $cmd in
+`)
+	writeBashCase(w, `""`, global)
+	for _, spec := range specs {
+		writeBashCase(w, spec.name, spec)
+	}
+	fmt.Fprint(w, `	esac
+
+	if [[ $cur == -* ]]; then
+		COMPREPLY=($(compgen -W "$flags" -- "$cur"))
+		return
+	fi
+	COMPREPLY=($(compgen -W "$values" -- "$cur"))
+	if [[ -n $files ]]; then
+		COMPREPLY+=($(compgen -f -- "$cur"))
+	fi
+}
+complete -o filenames -F _vlcx vlcx
+`)
+}
+
+func writeBashCase(w io.Writer, pattern string, spec commandSpec) {
+	fmt.Fprintf(w, "\t%s)\n", pattern)
+
+	// Complete flag values.
+	var valueCases []string
+	for _, f := range spec.flags {
+		switch {
+		case f.isBool:
+			continue
+		case f.files:
+			valueCases = append(valueCases, fmt.Sprintf(`-%s) COMPREPLY=($(compgen -f -- "$cur")); return ;;`, f.name))
+		case len(f.values) > 0:
+			valueCases = append(valueCases, fmt.Sprintf(`-%s) COMPREPLY=($(compgen -W "%s" -- "$cur")); return ;;`,
+				f.name, strings.Join(f.values, " ")))
+		default:
+			valueCases = append(valueCases, fmt.Sprintf("-%s) return ;;", f.name))
+		}
+	}
+	if len(valueCases) > 0 {
+		fmt.Fprintln(w, "\t\tcase $prev in")
+		for _, valueCase := range valueCases {
+			fmt.Fprintf(w, "\t\t%s\n", valueCase)
+		}
+		fmt.Fprintln(w, "\t\tesac")
+	}
+
+	fmt.Fprintf(w, "\t\tflags=%q\n", strings.Join(spec.flagNames(), " "))
+	if len(spec.args) > 0 {
+		fmt.Fprintf(w, "\t\tvalues=%q\n", strings.Join(spec.args, " "))
+	}
+	if spec.files {
+		fmt.Fprintln(w, "\t\tfiles=1")
+	}
+	fmt.Fprintln(w, "\t\t;;")
+}
+
+func writeZshCompletion(w io.Writer, specs []commandSpec, global commandSpec) {
+	fmt.Fprint(w, `#compdef vlcx
+# zsh completion for vlcx. Load it using:
+#   source <(vlcx completion zsh)
+autoload -U +X bashcompinit && bashcompinit
+
+`)
+	writeBashCompletion(w, specs, global)
+}
+
+func writeFishCompletion(w io.Writer, specs []commandSpec, global commandSpec) {
+	fmt.Fprint(w, `# fish completion for vlcx. Load it using:
+#   vlcx completion fish | source
+complete -c vlcx -f
+`)
+
+	// Global flags and commands.
+	const noCommand = "__fish_use_subcommand"
+	writeFishFlags(w, noCommand, global.flags)
+	for _, spec := range specs {
+		fmt.Fprintf(w, "complete -c vlcx -n %s -a %s -d %s\n", noCommand, spec.name, fishQuote(spec.summary))
+	}
+
+	// Command flags and arguments.
+	for _, spec := range specs {
+		condition := fishQuote("__fish_seen_subcommand_from " + spec.name)
+		writeFishFlags(w, condition, spec.flags)
+		if len(spec.args) > 0 {
+			fmt.Fprintf(w, "complete -c vlcx -n %s -a %s\n", condition, fishQuote(strings.Join(spec.args, " ")))
+		}
+		if spec.files {
+			fmt.Fprintf(w, "complete -c vlcx -n %s -F\n", condition)
+		}
+	}
+}
+
+func writeFishFlags(w io.Writer, condition string, flags []flagSpec) {
+	for _, f := range flags {
+		fmt.Fprintf(w, "complete -c vlcx -n %s -o %s", condition, f.name)
+		switch {
+		case f.isBool:
+		case f.files:
+			fmt.Fprint(w, " -r -F")
+		case len(f.values) > 0:
+			fmt.Fprintf(w, " -x -a %s", fishQuote(strings.Join(f.values, " ")))
+		default:
+			fmt.Fprint(w, " -x")
+		}
+		fmt.Fprintf(w, " -d %s\n", fishQuote(f.usage))
+	}
+}
+
+// fishQuote quotes the provided value for use in fish scripts.
+func fishQuote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return `'` + value + `'`
+}
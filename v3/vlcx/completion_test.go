@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCompletion(t *testing.T) {
+	for _, shell := range shellNames() {
+		var stdout, stderr bytes.Buffer
+		if err := run(context.Background(), []string{"completion", shell}, &stdout, &stderr); err != nil {
+			t.Fatalf("%s: %v", shell, err)
+		}
+
+		script := stdout.String()
+		for _, spec := range commandSpecs() {
+			if !strings.Contains(script, spec.name) {
+				t.Errorf("%s: command %s not completed", shell, spec.name)
+			}
+			for _, f := range spec.flags {
+				if !strings.Contains(script, f.name) {
+					t.Errorf("%s: flag -%s of command %s not completed", shell, f.name, spec.name)
+				}
+			}
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := run(context.Background(), []string{"completion", "tcsh"}, &stdout, &stderr); err == nil {
+		t.Error("expected error for unsupported shell")
+	}
+}
+
+func TestCommandSpecs(t *testing.T) {
+	specs := map[string]commandSpec{}
+	for _, spec := range commandSpecs() {
+		specs[spec.name] = spec
+	}
+	if len(specs) != len(commands) {
+		t.Fatalf("got %d command specs, want %d", len(specs), len(commands))
+	}
+
+	var profile *flagSpec
+	for i, f := range specs["screen"].flags {
+		if f.name == "profile" {
+			profile = &specs["screen"].flags[i]
+		}
+		if f.name == "follow-mouse" && !f.isBool {
+			t.Error("expected -follow-mouse to be a boolean flag")
+		}
+	}
+	if profile == nil || profile.isBool || len(profile.values) == 0 {
+		t.Errorf("expected -profile to be completed with the profile names, got %+v", profile)
+	}
+
+	if !specs["play"].files || specs["discover"].files {
+		t.Error("expected files to be completed for play and not for discover")
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	"github.com/adrg/libvlc-go-examples/v3/mediatree"
+)
+
+var discoverCommand = &command{
+	name:    "discover",
+	args:    "[-list [-category <name>] | <service>]",
+	summary: "browse the media found by a media discovery service",
+	complete: completion{
+		values: map[string][]string{"category": discoveryCategories()},
+	},
+	setup: func(flags *flag.FlagSet, e *env) func(context.Context, []string) error {
+		var (
+			list         = flags.Bool("list", false, "list media discovery services and exit")
+			category     = flags.String("category", "", "list the services of the specified category only: "+mediatree.CategoryNames())
+			wait         = flags.Duration("wait", 3*time.Second, "duration for which top level items are collected")
+			depth        = flags.Int("depth", 2, "maximum depth of the tree (-1 for unlimited)")
+			timeout      = flags.Duration("timeout", 30*time.Second, "maximum duration of the tree expansion")
+			parseTimeout = flags.Duration("parse-timeout", 5*time.Second, "maximum duration for parsing an item")
+			jsonOutput   = flags.Bool("json", false, "print the tree in JSON format")
+		)
+
+		return func(ctx context.Context, args []string) error {
+			if *list {
+				return mediatree.ListServices(e.stdout, *category)
+			}
+			if len(args) != 1 {
+				return errUsage
+			}
+			serviceName := args[0]
+
+			// Discover media.
+			e.verbose("Discovering media using %s for %s\n", serviceName, *wait)
+			discoverCtx, cancel := context.WithTimeout(ctx, *wait)
+			items, release, err := mediatree.Discover(discoverCtx, serviceName)
+			cancel()
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			nodes := mediatree.Build(items, mediatree.Options{
+				MaxDepth:     *depth,
+				ParseTimeout: *parseTimeout,
+				Deadline:     time.Now().Add(*timeout),
+			})
+
+			if *jsonOutput {
+				return mediatree.WriteJSON(e.stdout, nodes)
+			}
+			if len(nodes) == 0 {
+				e.logger.Printf("No media found by %s within %s.\n", serviceName, *wait)
+				return nil
+			}
+			mediatree.Print(e.stdout, nodes)
+
+			return nil
+		}
+	},
+}
+
+func discoveryCategories() []string {
+	names := make([]string, 0, len(mediatree.Categories))
+	for _, c := range mediatree.Categories {
+		names = append(names, c.Name)
+	}
+
+	return names
+}
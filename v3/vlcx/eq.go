@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+
+	"github.com/adrg/libvlc-go-examples/v3/vlcutil"
+)
+
+var eqCommand = &command{
+	name:     "eq",
+	args:     "[-list | -preset <name> <path or URL>]",
+	summary:  "play media using an equalizer preset",
+	complete: completion{files: true},
+	setup: func(flags *flag.FlagSet, e *env) func(context.Context, []string) error {
+		list := flags.Bool("list", false, "list the equalizer presets and band frequencies and exit")
+		preset := flags.String("preset", "", "name of the equalizer preset (case insensitive)")
+		noVideo := flags.Bool("no-video", false, "disable video output")
+
+		return func(ctx context.Context, args []string) error {
+			if *list {
+				listEqualizer(e)
+				return nil
+			}
+			if *preset == "" || len(args) != 1 {
+				return errUsage
+			}
+
+			// Create a new player.
+			player, err := vlc.NewPlayer()
+			if err != nil {
+				return err
+			}
+			defer func() {
+				player.Stop()
+				player.Release()
+			}()
+
+			if err := vlcutil.ApplyPreset(player, *preset); err != nil {
+				return err
+			}
+
+			media, err := newMedia(args[0], *noVideo)
+			if err != nil {
+				return err
+			}
+			defer media.Release()
+
+			if err := player.SetMedia(media); err != nil {
+				return err
+			}
+
+			e.verbose("Playing %s using the %q equalizer preset\n", args[0], *preset)
+			return vlcutil.PlayMedia(ctx, player)
+		}
+	},
+}
+
+// listEqualizer writes the equalizer presets and band frequencies.
+func listEqualizer(e *env) {
+	fmt.Fprintln(e.stdout, "Presets:")
+	for i, name := range vlc.EqualizerPresetNames() {
+		fmt.Fprintf(e.stdout, "  #%d: %s\n", i, name)
+	}
+
+	fmt.Fprintln(e.stdout, "Band frequencies:")
+	for i, freq := range vlc.EqualizerBandFrequencies() {
+		fmt.Fprintf(e.stdout, "  #%d: %.2f Hz\n", i, freq)
+	}
+}
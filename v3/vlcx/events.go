@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+
+	"github.com/adrg/libvlc-go-examples/v3/vlcutil"
+)
+
+// playerEvents contains the player events logged by the events command,
+// along with their names.
+var playerEvents = []struct {
+	Event vlc.Event
+	Name  string
+}{
+	{vlc.MediaPlayerOpening, "opening"},
+	{vlc.MediaPlayerBuffering, "buffering"},
+	{vlc.MediaPlayerPlaying, "playing"},
+	{vlc.MediaPlayerPaused, "paused"},
+	{vlc.MediaPlayerStopped, "stopped"},
+	{vlc.MediaPlayerEndReached, "end reached"},
+	{vlc.MediaPlayerEncounteredError, "error"},
+	{vlc.MediaPlayerTimeChanged, "time changed"},
+	{vlc.MediaPlayerLengthChanged, "length changed"},
+	{vlc.MediaPlayerVout, "video output changed"},
+}
+
+var eventsCommand = &command{
+	name:     "events",
+	args:     "<path or URL>",
+	summary:  "play media and log the player events",
+	complete: completion{files: true},
+	setup: func(flags *flag.FlagSet, e *env) func(context.Context, []string) error {
+		stats := flags.Duration("stats", time.Second, "minimum interval between media statistics entries (0 disables them)")
+		noVideo := flags.Bool("no-video", false, "disable video output")
+
+		return func(ctx context.Context, args []string) error {
+			if len(args) != 1 {
+				return errUsage
+			}
+
+			// Create a new player.
+			player, err := vlc.NewPlayer()
+			if err != nil {
+				return err
+			}
+			defer func() {
+				player.Stop()
+				player.Release()
+			}()
+
+			media, err := newMedia(args[0], *noVideo)
+			if err != nil {
+				return err
+			}
+			defer media.Release()
+
+			if err := player.SetMedia(media); err != nil {
+				return err
+			}
+
+			// Retrieve player event manager.
+			manager, err := player.EventManager()
+			if err != nil {
+				return err
+			}
+
+			// Create event handler. The time changed events are frequent,
+			// so they are only logged along with the media statistics.
+			var lastStats time.Time
+			eventCallback := func(event vlc.Event, userData interface{}) {
+				name, _ := userData.(string)
+				if event != vlc.MediaPlayerTimeChanged {
+					e.logger.Println("Event:", name)
+					return
+				}
+				if *stats <= 0 || time.Since(lastStats) < *stats {
+					return
+				}
+				lastStats = time.Now()
+
+				mediaStats, err := media.Stats()
+				if err != nil {
+					e.logger.Println(err)
+					return
+				}
+				playerTime, _ := player.MediaTime()
+				e.logger.Printf("Time: %dms, stats: %+v\n", playerTime, mediaStats)
+			}
+
+			// Register events with the event manager.
+			var eventIDs []vlc.EventID
+			for _, playerEvent := range playerEvents {
+				eventID, err := manager.Attach(playerEvent.Event, eventCallback, playerEvent.Name)
+				if err != nil {
+					manager.Detach(eventIDs...)
+					return err
+				}
+				eventIDs = append(eventIDs, eventID)
+			}
+			defer manager.Detach(eventIDs...)
+
+			return vlcutil.PlayMedia(ctx, player)
+		}
+	},
+}
@@ -0,0 +1,283 @@
+package main
+
+/*
+ * Command line tool wrapping the libvlc-go examples.
+ *
+ * Usage:
+ *   vlcx [-v level] [-log file] [-vlc-arg arg]... <command> [flags] [args]
+ *
+ * Commands:
+ *   play        play media files or URLs, one after another
+ *   playlist    play media files or URLs using a list player
+ *   events      play media and log the player events
+ *   tracks      print the tracks of media files
+ *   info        print the metadata of media files
+ *   screen      record the screen to a file
+ *   cast        cast media to a network renderer
+ *   eq          play media using an equalizer preset
+ *   discover    browse the media found by a media discovery service
+ *   completion  print a shell completion script
+ *
+ * Run `vlcx <command> -h` for the flags of each command.
+ */
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+)
+
+// command is a vlcx subcommand.
+type command struct {
+	name    string
+	args    string
+	summary string
+
+	// setup defines the flags of the command on the provided flag set and
+	// returns the function which runs the command, using the arguments
+	// remaining after the flags are parsed.
+	setup func(flags *flag.FlagSet, env *env) func(ctx context.Context, args []string) error
+
+	// noVLC reports whether the command runs without initializing libVLC.
+	noVLC bool
+
+	// complete contains the values suggested by shell completion.
+	complete completion
+}
+
+// completion contains the values suggested by shell completion for the
+// flags and the arguments of a command.
+type completion struct {
+	// Suggested values of the flags, by flag name.
+	values map[string][]string
+
+	// Suggested values of the arguments.
+	args []string
+
+	// files reports whether files are suggested as arguments.
+	files bool
+}
+
+// commands contains the vlcx subcommands, in the order they are listed.
+// It is populated in init in order to avoid an initialization cycle with
+// the completion command, which describes the other commands.
+var commands []*command
+
+func init() {
+	commands = []*command{
+		playCommand,
+		playlistCommand,
+		eventsCommand,
+		tracksCommand,
+		infoCommand,
+		screenCommand,
+		castCommand,
+		eqCommand,
+		discoverCommand,
+		completionCommand,
+	}
+}
+
+func findCommand(name string) *command {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+
+	return nil
+}
+
+// env contains the settings shared by all commands.
+type env struct {
+	stdout io.Writer
+	logger *log.Logger
+
+	// Verbosity level: 0 (quiet), 1 (libVLC errors and warnings and
+	// progress messages) or 2 (libVLC debug messages).
+	verbosity int
+
+	// Path of the file the log messages are written to. If empty, the log
+	// messages are written to stderr.
+	logFile string
+
+	// Additional libVLC arguments.
+	vlcArgs []string
+}
+
+// verbose logs the specified message if the verbosity level is at least 1.
+func (e *env) verbose(format string, v ...interface{}) {
+	if e.verbosity > 0 {
+		e.logger.Printf(format, v...)
+	}
+}
+
+// initArgs returns the arguments libVLC is initialized with.
+func (e *env) initArgs() []string {
+	var args []string
+	switch {
+	case e.verbosity <= 0:
+		args = append(args, "--quiet")
+	default:
+		args = append(args, fmt.Sprintf("--verbose=%d", e.verbosity))
+	}
+	if e.logFile != "" {
+		args = append(args, "--file-logging", "--logfile="+e.logFile)
+	}
+
+	return append(args, e.vlcArgs...)
+}
+
+// stringsFlag is a flag which can be specified multiple times.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, " ")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// globalFlags defines the flags shared by all commands on the provided
+// flag set.
+func globalFlags(flags *flag.FlagSet, e *env) {
+	flags.IntVar(&e.verbosity, "v", 0, "verbosity level: 0 (quiet), 1 (warnings and progress) or 2 (debug)")
+	flags.StringVar(&e.logFile, "log", "", "write the log messages to the specified file instead of stderr")
+	flags.Var((*stringsFlag)(&e.vlcArgs), "vlc-arg", "additional libVLC argument (can be repeated)")
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: vlcx [global flags] <command> [flags] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-11s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Global flags:")
+
+	flags := flag.NewFlagSet("vlcx", flag.ContinueOnError)
+	flags.SetOutput(w)
+	globalFlags(flags, &env{})
+	flags.PrintDefaults()
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "vlcx <command> -h" for the flags of each command.`)
+}
+
+// errUsage is returned by run if the command line arguments are invalid.
+var errUsage = errors.New("invalid usage")
+
+// run parses the global flags and runs the specified command.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	e := &env{stdout: stdout}
+
+	flags := flag.NewFlagSet("vlcx", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	globalFlags(flags, e)
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			usage(stdout)
+			return nil
+		}
+		fmt.Fprintln(stderr, err)
+		usage(stderr)
+		return errUsage
+	}
+
+	// Set up logging.
+	logOutput := stderr
+	if e.logFile != "" {
+		f, err := os.OpenFile(e.logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		logOutput = f
+	}
+	e.logger = log.New(logOutput, "", log.LstdFlags)
+
+	// Find command.
+	if flags.NArg() == 0 {
+		usage(stderr)
+		return errUsage
+	}
+	name, args := flags.Arg(0), flags.Args()[1:]
+	if name == "help" {
+		usage(stdout)
+		return nil
+	}
+
+	cmd := findCommand(name)
+	if cmd == nil {
+		fmt.Fprintf(stderr, "Unknown command %q\n\n", name)
+		usage(stderr)
+		return errUsage
+	}
+
+	// Parse command flags.
+	cmdFlags := flag.NewFlagSet("vlcx "+cmd.name, flag.ContinueOnError)
+	cmdFlags.SetOutput(stderr)
+	cmdFlags.Usage = func() {
+		fmt.Fprintf(cmdFlags.Output(), "Usage: vlcx %s [flags] %s\n\n%s.\n\nFlags:\n",
+			cmd.name, cmd.args, capitalize(cmd.summary))
+		cmdFlags.PrintDefaults()
+	}
+	runCmd := cmd.setup(cmdFlags, e)
+	if err := cmdFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return errUsage
+	}
+
+	// Initialize libVLC.
+	if !cmd.noVLC {
+		if err := vlc.Init(e.initArgs()...); err != nil {
+			return err
+		}
+		defer vlc.Release()
+	}
+
+	err := runCmd(ctx, cmdFlags.Args())
+	if errors.Is(err, errUsage) {
+		cmdFlags.Usage()
+	}
+
+	return err
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func main() {
+	// Stop the running command on SIGINT and SIGTERM.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+
+	switch {
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "vlcx:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRunUsage(t *testing.T) {
+	tests := []struct {
+		args []string
+		err  error
+	}{
+		{nil, errUsage},
+		{[]string{"unknown"}, errUsage},
+		{[]string{"-unknown", "play"}, errUsage},
+		{[]string{"completion"}, errUsage},
+		{[]string{"help"}, nil},
+		{[]string{"-h"}, nil},
+		{[]string{"play", "-h"}, nil},
+	}
+	for _, test := range tests {
+		var stdout, stderr bytes.Buffer
+		if err := run(context.Background(), test.args, &stdout, &stderr); !errors.Is(err, test.err) {
+			t.Errorf("%q: got error %v, want %v", test.args, err, test.err)
+		}
+		if output := stdout.String() + stderr.String(); !strings.Contains(output, "Usage: vlcx") {
+			t.Errorf("%q: usage not printed:\n%s", test.args, output)
+		}
+	}
+}
+
+func TestRunLogFile(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "vlcx.log")
+
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), []string{"-log", logFile, "completion", "bash"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(logFile); err != nil {
+		t.Errorf("log file not created: %v", err)
+	}
+	if stdout.Len() == 0 {
+		t.Error("completion script not written to stdout")
+	}
+}
+
+func TestInitArgs(t *testing.T) {
+	tests := []struct {
+		env  env
+		args []string
+	}{
+		{env{}, []string{"--quiet"}},
+		{env{verbosity: 2}, []string{"--verbose=2"}},
+		{
+			env{verbosity: 1, logFile: "vlcx.log", vlcArgs: []string{"--no-video"}},
+			[]string{"--verbose=1", "--file-logging", "--logfile=vlcx.log", "--no-video"},
+		},
+	}
+	for _, test := range tests {
+		if args := test.env.initArgs(); !reflect.DeepEqual(args, test.args) {
+			t.Errorf("got init args %q, want %q", args, test.args)
+		}
+	}
+}
+
+func TestIsURL(t *testing.T) {
+	tests := map[string]bool{
+		"http://example.com/stream.mp3": true,
+		"file:///music/song.mp3":        true,
+		"/music/song.mp3":               false,
+		"song.mp3":                      false,
+		"dir/with://colon.mp3":          false,
+		"://missing-scheme":             false,
+	}
+	for value, want := range tests {
+		if got := isURL(value); got != want {
+			t.Errorf("isURL(%q): got %t, want %t", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+
+	"github.com/adrg/libvlc-go-examples/v3/medialib"
+	"github.com/adrg/libvlc-go-examples/v3/vlcutil"
+)
+
+var tracksCommand = &command{
+	name:     "tracks",
+	args:     "<path or URL>...",
+	summary:  "print the tracks of media files",
+	complete: completion{files: true},
+	setup: func(flags *flag.FlagSet, e *env) func(context.Context, []string) error {
+		timeout := flags.Duration("timeout", 10*time.Second, "maximum duration of the media parsing")
+		network := flags.Bool("network", false, "parse network resources as well")
+
+		return func(ctx context.Context, args []string) error {
+			if len(args) == 0 {
+				return errUsage
+			}
+
+			opts := []vlc.MediaParseOption{vlc.MediaParseLocal}
+			if *network {
+				opts = append(opts, vlc.MediaParseNetwork)
+			}
+
+			for i, arg := range args {
+				if ctx.Err() != nil {
+					return nil
+				}
+				if len(args) > 1 {
+					if i > 0 {
+						fmt.Fprintln(e.stdout)
+					}
+					fmt.Fprintf(e.stdout, "%s:\n", arg)
+				}
+
+				if err := printMediaTracks(e, arg, *timeout, opts); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+	},
+}
+
+func printMediaTracks(e *env, pathOrURL string, timeout time.Duration, opts []vlc.MediaParseOption) error {
+	media, err := newMedia(pathOrURL, false)
+	if err != nil {
+		return err
+	}
+	defer media.Release()
+
+	e.verbose("Parsing %s\n", pathOrURL)
+	if err := vlcutil.ParseMedia(media, timeout, opts...); err != nil {
+		return fmt.Errorf("cannot parse %s: %w", pathOrURL, err)
+	}
+
+	return vlcutil.WriteTracks(e.stdout, media)
+}
+
+var infoCommand = &command{
+	name:     "info",
+	args:     "<path>...",
+	summary:  "print the metadata of media files",
+	complete: completion{files: true},
+	setup: func(flags *flag.FlagSet, e *env) func(context.Context, []string) error {
+		timeout := flags.Duration("timeout", 5*time.Second, "maximum duration of the media parsing")
+		jsonOutput := flags.Bool("json", false, "print the information in JSON format")
+
+		return func(ctx context.Context, args []string) error {
+			if len(args) == 0 {
+				return errUsage
+			}
+
+			var entries []*medialib.Entry
+			for _, arg := range args {
+				if ctx.Err() != nil {
+					return nil
+				}
+
+				path, err := filepath.Abs(arg)
+				if err != nil {
+					return err
+				}
+
+				fi, err := os.Stat(path)
+				if err != nil {
+					return err
+				}
+
+				e.verbose("Parsing %s\n", path)
+				entry, err := medialib.ParseFile(path, *timeout)
+				if err != nil {
+					return err
+				}
+				entry.Size, entry.ModTime = fi.Size(), fi.ModTime()
+				entries = append(entries, entry)
+			}
+
+			if *jsonOutput {
+				return writeEntriesJSON(e, entries)
+			}
+
+			for i, entry := range entries {
+				if i > 0 {
+					fmt.Fprintln(e.stdout)
+				}
+				if err := printEntry(e, entry); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+	},
+}
+
+// writeEntriesJSON writes the specified media library entries in JSON
+// format. The entries are not part of a library, so the indexing time
+// is omitted.
+func writeEntriesJSON(e *env, entries []*medialib.Entry) error {
+	type mediaInfo struct {
+		*medialib.Entry
+		IndexedAt *time.Time `json:"indexed_at,omitempty"`
+	}
+
+	infos := make([]mediaInfo, 0, len(entries))
+	for _, entry := range entries {
+		infos = append(infos, mediaInfo{Entry: entry})
+	}
+
+	encoder := json.NewEncoder(e.stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(infos)
+}
+
+// printEntry writes the metadata and the tracks of the specified media
+// library entry as a table.
+func printEntry(e *env, entry *medialib.Entry) error {
+	tw := tabwriter.NewWriter(e.stdout, 0, 0, 2, ' ', 0)
+	for _, field := range []struct {
+		name  string
+		value string
+	}{
+		{"Path", entry.Path},
+		{"Title", entry.Title},
+		{"Artist", entry.Artist},
+		{"Album artist", entry.AlbumArtist},
+		{"Album", entry.Album},
+		{"Track number", entry.TrackNumber},
+		{"Genre", entry.Genre},
+		{"Date", entry.Date},
+		{"Duration", entry.Duration.Round(time.Millisecond).String()},
+	} {
+		if field.value != "" {
+			fmt.Fprintf(tw, "%s:\t%s\n", field.name, field.value)
+		}
+	}
+
+	for _, track := range entry.Tracks {
+		details := []string{track.Codec}
+		switch {
+		case track.Width > 0:
+			details = append(details, fmt.Sprintf("%dx%d", track.Width, track.Height))
+			if track.FrameRate > 0 {
+				details = append(details, fmt.Sprintf("%.2f fps", track.FrameRate))
+			}
+		case track.Rate > 0:
+			details = append(details, fmt.Sprintf("%d Hz", track.Rate), fmt.Sprintf("%d channels", track.Channels))
+		}
+		if track.Language != "" {
+			details = append(details, track.Language)
+		}
+
+		fmt.Fprintf(tw, "Track #%d (%s):\t%s\n", track.ID, track.Type, strings.Join(details, ", "))
+	}
+
+	return tw.Flush()
+}
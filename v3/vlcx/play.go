@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"strings"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+
+	"github.com/adrg/libvlc-go-examples/v3/vlcutil"
+)
+
+var playCommand = &command{
+	name:     "play",
+	args:     "<path or URL>...",
+	summary:  "play media files or URLs, one after another",
+	complete: completion{files: true},
+	setup: func(flags *flag.FlagSet, e *env) func(context.Context, []string) error {
+		noVideo := flags.Bool("no-video", false, "disable video output")
+
+		return func(ctx context.Context, args []string) error {
+			if len(args) == 0 {
+				return errUsage
+			}
+
+			// Create a new player.
+			player, err := vlc.NewPlayer()
+			if err != nil {
+				return err
+			}
+			defer func() {
+				player.Stop()
+				player.Release()
+			}()
+
+			for _, arg := range args {
+				if ctx.Err() != nil {
+					return nil
+				}
+
+				media, err := newMedia(arg, *noVideo)
+				if err != nil {
+					return err
+				}
+
+				e.verbose("Playing %s\n", arg)
+				if err := player.SetMedia(media); err != nil {
+					media.Release()
+					return err
+				}
+				err = vlcutil.PlayMedia(ctx, player)
+				media.Release()
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+	},
+}
+
+var playlistCommand = &command{
+	name:     "playlist",
+	args:     "<path or URL>...",
+	summary:  "play media files or URLs using a list player",
+	complete: completion{files: true},
+	setup: func(flags *flag.FlagSet, e *env) func(context.Context, []string) error {
+		noVideo := flags.Bool("no-video", false, "disable video output")
+		loop := flags.Bool("loop", false, "play the list repeatedly, until interrupted")
+		repeat := flags.Bool("repeat", false, "repeat the first item of the list, until interrupted")
+
+		return func(ctx context.Context, args []string) error {
+			if len(args) == 0 {
+				return errUsage
+			}
+
+			// Create a new list player.
+			player, err := vlc.NewListPlayer()
+			if err != nil {
+				return err
+			}
+			defer func() {
+				player.Stop()
+				player.Release()
+			}()
+
+			// Create a new media list.
+			list, err := vlc.NewMediaList()
+			if err != nil {
+				return err
+			}
+			defer list.Release()
+
+			for _, arg := range args {
+				media, err := newMedia(arg, *noVideo)
+				if err != nil {
+					return err
+				}
+				err = list.AddMedia(media)
+				media.Release()
+				if err != nil {
+					return err
+				}
+			}
+
+			// Set player media list.
+			if err = player.SetMediaList(list); err != nil {
+				return err
+			}
+
+			mode := vlc.Default
+			switch {
+			case *loop:
+				mode = vlc.Loop
+			case *repeat:
+				mode = vlc.Repeat
+			}
+			if err := player.SetPlaybackMode(mode); err != nil {
+				return err
+			}
+
+			e.verbose("Playing %d media files\n", len(args))
+			return vlcutil.PlayMediaList(ctx, player)
+		}
+	},
+}
+
+// newMedia creates a new media instance from the specified path or URL.
+// Arguments containing a scheme separator (e.g. http://) are treated as URLs.
+func newMedia(pathOrURL string, noVideo bool) (*vlc.Media, error) {
+	newFunc := vlc.NewMediaFromPath
+	if isURL(pathOrURL) {
+		newFunc = vlc.NewMediaFromURL
+	}
+
+	media, err := newFunc(pathOrURL)
+	if err != nil {
+		return nil, err
+	}
+	if noVideo {
+		if err := media.AddOptions(":no-video"); err != nil {
+			media.Release()
+			return nil, err
+		}
+	}
+
+	return media, nil
+}
+
+func isURL(pathOrURL string) bool {
+	scheme, _, ok := strings.Cut(pathOrURL, "://")
+	return ok && scheme != "" && !strings.ContainsAny(scheme, `/\`)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	vlc "github.com/adrg/libvlc-go/v3"
+
+	"github.com/adrg/libvlc-go-examples/shared/screencap"
+	"github.com/adrg/libvlc-go-examples/v3/vlcutil"
+)
+
+var screenCommand = &command{
+	name:    "screen",
+	args:    "<output file>",
+	summary: "record the screen to a file",
+	complete: completion{
+		values: map[string][]string{"profile": vlcutil.RecordProfileNames()},
+		files:  true,
+	},
+	setup: func(flags *flag.FlagSet, e *env) func(context.Context, []string) error {
+		var (
+			region      = flags.String("region", "", "captured region as WIDTHxHEIGHT+X+Y (default: entire screen)")
+			fps         = flags.Float64("fps", 30, "capture frame rate")
+			followMouse = flags.Bool("follow-mouse", false, "captured region follows the mouse cursor")
+			profileName = flags.String("profile", vlcutil.DefaultRecordProfile,
+				"output profile: "+strings.Join(vlcutil.RecordProfileNames(), ", "))
+			duration = flags.Duration("duration", 0, "recording duration (default: until interrupted)")
+		)
+
+		return func(ctx context.Context, args []string) error {
+			if len(args) != 1 {
+				return errUsage
+			}
+
+			profile, ok := vlcutil.RecordProfiles[*profileName]
+			if !ok {
+				return fmt.Errorf("unknown profile %q. Available profiles: %s",
+					*profileName, strings.Join(vlcutil.RecordProfileNames(), ", "))
+			}
+
+			output := args[0]
+			if filepath.Ext(output) == "" {
+				output += "." + profile.Extension
+			}
+
+			opts := vlcutil.RecordOptions{
+				Output:  output,
+				Profile: *profileName,
+				Screen: vlc.MediaScreenOptions{
+					FPS:         *fps,
+					FollowMouse: *followMouse,
+				},
+			}
+			if *region != "" {
+				area, err := screencap.ParseRegion(*region)
+				if err != nil {
+					return err
+				}
+				opts.Screen.X, opts.Screen.Y = area.X, area.Y
+				opts.Screen.Width, opts.Screen.Height = area.Width, area.Height
+			}
+
+			if *duration > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, *duration)
+				defer cancel()
+			}
+
+			start := time.Now()
+			e.logger.Printf("Recording to %s. Press Ctrl+C to stop.\n", output)
+			if err := vlcutil.RecordScreen(ctx, opts); err != nil {
+				return fmt.Errorf("recording failed: %w", err)
+			}
+
+			info, err := os.Stat(output)
+			if err != nil {
+				return fmt.Errorf("recording failed: %w", err)
+			}
+			e.logger.Printf("Saved %s (%d bytes, %s).\n", output, info.Size(), time.Since(start).Truncate(time.Second))
+
+			return nil
+		}
+	},
+}